@@ -17,12 +17,20 @@ type BannersWorker struct {
 	interval  time.Duration
 	cfg       longterm.UpdateAllConfig
 
+	leader Leader
+
 	ctx    context.Context
 	cancel func()
 	wg     sync.WaitGroup
 }
 
-func NewBannersWorker(logger logger.Logger, updateAllFunc UpdateAllFunc, interval time.Duration, cfg longterm.UpdateAllConfig) *BannersWorker {
+// Leader reports, if replica should run scheduled work
+type Leader interface {
+	IsLeader() bool
+}
+
+// leader can be nil, then worker runs on every tick
+func NewBannersWorker(logger logger.Logger, updateAllFunc UpdateAllFunc, interval time.Duration, cfg longterm.UpdateAllConfig, leader Leader) *BannersWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &BannersWorker{
@@ -30,6 +38,7 @@ func NewBannersWorker(logger logger.Logger, updateAllFunc UpdateAllFunc, interva
 		interval:  interval,
 		updateAll: updateAllFunc,
 		cfg:       cfg,
+		leader:    leader,
 		ctx:       ctx,
 		cancel:    cancel,
 		wg:        sync.WaitGroup{},
@@ -72,6 +81,11 @@ func (w *BannersWorker) run() {
 		case <-w.ctx.Done():
 			return
 		case <-ticker.C:
+			// other replica runs scheduled work
+			if w.leader != nil && !w.leader.IsLeader() {
+				w.logger.Debug("not a leader, skipping refreshing")
+				continue
+			}
 			w.logger.Info("starting refreshing")
 			ctx, cancel := context.WithCancel(w.ctx)
 			go func() {
@@ -17,12 +17,20 @@ type StatsWorker struct {
 	logger     logger.Logger
 	cfg        userstats.WorkerConfig
 
+	leader Leader
+
 	ctx    context.Context
 	cancel func()
 	wg     sync.WaitGroup
 }
 
-func NewStatsWorker(refreshAll RefreshAllFunc, interval time.Duration, logger logger.Logger, cfg userstats.WorkerConfig) *StatsWorker {
+// Leader reports, if replica should run scheduled work
+type Leader interface {
+	IsLeader() bool
+}
+
+// leader can be nil, then worker runs on every tick
+func NewStatsWorker(refreshAll RefreshAllFunc, interval time.Duration, logger logger.Logger, cfg userstats.WorkerConfig, leader Leader) *StatsWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &StatsWorker{
@@ -30,6 +38,7 @@ func NewStatsWorker(refreshAll RefreshAllFunc, interval time.Duration, logger lo
 		interval:   interval,
 		logger:     logger.With("service", "stats-updater-worker"),
 		cfg:        cfg,
+		leader:     leader,
 		ctx:        ctx,
 		cancel:     cancel,
 		wg:         sync.WaitGroup{},
@@ -73,6 +82,11 @@ func (w *StatsWorker) run() {
 		case <-w.ctx.Done():
 			return
 		case <-ticker.C:
+			// other replica runs scheduled work
+			if w.leader != nil && !w.leader.IsLeader() {
+				w.logger.Debug("not a leader, skipping refreshing")
+				continue
+			}
 			w.logger.Info("starting refreshing")
 			ctx, cancel := context.WithCancel(w.ctx)
 			go func() {
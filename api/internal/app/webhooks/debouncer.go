@@ -0,0 +1,156 @@
+package webhooks_worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	longterm "github.com/hurtki/github-banners/api/internal/domain/long-term"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+type RefreshOwnerFunc func(ctx context.Context, owner string) ([]longterm.Result, error)
+
+// refreshTimeout limits single refresh of owner's banners
+const refreshTimeout = time.Minute
+
+// RefreshDebouncer collapses bursts of github events into one refresh per owner:
+// the first event schedules refresh after delay, events, that come before it starts, are joined to it
+// events, that come during refresh, schedule one trailing refresh after it ends, so their changes aren't lost
+// and refreshes of the same owner never overlap
+type RefreshDebouncer struct {
+	logger  logger.Logger
+	refresh RefreshOwnerFunc
+	delay   time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*ownerRefresh
+	closed  bool
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+// ownerRefresh is a state of owner's refresh, it's kept in pending map until refresh ends
+type ownerRefresh struct {
+	// timer of scheduled refresh, nil while refresh is running
+	timer *time.Timer
+	// trailing is set by event during running refresh
+	trailing bool
+}
+
+func NewRefreshDebouncer(logger logger.Logger, refreshFunc RefreshOwnerFunc, delay time.Duration) *RefreshDebouncer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &RefreshDebouncer{
+		logger:  logger.With("service", "webhooks-debouncer"),
+		refresh: refreshFunc,
+		delay:   delay,
+		pending: make(map[string]*ownerRefresh),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Push schedules refresh of owner, it returns false, if refresh was already scheduled
+func (d *RefreshDebouncer) Push(owner string) bool {
+	key := domain.NormalizeGithubUsername(owner)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return false
+	}
+
+	state, ok := d.pending[key]
+	if !ok {
+		state = &ownerRefresh{}
+		d.pending[key] = state
+		d.schedule(key, state)
+		return true
+	}
+	// running refresh could have fetched data before the event
+	if state.timer == nil && !state.trailing {
+		state.trailing = true
+		return true
+	}
+	return false
+}
+
+// schedule starts timer of owner's refresh, d.mu should be held
+func (d *RefreshDebouncer) schedule(key string, state *ownerRefresh) {
+	d.wg.Add(1)
+	state.timer = time.AfterFunc(d.delay, func() {
+		defer d.wg.Done()
+
+		d.mu.Lock()
+		state.timer = nil
+		d.mu.Unlock()
+
+		d.run(key)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if state.trailing && !d.closed {
+			state.trailing = false
+			d.schedule(key, state)
+			return
+		}
+		delete(d.pending, key)
+	})
+}
+
+func (d *RefreshDebouncer) run(owner string) {
+	// timer could fire right before closing
+	if d.ctx.Err() != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(d.ctx, refreshTimeout)
+	defer cancel()
+
+	start := time.Now()
+	results, err := d.refresh(ctx, owner)
+	if err != nil {
+		d.logger.Error("can't refresh owner's banners", "owner", owner, "err", err)
+		return
+	}
+
+	errors := 0
+	for _, res := range results {
+		if res.Err != nil {
+			errors++
+			d.logger.Error("can't update", "username", res.Meta.Username, "type", res.Meta.BannerType, "url-path", res.Meta.UrlPath, "err", res.Err)
+		}
+	}
+	d.logger.Info("refreshed owner's banners", "owner", owner, "banners", len(results), "errors", errors, "duration", time.Since(start).String())
+}
+
+// Close drops scheduled refreshes and waits for running ones, that are cancelled
+func (d *RefreshDebouncer) Close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	for key, state := range d.pending {
+		if state.timer != nil && state.timer.Stop() {
+			d.wg.Done()
+		}
+		delete(d.pending, key)
+	}
+	d.mu.Unlock()
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		d.logger.Warn("couldn't shutdown in time, exiting", "ctxErr", ctx.Err())
+		return ctx.Err()
+	case <-done:
+		d.logger.Info("successfully shutted down")
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package webhooks_worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	longterm "github.com/hurtki/github-banners/api/internal/domain/long-term"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type refreshRecorder struct {
+	mu     sync.Mutex
+	owners []string
+}
+
+func (r *refreshRecorder) refresh(ctx context.Context, owner string) ([]longterm.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.owners = append(r.owners, owner)
+	return nil, nil
+}
+
+func (r *refreshRecorder) calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.owners...)
+}
+
+func TestRefreshDebouncerCollapsesBursts(t *testing.T) {
+	rec := &refreshRecorder{}
+	d := NewRefreshDebouncer(logger.NewLogger("error", "json"), rec.refresh, 20*time.Millisecond)
+
+	require.True(t, d.Push("hurtki"))
+	require.False(t, d.Push("HURTKI"))
+	require.False(t, d.Push("hurtki"))
+	require.True(t, d.Push("github.example.com:hurtki"))
+
+	require.Eventually(t, func() bool { return len(rec.calls()) == 2 }, time.Second, 5*time.Millisecond)
+	require.ElementsMatch(t, []string{"hurtki", "github.example.com:hurtki"}, rec.calls())
+
+	// event after refresh schedules the next one
+	require.True(t, d.Push("hurtki"))
+	require.Eventually(t, func() bool { return len(rec.calls()) == 3 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, d.Close(context.Background()))
+}
+
+func TestRefreshDebouncerCloseDropsScheduled(t *testing.T) {
+	rec := &refreshRecorder{}
+	d := NewRefreshDebouncer(logger.NewLogger("error", "json"), rec.refresh, time.Hour)
+
+	require.True(t, d.Push("hurtki"))
+	require.NoError(t, d.Close(context.Background()))
+	require.False(t, d.Push("hurtki"))
+	require.Empty(t, rec.calls())
+}
+
+// blockingRefresher blocks every refresh until release is closed and counts overlapping ones
+type blockingRefresher struct {
+	started chan string
+	release chan struct{}
+
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+	calls      int
+}
+
+func (r *blockingRefresher) refresh(ctx context.Context, owner string) ([]longterm.Result, error) {
+	r.mu.Lock()
+	r.running++
+	r.calls++
+	r.maxRunning = max(r.maxRunning, r.running)
+	r.mu.Unlock()
+
+	r.started <- owner
+	<-r.release
+
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+	return nil, nil
+}
+
+func (r *blockingRefresher) stats() (calls int, maxRunning int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls, r.maxRunning
+}
+
+func TestRefreshDebouncerPushDuringRefresh(t *testing.T) {
+	rec := &blockingRefresher{started: make(chan string, 2), release: make(chan struct{})}
+	d := NewRefreshDebouncer(logger.NewLogger("error", "json"), rec.refresh, 10*time.Millisecond)
+
+	require.True(t, d.Push("hurtki"))
+	select {
+	case <-rec.started:
+	case <-time.After(time.Second):
+		t.Fatal("refresh wasn't started")
+	}
+
+	// events during refresh schedule only one trailing refresh
+	require.True(t, d.Push("hurtki"))
+	require.False(t, d.Push("hurtki"))
+
+	// trailing refresh doesn't start, while the first one is running
+	time.Sleep(50 * time.Millisecond)
+	calls, _ := rec.stats()
+	require.Equal(t, 1, calls)
+
+	close(rec.release)
+	select {
+	case <-rec.started:
+	case <-time.After(time.Second):
+		t.Fatal("trailing refresh wasn't started")
+	}
+	require.NoError(t, d.Close(context.Background()))
+
+	calls, maxRunning := rec.stats()
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, maxRunning)
+}
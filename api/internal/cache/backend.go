@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain/preview"
+	userstats "github.com/hurtki/github-banners/api/internal/domain/user_stats"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// Backend is a storage of stats and previews caches
+type Backend string
+
+const (
+	BackendMemory Backend = "memory"
+	// redis compatible server, shared by replicas
+	BackendRemote Backend = "remote"
+	// memory cache in front of remote one
+	BackendLayered Backend = "layered"
+)
+
+var Backends = map[string]Backend{
+	"memory":  BackendMemory,
+	"remote":  BackendRemote,
+	"layered": BackendLayered,
+}
+
+// ParseBackend returns BackendMemory for blank backend
+func ParseBackend(v string) (Backend, bool) {
+	if v == "" {
+		return BackendMemory, true
+	}
+	b, ok := Backends[v]
+	return b, ok
+}
+
+// Factory creates caches of the backend, client is used only by remote and layered backends
+type Factory struct {
+	backend Backend
+	client  *resp.Client
+	// ttl of memory caches
+	ttl time.Duration
+	// ttl of memory layer of layered stats caches, it's short
+	// because invalidation on other replica drops only its own memory layer and remote cache
+	memoryTTL time.Duration
+	timeout   time.Duration
+	logger    logger.Logger
+}
+
+func NewFactory(backend Backend, client *resp.Client, ttl time.Duration, memoryTTL time.Duration, timeout time.Duration, logger logger.Logger) *Factory {
+	return &Factory{
+		backend:   backend,
+		client:    client,
+		ttl:       ttl,
+		memoryTTL: memoryTTL,
+		timeout:   timeout,
+		logger:    logger,
+	}
+}
+
+// Stats creates stats cache, remote keys are prefixed with prefix
+func (f *Factory) Stats(prefix string) userstats.Cache {
+	switch f.backend {
+	case BackendRemote:
+		return NewStatsRemoteCache(f.client, prefix, f.timeout, f.logger)
+	case BackendLayered:
+		return NewStatsLayeredCache(NewStatsMemoryCache(f.memoryTTL), NewStatsRemoteCache(f.client, prefix, f.timeout, f.logger), f.memoryTTL)
+	}
+	return NewStatsMemoryCache(f.ttl)
+}
+
+// Preview creates previews cache, its memory layer keeps ttl,
+// because hash of the preview covers stats, so it's never stale
+func (f *Factory) Preview() preview.Cache {
+	switch f.backend {
+	case BackendRemote:
+		return NewPreviewRemoteCache(f.client, f.ttl, f.timeout, f.logger)
+	case BackendLayered:
+		return NewPreviewLayeredCache(NewPreviewMemoryCache(f.ttl), NewPreviewRemoteCache(f.client, f.ttl, f.timeout, f.logger))
+	}
+	return NewPreviewMemoryCache(f.ttl)
+}
@@ -39,8 +39,25 @@ func (c *bannerInfoHashCounter) Hash(b domain.BannerInfo) string {
 	h.WriteString(b.Username)
 	h.Write([]byte{0})
 
+	// Kind
+	h.WriteString(string(b.Kind))
+	h.Write([]byte{0})
+
 	// BannerType
-	writeInt(h, int(b.BannerType))
+	h.WriteString(string(b.BannerType))
+	h.Write([]byte{0})
+
+	// Layout
+	h.WriteString(string(b.Layout))
+	h.Write([]byte{0})
+
+	// Motion
+	h.WriteString(string(b.Motion))
+	h.Write([]byte{0})
+
+	// Format
+	h.WriteString(string(b.Format))
+	h.Write([]byte{0})
 
 	// Stats
 	writeInt(h, b.Stats.TotalRepos)
@@ -48,6 +65,36 @@ func (c *bannerInfoHashCounter) Hash(b domain.BannerInfo) string {
 	writeInt(h, b.Stats.ForkedRepos)
 	writeInt(h, b.Stats.TotalStars)
 	writeInt(h, b.Stats.TotalForks)
+	writeInt(h, b.Stats.Members)
+
+	// Contributions
+	if c := b.Stats.Contributions; c != nil {
+		h.Write([]byte{1})
+		writeInt(h, c.Total)
+		writeInt(h, c.Commits)
+		writeInt(h, c.PullRequests)
+		writeInt(h, c.Issues)
+		writeInt(h, c.Reviews)
+		writeInt(h, c.CurrentStreak)
+		writeInt(h, c.LongestStreak)
+	} else {
+		h.Write([]byte{0})
+	}
+
+	// Repository
+	if r := b.Stats.Repository; r != nil {
+		h.Write([]byte{1})
+		writeInt(h, r.OpenIssues)
+		writeOptionalString(h, r.License)
+		writeOptionalString(h, r.LatestRelease)
+		if r.PushedAt != nil {
+			writeInt(h, int(r.PushedAt.Unix()))
+		} else {
+			h.Write([]byte{0})
+		}
+	} else {
+		h.Write([]byte{0})
+	}
 
 	// Languages (sorted for determinism)
 	if len(b.Stats.Languages) > 0 {
@@ -83,3 +130,14 @@ func writeInt(h *xxhash.Digest, v int) {
 	binary.LittleEndian.PutUint64(buf[:], uint64(v))
 	h.Write(buf[:])
 }
+
+// writeOptionalString writes nil and blank strings differently
+func writeOptionalString(h *xxhash.Digest, v *string) {
+	if v == nil {
+		h.Write([]byte{0})
+		return
+	}
+	h.Write([]byte{1})
+	h.WriteString(*v)
+	h.Write([]byte{0})
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	userstats "github.com/hurtki/github-banners/api/internal/domain/user_stats"
+)
+
+// StatsLayeredCache reads memory cache first and remote one on its miss
+// memory entries live at most memoryTTL, so stats, that other replica refreshed or dropped, are read from remote cache soon
+// Delete drops only memory layer of this replica, so memoryTTL should be a few seconds
+type StatsLayeredCache struct {
+	memory    *StatsMemoryCache
+	remote    *StatsRemoteCache
+	memoryTTL time.Duration
+}
+
+func NewStatsLayeredCache(memory *StatsMemoryCache, remote *StatsRemoteCache, memoryTTL time.Duration) *StatsLayeredCache {
+	return &StatsLayeredCache{
+		memory:    memory,
+		remote:    remote,
+		memoryTTL: memoryTTL,
+	}
+}
+
+func (c *StatsLayeredCache) Get(username string) (*userstats.CachedStats, bool) {
+	if stats, found := c.memory.Get(username); found {
+		return stats, true
+	}
+	stats, found := c.remote.Get(username)
+	if found {
+		c.memory.Set(username, stats, c.memoryTTL)
+	}
+	return stats, found
+}
+
+func (c *StatsLayeredCache) Set(username string, entry *userstats.CachedStats, ttl time.Duration) {
+	c.memory.Set(username, entry, min(ttl, c.memoryTTL))
+	c.remote.Set(username, entry, ttl)
+}
+
+func (c *StatsLayeredCache) Delete(username string) {
+	c.memory.Delete(username)
+	c.remote.Delete(username)
+}
+
+// PreviewLayeredCache reads rendered banners from memory cache first and from remote one on its miss
+// both caches count the same hash of domain.BannerInfo
+type PreviewLayeredCache struct {
+	memory *PreviewMemoryCache
+	remote *PreviewRemoteCache
+}
+
+func NewPreviewLayeredCache(memory *PreviewMemoryCache, remote *PreviewRemoteCache) *PreviewLayeredCache {
+	return &PreviewLayeredCache{
+		memory: memory,
+		remote: remote,
+	}
+}
+
+func (c *PreviewLayeredCache) Get(bf domain.BannerInfo) (*domain.Banner, string, bool) {
+	banner, hashKey, found := c.memory.Get(bf)
+	if found {
+		return banner, hashKey, true
+	}
+	banner, found = c.remote.get(hashKey)
+	if found {
+		c.memory.Set(hashKey, banner)
+	}
+	return banner, hashKey, found
+}
+
+// Set sets rendered banner to both caches, banner pointer shouldn't be nil, otherwise panic
+func (c *PreviewLayeredCache) Set(hashKey string, banner *domain.Banner) {
+	c.memory.Set(hashKey, banner)
+	c.remote.Set(hashKey, banner)
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/patrickmn/go-cache"
+)
+
+// MissingRepo persists negative cache, so it's shared by replicas and survives restarts
+type MissingRepo interface {
+	SaveMissing(ctx context.Context, missing domain.MissingGithubEntity) error
+	DeleteMissing(ctx context.Context, username string) error
+	IsMissing(ctx context.Context, kind domain.BannerKind, username string) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+// NegativeCache remembers users, organizations and repositories, that github doesn't have, for ttl
+// it's kept in memory, if repo is nil, otherwise only in repo, so clearing on one replica is seen by others
+// it's checked only after miss of stats cache, that is followed by database query anyway
+// errors of repo are logged, username is fetched from github then
+type NegativeCache struct {
+	cache   *cache.Cache
+	ttl     time.Duration
+	repo    MissingRepo
+	timeout time.Duration
+	logger  logger.Logger
+}
+
+// NewNegativeCache creates cache, zero ttl disables it
+func NewNegativeCache(ttl time.Duration, repo MissingRepo, timeout time.Duration, logger logger.Logger) *NegativeCache {
+	return &NegativeCache{
+		cache:   cache.New(ttl, 10*time.Minute),
+		ttl:     ttl,
+		repo:    repo,
+		timeout: timeout,
+		logger:  logger.With("service", "negative-cache"),
+	}
+}
+
+// key is "kind:normalized username", kind has no colons, so username can be namespaced by host
+func negativeKey(kind domain.BannerKind, username string) string {
+	return string(kind) + ":" + domain.NormalizeGithubUsername(username)
+}
+
+func (c *NegativeCache) Missing(kind domain.BannerKind, username string) bool {
+	fn := "internal.cache.NegativeCache.Missing"
+	if c.ttl <= 0 {
+		return false
+	}
+	if c.repo == nil {
+		_, found := c.cache.Get(negativeKey(kind, username))
+		return found
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	missing, err := c.repo.IsMissing(ctx, kind, username)
+	if err != nil {
+		c.logger.Warn("can't check missing entity", "source", fn, "err", err)
+		return false
+	}
+	return missing
+}
+
+func (c *NegativeCache) SetMissing(kind domain.BannerKind, username string) {
+	fn := "internal.cache.NegativeCache.SetMissing"
+	if c.ttl <= 0 {
+		return
+	}
+	if c.repo == nil {
+		c.cache.Set(negativeKey(kind, username), struct{}{}, c.ttl)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	err := c.repo.SaveMissing(ctx, domain.MissingGithubEntity{Kind: kind, Username: username, ExpiresAt: time.Now().Add(c.ttl)})
+	if err != nil {
+		c.logger.Warn("can't save missing entity", "source", fn, "err", err)
+	}
+}
+
+// Clear forgets username of every kind and repositories, that it owns
+func (c *NegativeCache) Clear(username string) {
+	fn := "internal.cache.NegativeCache.Clear"
+	if c.repo == nil {
+		normalized := domain.NormalizeGithubUsername(username)
+		for key := range c.cache.Items() {
+			_, keyUsername, _ := strings.Cut(key, ":")
+			if keyUsername == normalized || strings.HasPrefix(keyUsername, normalized+"/") {
+				c.cache.Delete(key)
+			}
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.repo.DeleteMissing(ctx, username); err != nil {
+		c.logger.Warn("can't delete missing entity", "source", fn, "err", err)
+	}
+}
+
+// Purge deletes expired entities from repo
+func (c *NegativeCache) Purge(ctx context.Context) error {
+	if c.repo == nil {
+		return nil
+	}
+	return c.repo.DeleteExpired(ctx)
+}
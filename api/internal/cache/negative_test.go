@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// missingRepoFake is shared storage, like postgres table
+type missingRepoFake struct {
+	mu      sync.Mutex
+	missing map[string]time.Time
+	err     error
+}
+
+func newMissingRepoFake() *missingRepoFake {
+	return &missingRepoFake{missing: map[string]time.Time{}}
+}
+
+func (r *missingRepoFake) SaveMissing(ctx context.Context, missing domain.MissingGithubEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.missing[negativeKey(missing.Kind, missing.Username)] = missing.ExpiresAt
+	return r.err
+}
+
+func (r *missingRepoFake) DeleteMissing(ctx context.Context, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	normalized := domain.NormalizeGithubUsername(username)
+	for key := range r.missing {
+		_, keyUsername, _ := strings.Cut(key, ":")
+		if keyUsername == normalized || strings.HasPrefix(keyUsername, normalized+"/") {
+			delete(r.missing, key)
+		}
+	}
+	return r.err
+}
+
+func (r *missingRepoFake) IsMissing(ctx context.Context, kind domain.BannerKind, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expiresAt, ok := r.missing[negativeKey(kind, username)]
+	return ok && time.Now().Before(expiresAt), r.err
+}
+
+func (r *missingRepoFake) DeleteExpired(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, expiresAt := range r.missing {
+		if !time.Now().Before(expiresAt) {
+			delete(r.missing, key)
+		}
+	}
+	return r.err
+}
+
+func testNegativeCache(t *testing.T, c *NegativeCache) {
+	c.SetMissing(domain.KindUser, "HurtKi")
+	c.SetMissing(domain.KindRepository, "hurtki/typo")
+	c.SetMissing(domain.KindUser, "github.example.com:hurtki")
+	require.True(t, c.Missing(domain.KindUser, "hurtki"))
+	require.True(t, c.Missing(domain.KindRepository, "HurtKi/Typo"))
+	// organization is other kind
+	require.False(t, c.Missing(domain.KindOrg, "hurtki"))
+
+	c.Clear("HURTKI")
+	require.False(t, c.Missing(domain.KindUser, "hurtki"))
+	require.False(t, c.Missing(domain.KindRepository, "hurtki/typo"))
+	require.True(t, c.Missing(domain.KindUser, "github.example.com:hurtki"))
+}
+
+func TestNegativeCacheMemory(t *testing.T) {
+	testNegativeCache(t, NewNegativeCache(time.Hour, nil, time.Second, logger.NewLogger("error", "json")))
+}
+
+func TestNegativeCacheRepo(t *testing.T) {
+	testNegativeCache(t, NewNegativeCache(time.Hour, newMissingRepoFake(), time.Second, logger.NewLogger("error", "json")))
+}
+
+func TestNegativeCacheSharedByReplicas(t *testing.T) {
+	repo := newMissingRepoFake()
+	first := NewNegativeCache(time.Hour, repo, time.Second, logger.NewLogger("error", "json"))
+	second := NewNegativeCache(time.Hour, repo, time.Second, logger.NewLogger("error", "json"))
+
+	first.SetMissing(domain.KindUser, "hurtki")
+	require.True(t, second.Missing(domain.KindUser, "hurtki"))
+
+	// webhook or admin call reached only the second replica
+	second.Clear("hurtki")
+	require.False(t, first.Missing(domain.KindUser, "hurtki"))
+}
+
+func TestNegativeCacheRepoExpiry(t *testing.T) {
+	repo := newMissingRepoFake()
+	repo.missing[negativeKey(domain.KindUser, "expired")] = time.Now().Add(-time.Minute)
+	c := NewNegativeCache(time.Hour, repo, time.Second, logger.NewLogger("error", "json"))
+
+	require.False(t, c.Missing(domain.KindUser, "expired"))
+	require.NoError(t, c.Purge(context.Background()))
+	require.Empty(t, repo.missing)
+}
+
+func TestNegativeCacheRepoError(t *testing.T) {
+	repo := newMissingRepoFake()
+	c := NewNegativeCache(time.Hour, repo, time.Second, logger.NewLogger("error", "json"))
+	c.SetMissing(domain.KindUser, "hurtki")
+
+	// username is fetched from github, if repo can't answer
+	repo.err = errors.New("connection refused")
+	require.False(t, c.Missing(domain.KindUser, "hurtki"))
+}
+
+func TestNegativeCacheDisabled(t *testing.T) {
+	repo := newMissingRepoFake()
+	c := NewNegativeCache(0, repo, time.Second, logger.NewLogger("error", "json"))
+
+	c.SetMissing(domain.KindUser, "hurtki")
+	require.False(t, c.Missing(domain.KindUser, "hurtki"))
+	require.Empty(t, repo.missing)
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	userstats "github.com/hurtki/github-banners/api/internal/domain/user_stats"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// StatsRemoteCache stores statistics in redis compatible server as json, so they are shared by replicas and survive restarts
+// errors of the server are logged, Get with error is a miss
+type StatsRemoteCache struct {
+	client  *resp.Client
+	prefix  string
+	timeout time.Duration
+	logger  logger.Logger
+}
+
+// NewStatsRemoteCache creates cache, that stores keys with prefix, so caches of users, organizations and repositories don't collide
+func NewStatsRemoteCache(client *resp.Client, prefix string, timeout time.Duration, logger logger.Logger) *StatsRemoteCache {
+	return &StatsRemoteCache{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+		logger:  logger.With("service", "stats-remote-cache"),
+	}
+}
+
+func (c *StatsRemoteCache) key(username string) string {
+	return c.prefix + domain.NormalizeGithubUsername(username)
+}
+
+func (c *StatsRemoteCache) Get(username string) (*userstats.CachedStats, bool) {
+	fn := "internal.cache.StatsRemoteCache.Get"
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.key(username))
+	if err != nil {
+		if !errors.Is(err, resp.ErrNil) {
+			c.logger.Warn("can't get stats from remote cache", "source", fn, "err", err)
+		}
+		return nil, false
+	}
+	stats := &userstats.CachedStats{}
+	if err := json.Unmarshal(data, stats); err != nil {
+		c.logger.Warn("can't unmarshal stats from remote cache", "source", fn, "err", err)
+		return nil, false
+	}
+	return stats, true
+}
+
+func (c *StatsRemoteCache) Set(username string, entry *userstats.CachedStats, ttl time.Duration) {
+	fn := "internal.cache.StatsRemoteCache.Set"
+	data, err := json.Marshal(entry)
+	if err != nil {
+		c.logger.Error("can't marshal stats", "source", fn, "err", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.client.Set(ctx, c.key(username), data, ttl); err != nil {
+		c.logger.Warn("can't set stats to remote cache", "source", fn, "err", err)
+	}
+}
+
+func (c *StatsRemoteCache) Delete(username string) {
+	fn := "internal.cache.StatsRemoteCache.Delete"
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.client.Del(ctx, c.key(username)); err != nil {
+		c.logger.Warn("can't delete stats from remote cache", "source", fn, "err", err)
+	}
+}
+
+// PreviewRemoteCache is PreviewMemoryCache, that stores rendered banners in redis compatible server
+// keys are hashes of domain.BannerInfo, hash doesn't depend on process, so replicas share rendered banners
+type PreviewRemoteCache struct {
+	client      *resp.Client
+	ttl         time.Duration
+	timeout     time.Duration
+	hashCounter bannerInfoHashCounter
+	logger      logger.Logger
+}
+
+func NewPreviewRemoteCache(client *resp.Client, ttl time.Duration, timeout time.Duration, logger logger.Logger) *PreviewRemoteCache {
+	return &PreviewRemoteCache{
+		client:      client,
+		ttl:         ttl,
+		timeout:     timeout,
+		hashCounter: newBannerInfoHashCounter(),
+		logger:      logger.With("service", "preview-remote-cache"),
+	}
+}
+
+func (c *PreviewRemoteCache) Get(bf domain.BannerInfo) (*domain.Banner, string, bool) {
+	bf.Username = domain.NormalizeGithubUsername(bf.Username)
+	hashKey := c.hashCounter.Hash(bf)
+	banner, found := c.get(hashKey)
+	return banner, hashKey, found
+}
+
+func (c *PreviewRemoteCache) get(hashKey string) (*domain.Banner, bool) {
+	fn := "internal.cache.PreviewRemoteCache.get"
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, "preview:"+hashKey)
+	if err != nil {
+		if !errors.Is(err, resp.ErrNil) {
+			c.logger.Warn("can't get banner from remote cache", "source", fn, "err", err)
+		}
+		return nil, false
+	}
+	banner := &domain.Banner{}
+	if err := json.Unmarshal(data, banner); err != nil {
+		c.logger.Warn("can't unmarshal banner from remote cache", "source", fn, "err", err)
+		return nil, false
+	}
+	return banner, true
+}
+
+// Set sets rendered banner using hash, that Get method generated
+// banner pointer shouldn't be nil, otherwise panic
+func (c *PreviewRemoteCache) Set(hashKey string, banner *domain.Banner) {
+	fn := "internal.cache.PreviewRemoteCache.Set"
+	if banner == nil {
+		panic("nil banner set in PreviewRemoteCache")
+	}
+	data, err := json.Marshal(banner)
+	if err != nil {
+		c.logger.Error("can't marshal banner", "source", fn, "err", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.client.Set(ctx, "preview:"+hashKey, data, c.ttl); err != nil {
+		c.logger.Warn("can't set banner to remote cache", "source", fn, "err", err)
+	}
+}
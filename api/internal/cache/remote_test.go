@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	userstats "github.com/hurtki/github-banners/api/internal/domain/user_stats"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp/resptest"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFactory(t *testing.T, backend Backend) (*Factory, *resptest.Server) {
+	srv := resptest.NewServer(t)
+	client := resp.NewClient(resp.Config{Addr: srv.Addr})
+	t.Cleanup(func() { client.Close() })
+	return NewFactory(backend, client, time.Minute, time.Minute, time.Second, logger.NewLogger("error", "json")), srv
+}
+
+func testStats() *userstats.CachedStats {
+	return &userstats.CachedStats{
+		Stats: domain.GithubUserStats{
+			TotalRepos: 3,
+			TotalStars: 42,
+			Languages:  map[string]int{"Go": 2},
+			FetchedAt:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStatsRemoteCache(t *testing.T) {
+	f, srv := newTestFactory(t, BackendRemote)
+	c := f.Stats("stats:user:")
+
+	_, found := c.Get("hurtki")
+	require.False(t, found)
+
+	c.Set("HurtKi", testStats(), time.Hour)
+	got, found := c.Get("hurtki")
+	require.True(t, found)
+	require.Equal(t, testStats(), got)
+
+	// other prefix is other cache
+	_, found = f.Stats("stats:org:").Get("hurtki")
+	require.False(t, found)
+
+	c.Delete("HURTKI")
+	_, found = c.Get("hurtki")
+	require.False(t, found)
+	require.Zero(t, srv.Keys())
+}
+
+func TestStatsRemoteCacheServerDown(t *testing.T) {
+	f, srv := newTestFactory(t, BackendRemote)
+	c := f.Stats("stats:user:")
+	srv.Close()
+
+	c.Set("hurtki", testStats(), time.Hour)
+	_, found := c.Get("hurtki")
+	require.False(t, found)
+}
+
+func TestStatsLayeredCacheSharesReplicasStats(t *testing.T) {
+	f, srv := newTestFactory(t, BackendLayered)
+	replica1 := f.Stats("stats:user:")
+	replica2 := f.Stats("stats:user:")
+
+	replica1.Set("hurtki", testStats(), time.Hour)
+	got, found := replica2.Get("hurtki")
+	require.True(t, found)
+	require.Equal(t, testStats(), got)
+
+	// replica2 has stats in memory now
+	srv.Close()
+	_, found = replica2.Get("hurtki")
+	require.True(t, found)
+}
+
+func TestStatsLayeredCacheMemoryTTL(t *testing.T) {
+	srv := resptest.NewServer(t)
+	client := resp.NewClient(resp.Config{Addr: srv.Addr})
+	t.Cleanup(func() { client.Close() })
+	f := NewFactory(BackendLayered, client, time.Minute, 200*time.Millisecond, time.Second, logger.NewLogger("error", "json"))
+	replica1 := f.Stats("stats:user:")
+	replica2 := f.Stats("stats:user:")
+
+	replica1.Set("hurtki", testStats(), time.Hour)
+	_, found := replica2.Get("hurtki")
+	require.True(t, found)
+
+	// invalidation on replica1 doesn't reach memory layer of replica2 until its ttl ends
+	replica1.Delete("hurtki")
+	_, found = replica2.Get("hurtki")
+	require.True(t, found)
+	require.Eventually(t, func() bool {
+		_, found := replica2.Get("hurtki")
+		return !found
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPreviewRemoteCache(t *testing.T) {
+	f, _ := newTestFactory(t, BackendRemote)
+	replica1 := f.Preview()
+	replica2 := f.Preview()
+	info := domain.BannerInfo{Username: "HurtKi", BannerType: domain.TypeDark}
+	banner := &domain.Banner{Username: "hurtki", BannerType: domain.TypeDark, Format: domain.FormatSVG, Banner: []byte("<svg/>")}
+
+	_, hashKey, found := replica1.Get(info)
+	require.False(t, found)
+	replica1.Set(hashKey, banner)
+
+	info.Username = "hurtki"
+	got, otherHashKey, found := replica2.Get(info)
+	require.True(t, found)
+	require.Equal(t, hashKey, otherHashKey)
+	require.Equal(t, banner, got)
+}
+
+func TestPreviewLayeredCache(t *testing.T) {
+	f, srv := newTestFactory(t, BackendLayered)
+	replica1 := f.Preview()
+	replica2 := f.Preview()
+	info := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDefault}
+	banner := &domain.Banner{Username: "hurtki", BannerType: domain.TypeDefault, Format: domain.FormatSVG, Banner: []byte("<svg/>")}
+
+	_, hashKey, _ := replica1.Get(info)
+	replica1.Set(hashKey, banner)
+
+	got, _, found := replica2.Get(info)
+	require.True(t, found)
+	require.Equal(t, banner, got)
+
+	srv.Close()
+	_, _, found = replica2.Get(info)
+	require.True(t, found)
+}
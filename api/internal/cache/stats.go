@@ -37,5 +37,6 @@ func (c *StatsMemoryCache) Set(username string, entry *userstats.CachedStats, tt
 }
 
 func (c *StatsMemoryCache) Delete(username string) {
-	c.cache.Delete(username)
+	normalizedUsername := domain.NormalizeGithubUsername(username)
+	c.cache.Delete(normalizedUsername)
 }
@@ -11,9 +11,60 @@ type Config struct {
 	Port        string
 	CORSOrigins []string
 
+	// tokens of the default host, "host=token" maps token to GitHub Enterprise Server at https://host/
 	GithubTokens []string
+	// api url and upload url of the default host, blank is api.github.com
+	GithubBaseURL   string
+	GithubUploadURL string
+	// GitHub App, that clients are authenticated as together with tokens, 0 id disables it
+	GithubAppID             int64
+	GithubAppPrivateKeyPath string
+	// installations of the app, empty list means all of them
+	GithubAppInstallationIDs []int64
+	// how long request waits for the nearest reset, when all github clients are exhausted, 0 fails at once
+	GithubClientWaitTimeout time.Duration
+	// base64 encoded 32 bytes key, tokens added at runtime are stored encrypted with it, blank keeps them only in memory
+	GithubTokensEncryptionKey string
+
+	// bearer token of admin endpoints, blank disables them
+	AdminToken string
+	// secret of github webhooks, blank disables webhooks endpoint
+	GithubWebhookSecret string
+	// how long events of the same owner are collected before its banners are refreshed
+	GithubWebhookDebounce time.Duration
+	// api, that users data is fetched with: "rest" or "graphql"
+	GithubAPI string
+	// how languages are counted: "primary" ( one per repository ) or "bytes" ( bytes of code )
+	LanguagesMode string
+	// languages, that are not counted in stats
+	ExcludedLanguages []string
 
 	CacheTTL time.Duration
+	// stats older than soft ttl are served, while they are refreshed in background, hard ttl is how long they are cached
+	StatsSoftTTL time.Duration
+	StatsHardTTL time.Duration
+	// how long usernames, that github doesn't have, aren't fetched again, 0 disables negative cache
+	NegativeCacheTTL time.Duration
+	// negative cache is stored in postgres instead of memory, so it survives restarts and is shared by replicas
+	NegativeCachePersist bool
+	// background refreshes of stale users' stats, that run at once, and size of their queue
+	StatsRefreshWorkers   int
+	StatsRefreshQueueSize int
+	// storage of stats and previews caches: "memory", "remote" or "layered" ( memory in front of remote )
+	CacheBackend string
+	// ttl of memory layer of stats in "layered" backend
+	// stats, that other replica dropped or refreshed, can be read from memory layer for this time
+	CacheMemoryTTL time.Duration
+	// replicas elect leader with postgres advisory lock, only leader runs scheduled workers
+	LeaderElection         bool
+	LeaderLockKey          int64
+	LeaderElectionInterval time.Duration
+	// renders of the same preview are serialized across replicas with postgres advisory locks
+	PreviewLocks bool
+	// redis://[:password@]host:port[/db] of remote cache
+	CacheRemoteURL string
+	// limit of single remote cache operation, slower ones are cache misses
+	CacheRemoteTimeout time.Duration
 
 	RequestTimeout time.Duration
 
@@ -21,9 +72,21 @@ type Config struct {
 	LogFormat string
 
 	ServicesSecret string
+	// list of named secrets "kid1:secret1,kid2:secret2:2026-12-31", has priority over ServicesSecret
+	ServicesSecretKeys string
+	// version of canonical, that api signs requests to other services with
+	SignatureVersion int
 
 	StorageBaseURL  string
 	RendererBaseURL string
+
+	// time, that user has to publish ownership challenge
+	OwnershipChallengeTTL time.Duration
+	// require management token for all the usernames, not only for verified ones
+	OwnershipRequired bool
+
+	// how often list of themes is requested from renderer
+	ThemesRefreshInterval time.Duration
 }
 
 func Load() *Config {
@@ -39,17 +102,71 @@ func Load() *Config {
 		githubTokens[i] = strings.TrimSpace(githubTokens[i])
 	}
 
+	appInstallationIDs := []int64{}
+	for _, id := range strings.Split(getEnv("GITHUB_APP_INSTALLATION_IDS", ""), ",") {
+		if parsed, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64); err == nil {
+			appInstallationIDs = append(appInstallationIDs, parsed)
+		}
+	}
+
+	excludedLanguages := []string{}
+	for _, lang := range strings.Split(getEnv("LANGUAGES_EXCLUDE", ""), ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			excludedLanguages = append(excludedLanguages, lang)
+		}
+	}
+
 	return &Config{
-		Port:            getEnv("PORT", "80"),
-		CORSOrigins:     corsOrigins,
-		GithubTokens:    githubTokens,
-		CacheTTL:        getEnvAsDuration("CACHE_TTL", 5*time.Minute),
-		RequestTimeout:  getEnvAsDuration("REQUEST_TIMEOUT", 10*time.Second),
-		LogLevel:        getEnv("LOG_LEVEL", "info"),
-		LogFormat:       getEnv("LOG_FORMAT", "json"),
-		ServicesSecret:  getEnv("SERVICES_SECRET_KEY", "1234"),
-		StorageBaseURL:  getEnv("STORAGE_BASE_URL", "http://storage/"),
-		RendererBaseURL: getEnv("RENDERER_BASE_URL", "https://renderer/"),
+		Port:               getEnv("PORT", "80"),
+		CORSOrigins:        corsOrigins,
+		GithubTokens:       githubTokens,
+		GithubBaseURL:      getEnv("GITHUB_BASE_URL", ""),
+		GithubUploadURL:    getEnv("GITHUB_UPLOAD_URL", ""),
+		GithubAPI:          getEnv("GITHUB_API", "rest"),
+		LanguagesMode:      getEnv("LANGUAGES_MODE", "primary"),
+		ExcludedLanguages:  excludedLanguages,
+		CacheTTL:           getEnvAsDuration("CACHE_TTL", 5*time.Minute),
+		RequestTimeout:     getEnvAsDuration("REQUEST_TIMEOUT", 10*time.Second),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		LogFormat:          getEnv("LOG_FORMAT", "json"),
+		ServicesSecret:     getEnv("SERVICES_SECRET_KEY", "1234"),
+		ServicesSecretKeys: getEnv("SERVICES_SECRET_KEYS", ""),
+		SignatureVersion:   getEnvAsInt("SIGNATURE_VERSION", 2),
+		StorageBaseURL:     getEnv("STORAGE_BASE_URL", "http://storage/"),
+		RendererBaseURL:    getEnv("RENDERER_BASE_URL", "https://renderer/"),
+
+		GithubAppID:              int64(getEnvAsInt("GITHUB_APP_ID", 0)),
+		GithubAppPrivateKeyPath:  getEnv("GITHUB_APP_PRIVATE_KEY_PATH", ""),
+		GithubAppInstallationIDs: appInstallationIDs,
+		GithubClientWaitTimeout:  getEnvAsDuration("GITHUB_CLIENT_WAIT_TIMEOUT", 0),
+
+		GithubTokensEncryptionKey: getEnv("GITHUB_TOKENS_ENCRYPTION_KEY", ""),
+		AdminToken:                getEnv("ADMIN_TOKEN", ""),
+
+		GithubWebhookSecret:   getEnv("GITHUB_WEBHOOK_SECRET", ""),
+		GithubWebhookDebounce: getEnvAsDuration("GITHUB_WEBHOOK_DEBOUNCE", 30*time.Second),
+
+		OwnershipChallengeTTL: getEnvAsDuration("OWNERSHIP_CHALLENGE_TTL", time.Hour),
+		OwnershipRequired:     getEnvAsBool("OWNERSHIP_REQUIRED", false),
+
+		ThemesRefreshInterval: getEnvAsDuration("THEMES_REFRESH_INTERVAL", 5*time.Minute),
+
+		CacheBackend:       getEnv("CACHE_BACKEND", "memory"),
+		CacheMemoryTTL:     getEnvAsDuration("CACHE_MEMORY_TTL", 5*time.Second),
+		CacheRemoteURL:     getEnv("CACHE_REMOTE_URL", "redis://redis:6379/0"),
+		CacheRemoteTimeout: getEnvAsDuration("CACHE_REMOTE_TIMEOUT", 200*time.Millisecond),
+
+		LeaderElection:         getEnvAsBool("LEADER_ELECTION", false),
+		LeaderLockKey:          int64(getEnvAsInt("LEADER_LOCK_KEY", 7291)),
+		LeaderElectionInterval: getEnvAsDuration("LEADER_ELECTION_INTERVAL", 15*time.Second),
+		PreviewLocks:           getEnvAsBool("PREVIEW_LOCKS", false),
+
+		StatsSoftTTL:          getEnvAsDuration("STATS_SOFT_TTL", 10*time.Minute),
+		StatsHardTTL:          getEnvAsDuration("STATS_HARD_TTL", 24*time.Hour),
+		StatsRefreshWorkers:   getEnvAsInt("STATS_REFRESH_WORKERS", 4),
+		NegativeCacheTTL:      getEnvAsDuration("NEGATIVE_CACHE_TTL", 15*time.Minute),
+		NegativeCachePersist:  getEnvAsBool("NEGATIVE_CACHE_PERSIST", false),
+		StatsRefreshQueueSize: getEnvAsInt("STATS_REFRESH_QUEUE_SIZE", 100),
 	}
 }
 
@@ -70,6 +187,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
 		if dur, err := time.ParseDuration(value); err == nil {
@@ -1,28 +1,158 @@
 package domain
 
-type BannerType int
+import "time"
+
+// BannerType is a name of the renderer's theme
+// list of available themes is owned by renderer service, so any type should be checked using themes catalog
+type BannerType string
+
+// themes, that are always embedded into renderer
+const (
+	TypeDefault BannerType = "default"
+	TypeDark    BannerType = "dark"
+)
+
+// BannerKind is a kind of github entity, that banner is built for
+type BannerKind string
 
 const (
-	TypeDefault BannerType = iota
-	TypeDark    BannerType = iota
+	KindUser BannerKind = "user"
+	// organization, its stats are aggregated from its public repositories
+	KindOrg BannerKind = "org"
+	// single repository, its username is a full name of repository: owner/name
+	KindRepository BannerKind = "repository"
 )
 
-var BannerTypes = map[string]BannerType{
-	"default": TypeDefault,
-	"dark":    TypeDark,
+var BannerKinds = map[string]BannerKind{
+	"user":       KindUser,
+	"org":        KindOrg,
+	"repository": KindRepository,
+}
+
+// ParseBannerKind returns KindUser for blank kind
+func ParseBannerKind(v string) (BannerKind, bool) {
+	if v == "" {
+		return KindUser, true
+	}
+	k, ok := BannerKinds[v]
+	return k, ok
+}
+
+// BannerLayout is a name of the renderer's layout: size of the banner and shown blocks
+type BannerLayout string
+
+const (
+	LayoutDefault BannerLayout = "default"
+	// small badge with main counters
+	LayoutCompact BannerLayout = "compact"
+	// 800px header for profile README
+	LayoutWide BannerLayout = "wide"
+	// card with counters only
+	LayoutCard BannerLayout = "card"
+	// languages donut
+	LayoutLanguages BannerLayout = "languages"
+	// card with contribution activity: contributions, streaks, pull requests, issues and reviews
+	LayoutActivity BannerLayout = "activity"
+	// card of single repository: stars, forks, open issues, license, latest release and languages
+	// it's a default layout of repository banners
+	LayoutRepository BannerLayout = "repository"
+)
+
+var BannerLayouts = map[string]BannerLayout{
+	"default":    LayoutDefault,
+	"compact":    LayoutCompact,
+	"wide":       LayoutWide,
+	"card":       LayoutCard,
+	"languages":  LayoutLanguages,
+	"activity":   LayoutActivity,
+	"repository": LayoutRepository,
+}
+
+// ParseBannerLayout returns LayoutDefault for blank layout
+func ParseBannerLayout(v string) (BannerLayout, bool) {
+	if v == "" {
+		return LayoutDefault, true
+	}
+	l, ok := BannerLayouts[v]
+	return l, ok
+}
+
+// ParseKindLayout is ParseBannerLayout, that returns LayoutRepository for blank layout of repository banner
+func ParseKindLayout(kind BannerKind, v string) (BannerLayout, bool) {
+	if v == "" && kind == KindRepository {
+		return LayoutRepository, true
+	}
+	return ParseBannerLayout(v)
+}
+
+// BannerMotion tells, whether svg banner is animated
+type BannerMotion string
+
+const (
+	MotionOn BannerMotion = "on"
+	// static svg without animations and glitch filters
+	// for users, who prefer reduced motion, and markdown renderers, that strip animations
+	MotionOff BannerMotion = "off"
+)
+
+var BannerMotions = map[string]BannerMotion{
+	"on":  MotionOn,
+	"off": MotionOff,
+}
+
+// ParseBannerMotion returns MotionOn for blank motion
+func ParseBannerMotion(v string) (BannerMotion, bool) {
+	if v == "" {
+		return MotionOn, true
+	}
+	m, ok := BannerMotions[v]
+	return m, ok
+}
+
+// BannerFormat is a format of the rendered banner's image
+type BannerFormat string
+
+const (
+	// animated svg, default format
+	FormatSVG BannerFormat = "svg"
+	// static png for places, that don't show svg ( social cards, chats, email )
+	FormatPNG BannerFormat = "png"
+)
+
+var BannerFormats = map[string]BannerFormat{
+	"svg": FormatSVG,
+	"png": FormatPNG,
+}
+
+// ParseBannerFormat returns FormatSVG for blank format
+func ParseBannerFormat(v string) (BannerFormat, bool) {
+	if v == "" {
+		return FormatSVG, true
+	}
+	f, ok := BannerFormats[v]
+	return f, ok
 }
 
-var BannerTypesBackward = map[BannerType]string{
-	TypeDefault: "default",
-	TypeDark:    "dark",
+// ContentType returns media type of the format's image
+func (f BannerFormat) ContentType() string {
+	if f == FormatPNG {
+		return "image/png"
+	}
+	return "image/svg+xml"
 }
 
 // BannerInfo is all data that banner contains
 // used to render banner
 type BannerInfo struct {
+	// Username is a login of the user or organization, or full name of the repository
 	Username   string
+	Kind       BannerKind
 	BannerType BannerType
-	Stats      GithubUserStats
+	Layout     BannerLayout
+	Motion     BannerMotion
+	// Format is a format of the image, that should be rendered, blank is svg
+	Format BannerFormat
+	Stats  GithubUserStats
 }
 
 // Long term banner info, embedded GithubBannerInfo with UrlPath
@@ -34,14 +164,25 @@ type LTBannerInfo struct {
 
 type LTBannerMetadata struct {
 	Username   string
+	Kind       BannerKind
 	BannerType BannerType
+	Layout     BannerLayout
+	Motion     BannerMotion
 	UrlPath    string
 	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// LastRenderRequestedAt is time, when banner was rendered on creation or its update was requested last time
+	// update is rendered asynchronously, so it can be still in progress or failed
+	// nil if banner was never rendered
+	LastRenderRequestedAt *time.Time
 }
 
 // Rendered banner
 type Banner struct {
 	Username   string
 	BannerType BannerType
+	Layout     BannerLayout
+	Format     BannerFormat
 	Banner     []byte
 }
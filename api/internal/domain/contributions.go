@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// GithubContributions is a contribution activity of user in the last year
+type GithubContributions struct {
+	// all the contributions from contribution calendar ( commits, pull requests, issues, reviews, etc. )
+	Total        int
+	Commits      int
+	PullRequests int
+	Issues       int
+	Reviews      int
+	// days in a row with at least one contribution, ending today ( or yesterday, if there are no contributions today yet )
+	CurrentStreak int
+	LongestStreak int
+}
+
+// ContributionDay is a day of contribution calendar
+type ContributionDay struct {
+	Date  time.Time
+	Count int
+}
+
+// CountStreaks returns current and longest streaks of days with contributions
+// days should be sorted by date and the last day is considered as today
+func CountStreaks(days []ContributionDay) (current int, longest int) {
+	run := 0
+	for _, day := range days {
+		if day.Count > 0 {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	last := len(days) - 1
+	// today without contributions doesn't break the streak, day isn't over yet
+	if last >= 0 && days[last].Count == 0 {
+		last--
+	}
+	for i := last; i >= 0 && days[i].Count > 0; i-- {
+		current++
+	}
+	return current, longest
+}
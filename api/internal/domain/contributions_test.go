@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func days(counts ...int) []ContributionDay {
+	res := make([]ContributionDay, len(counts))
+	for i, c := range counts {
+		res[i] = ContributionDay{Count: c}
+	}
+	return res
+}
+
+func TestCountStreaks(t *testing.T) {
+	cases := []struct {
+		name             string
+		days             []ContributionDay
+		current, longest int
+	}{
+		{"empty calendar", nil, 0, 0},
+		{"streak ends today", days(1, 0, 1, 1, 1), 3, 3},
+		{"no contributions today yet", days(1, 1, 1, 0, 2, 0), 1, 3},
+		{"streak was broken yesterday", days(4, 4, 0, 0), 0, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			current, longest := CountStreaks(tc.days)
+			require.Equal(t, tc.current, current)
+			require.Equal(t, tc.longest, longest)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import "errors"
 var (
 	ErrNotFound    = errors.New("not found")
 	ErrUnavailable = errors.New("service unavailable")
+	// github rejected the token with 401, it's revoked, expired or never existed
+	ErrTokenRejected = errors.New("github token was rejected")
 )
 
 type ConflictError struct {
@@ -8,3 +8,33 @@ import "strings"
 func NormalizeGithubUsername(username string) string {
 	return strings.ToLower(username)
 }
+
+// SplitRepoFullName splits full name of repository into owner's login and repository's name
+// both parts should be non blank and can't contain slashes
+func SplitRepoFullName(fullName string) (owner string, name string, ok bool) {
+	owner, name, ok = strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return owner, name, true
+}
+
+// SplitGithubHost splits username, namespaced by github host as "host:login"
+// usernames of the default host aren't namespaced, their host is blank
+// github logins and repository names can't contain colons, so the last one separates the host ( that can have a port )
+func SplitGithubHost(username string) (host string, login string) {
+	i := strings.LastIndex(username, ":")
+	if i < 0 {
+		return "", username
+	}
+	return username[:i], username[i+1:]
+}
+
+// JoinGithubHost namespaces login by host, it's reverse of SplitGithubHost
+// login of the default ( blank ) host stays as it is, so its stored data and cache keys don't change
+func JoinGithubHost(host string, login string) string {
+	if host == "" {
+		return login
+	}
+	return host + ":" + login
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRepoFullName(t *testing.T) {
+	cases := []struct {
+		fullName    string
+		owner, name string
+		ok          bool
+	}{
+		{"hurtki/github-banners", "hurtki", "github-banners", true},
+		{"hurtki/.dotfiles", "hurtki", ".dotfiles", true},
+		{"hurtki", "", "", false},
+		{"hurtki/", "", "", false},
+		{"/github-banners", "", "", false},
+		{"hurtki/github-banners/tree", "", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.fullName, func(t *testing.T) {
+			owner, name, ok := SplitRepoFullName(tc.fullName)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.owner, owner)
+			require.Equal(t, tc.name, name)
+		})
+	}
+}
+
+func TestSplitGithubHost(t *testing.T) {
+	cases := []struct {
+		username    string
+		host, login string
+	}{
+		{"hurtki", "", "hurtki"},
+		{"github.example.com:hurtki", "github.example.com", "hurtki"},
+		{"ghe.local:8443:hurtki", "ghe.local:8443", "hurtki"},
+		{"github.example.com:hurtki/banners", "github.example.com", "hurtki/banners"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.username, func(t *testing.T) {
+			host, login := SplitGithubHost(tc.username)
+			require.Equal(t, tc.host, host)
+			require.Equal(t, tc.login, login)
+		})
+	}
+
+	require.Equal(t, "hurtki", JoinGithubHost("", "hurtki"))
+	require.Equal(t, "github.example.com:hurtki", JoinGithubHost("github.example.com", "hurtki"))
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// GithubTokenSource tells, where token of github clients pool came from
+type GithubTokenSource string
+
+const (
+	// token from GITHUB_TOKENS
+	GithubTokenSourceEnv GithubTokenSource = "env"
+	// installation of GitHub App
+	GithubTokenSourceApp GithubTokenSource = "app"
+	// token added by admin at runtime
+	GithubTokenSourceAdmin GithubTokenSource = "admin"
+)
+
+// GithubToken is a token, that is stored to survive restarts
+// Token is blank for stored mark of disabled token, that isn't stored itself ( token from GITHUB_TOKENS )
+type GithubToken struct {
+	ID       string
+	Host     string
+	Token    string
+	Disabled bool
+	AddedAt  time.Time
+}
+
+// GithubTokenState is a state of client of github clients pool, token itself is shown only masked
+type GithubTokenState struct {
+	ID     string
+	Host   string
+	Masked string
+	Source GithubTokenSource
+	// core ( REST ) and GraphQL budgets
+	Remaining        int
+	ResetsAt         time.Time
+	GraphQLRemaining int
+	GraphQLResetsAt  time.Time
+	// client isn't used until CooldownUntil after secondary rate limit
+	CooldownUntil *time.Time
+	// token was rejected by github with 401
+	Quarantined bool
+	Disabled    bool
+}
+
+// GithubTokenID returns id of token, that is safe to be shown and logged
+// it's stable across restarts, so stored tokens and disabled marks are matched with clients by it
+func GithubTokenID(host string, token string) string {
+	sum := sha256.Sum256([]byte(host + "\n" + token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// githubTokenPrefixes are public prefixes of github tokens formats, they show only the type of the token
+var githubTokenPrefixes = []string{"github_pat_", "ghp_", "gho_", "ghu_", "ghs_", "ghr_"}
+
+// maskedTokenMinSecret is min length of token without prefix, that has its last characters shown
+const maskedTokenMinSecret = 16
+
+// MaskGithubToken keeps only known prefix of the token and its last 4 characters
+// last characters of short token are hidden too, so they aren't a big part of it
+func MaskGithubToken(token string) string {
+	prefix := ""
+	for _, p := range githubTokenPrefixes {
+		if strings.HasPrefix(token, p) {
+			prefix = p
+			break
+		}
+	}
+
+	secret := token[len(prefix):]
+	if len(secret) < maskedTokenMinSecret {
+		return prefix + "..."
+	}
+	return prefix + "..." + secret[len(secret)-4:]
+}
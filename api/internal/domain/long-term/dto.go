@@ -1,10 +1,52 @@
 package longterm
 
+import "time"
+
 type CreateBannerIn struct {
-	Username   string
+	Username string
+	// Kind is optional, blank means user
+	Kind       string
 	BannerType string
+	// Layout is optional, blank means default layout
+	Layout string
+	// Motion is optional, blank means animated banner
+	Motion string
+	// ManagementToken is required, if username has verified owner
+	ManagementToken string
 }
 
 type CreateBannerOut struct {
 	BannerUrlPath string
 }
+
+type BannerOut struct {
+	Username              string
+	Kind                  string
+	BannerType            string
+	Layout                string
+	Motion                string
+	BannerUrlPath         string
+	Active                bool
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	LastRenderRequestedAt *time.Time
+}
+
+type DeleteBannerIn struct {
+	Username        string
+	BannerType      string
+	ManagementToken string
+}
+
+type ListBannersIn struct {
+	Username string
+	Limit    int
+	Offset   int
+}
+
+type ListBannersOut struct {
+	Banners []BannerOut
+	Total   int
+	Limit   int
+	Offset  int
+}
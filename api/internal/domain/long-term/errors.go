@@ -3,7 +3,15 @@ package longterm
 import "errors"
 
 var (
+	ErrInvalidKind       = errors.New("invalid kind")
 	ErrInvalidBannerType = errors.New("invalid banner type")
+	ErrInvalidLayout     = errors.New("invalid layout")
+	ErrInvalidMotion     = errors.New("invalid motion")
 	ErrUserDoesntExist   = errors.New("github user doesn't exist")
 	ErrCantCreateBanner  = errors.New("can't create banner")
+	ErrInvalidInputs     = errors.New("invalid inputs")
+	ErrBannerNotFound    = errors.New("banner not found")
+	ErrCantGetBanner     = errors.New("can't get banner")
+	ErrCantDeleteBanner  = errors.New("can't delete banner")
+	ErrForbidden         = errors.New("valid management token is required")
 )
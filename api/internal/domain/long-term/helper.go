@@ -2,10 +2,33 @@ package longterm
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hurtki/github-banners/api/internal/domain"
 )
 
-func generateUrlPath(username string, bt domain.BannerType) string {
-	return fmt.Sprintf("%s-%s", username, domain.BannerTypesBackward[bt])
+// orgUrlPathPrefix separates url paths of organizations' banners
+// github logins can't contain underscore, so they never collide with users' ones
+const orgUrlPathPrefix = "org_"
+
+// repoUrlPathPrefix separates url paths of repositories' banners
+// slash of full name is replaced with dot, logins can't contain dots, so owner and name are never mixed
+const repoUrlPathPrefix = "repo_"
+
+func generateUrlPath(username string, kind domain.BannerKind, bt domain.BannerType) string {
+	switch kind {
+	case domain.KindOrg:
+		return fmt.Sprintf("%s%s-%s", orgUrlPathPrefix, username, bt)
+	case domain.KindRepository:
+		return fmt.Sprintf("%s%s-%s", repoUrlPathPrefix, strings.Replace(username, "/", ".", 1), bt)
+	}
+	return fmt.Sprintf("%s-%s", username, bt)
+}
+
+// ownerOf returns login, that owns banners of username: owner of the repository for full name of repository
+func ownerOf(username string) string {
+	if owner, _, ok := domain.SplitRepoFullName(username); ok {
+		return owner
+	}
+	return username
 }
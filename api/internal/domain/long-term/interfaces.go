@@ -8,13 +8,28 @@ import (
 
 type BannerRepo interface {
 	GetActiveBanners(ctx context.Context) ([]domain.LTBannerMetadata, error)
+	// GetOwnerActiveBanners returns active banners of owner and of repositories, that it owns
+	GetOwnerActiveBanners(ctx context.Context, owner string) ([]domain.LTBannerMetadata, error)
 	SaveBanner(ctx context.Context, banner domain.LTBannerMetadata) error
 	DeactivateBanner(ctx context.Context, githubUsername string, bannerType domain.BannerType) error
 	GetBanner(ctx context.Context, githubUsername string, bannerType domain.BannerType) (domain.LTBannerMetadata, error)
+	ListBanners(ctx context.Context, githubUsername string, limit, offset int) ([]domain.LTBannerMetadata, int, error)
+	MarkRenderRequested(ctx context.Context, githubUsername string, bannerType domain.BannerType) error
 }
 
 type StatsService interface {
 	GetStats(context.Context, string) (domain.GithubUserStats, error)
+	// RecalculateAndSync fetches data from github, even if cached stats are fresh
+	RecalculateAndSync(context.Context, string) (domain.GithubUserStats, error)
+	// Invalidate drops cached stats
+	Invalidate(string)
+}
+
+// RepoStatsService is StatsService for repositories, they are refreshed by banners worker
+type RepoStatsService interface {
+	StatsService
+	// Refresh fetches repository again, unless it was fetched recently
+	Refresh(context.Context, string) (domain.GithubUserStats, error)
 }
 
 type UpdateRequestPublisher interface {
@@ -26,5 +41,17 @@ type PreviewService interface {
 }
 
 type StorageClient interface {
-	SaveBanner(ctx context.Context, urlPath string, svg string) (string, error)
+	SaveBanner(ctx context.Context, urlPath string, data []byte, format domain.BannerFormat) (string, error)
+	DeleteBanner(ctx context.Context, urlPath string) error
+}
+
+// OwnershipAuthorizer checks, that caller is allowed to manage banners of the username
+// returns ownership.ErrForbidden, if he isn't
+type OwnershipAuthorizer interface {
+	Authorize(ctx context.Context, username string, token string) error
+}
+
+// ThemesCatalog checks, that renderer can render banners of given type
+type ThemesCatalog interface {
+	Has(ctx context.Context, bannerType domain.BannerType) bool
 }
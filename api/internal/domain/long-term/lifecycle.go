@@ -0,0 +1,122 @@
+package longterm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// GetBanner returns metadata of the banner, both active and deactivated
+// banner type isn't checked with themes catalog, so banners of removed themes are still accessible
+func (u *LTBannersUsecase) GetBanner(ctx context.Context, username string, bannerType string) (BannerOut, error) {
+	bt := domain.BannerType(bannerType)
+	if bt == "" {
+		return BannerOut{}, ErrInvalidBannerType
+	}
+	if username == "" {
+		return BannerOut{}, ErrInvalidInputs
+	}
+
+	meta, err := u.bannerRepo.GetBanner(ctx, username, bt)
+	if err != nil {
+		if errors.Is(err, repo.ErrNothingFound) {
+			return BannerOut{}, ErrBannerNotFound
+		}
+		return BannerOut{}, fmt.Errorf("%w: %w", ErrCantGetBanner, err)
+	}
+
+	return toBannerOut(meta), nil
+}
+
+// ListBanners returns page of user's banners
+// zero limit is replaced with DefaultListLimit, limit can't be more than MaxListLimit
+func (u *LTBannersUsecase) ListBanners(ctx context.Context, in ListBannersIn) (ListBannersOut, error) {
+	if in.Username == "" || in.Limit < 0 || in.Offset < 0 {
+		return ListBannersOut{}, ErrInvalidInputs
+	}
+	if in.Limit == 0 {
+		in.Limit = DefaultListLimit
+	}
+	in.Limit = min(in.Limit, MaxListLimit)
+
+	metas, total, err := u.bannerRepo.ListBanners(ctx, in.Username, in.Limit, in.Offset)
+	if err != nil {
+		return ListBannersOut{}, fmt.Errorf("%w: %w", ErrCantGetBanner, err)
+	}
+
+	out := ListBannersOut{
+		Banners: make([]BannerOut, 0, len(metas)),
+		Total:   total,
+		Limit:   in.Limit,
+		Offset:  in.Offset,
+	}
+	for _, meta := range metas {
+		out.Banners = append(out.Banners, toBannerOut(meta))
+	}
+	return out, nil
+}
+
+// DeleteBanner deactivates banner, so it won't be updated anymore, and removes its image from the storage
+// is idempotent: deleting already deactivated banner removes its image again
+// deactivated banner can be activated again with CreateBanner
+// if username ( or owner of the repository ) has verified owner, his management token is required
+func (u *LTBannersUsecase) DeleteBanner(ctx context.Context, in DeleteBannerIn) error {
+	bt := domain.BannerType(in.BannerType)
+	if bt == "" {
+		return ErrInvalidBannerType
+	}
+	if in.Username == "" {
+		return ErrInvalidInputs
+	}
+
+	if err := u.authorize(ctx, ownerOf(in.Username), in.ManagementToken); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrCantDeleteBanner, err)
+	}
+
+	meta, err := u.bannerRepo.GetBanner(ctx, in.Username, bt)
+	if err != nil {
+		if errors.Is(err, repo.ErrNothingFound) {
+			return ErrBannerNotFound
+		}
+		return fmt.Errorf("%w: %w", ErrCantDeleteBanner, err)
+	}
+
+	if meta.Active {
+		// banner could be deactivated concurrently, it's fine
+		if err := u.bannerRepo.DeactivateBanner(ctx, in.Username, bt); err != nil && !errors.Is(err, repo.ErrNothingChanged) {
+			return fmt.Errorf("%w: %w", ErrCantDeleteBanner, err)
+		}
+	}
+
+	if err := u.storageClient.DeleteBanner(ctx, meta.UrlPath); err != nil {
+		return fmt.Errorf("%w: %w", ErrCantDeleteBanner, err)
+	}
+	return nil
+}
+
+func toBannerOut(meta domain.LTBannerMetadata) BannerOut {
+	return BannerOut{
+		Username:              meta.Username,
+		Kind:                  string(meta.Kind),
+		BannerType:            string(meta.BannerType),
+		Layout:                string(meta.Layout),
+		Motion:                string(meta.Motion),
+		BannerUrlPath:         path.Join("/banners/", meta.UrlPath),
+		Active:                meta.Active,
+		CreatedAt:             meta.CreatedAt,
+		UpdatedAt:             meta.UpdatedAt,
+		LastRenderRequestedAt: meta.LastRenderRequestedAt,
+	}
+}
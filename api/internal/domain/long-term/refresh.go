@@ -0,0 +1,55 @@
+package longterm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// RefreshOwner is used, when github reports changes of owner or its repositories:
+// it drops cached stats of owner, fetches them again and publishes update requests of owner's active banners
+// banners of repositories, that owner has, are refreshed too, each user, organization and repository is fetched once
+func (u *LTBannersUsecase) RefreshOwner(ctx context.Context, owner string) ([]Result, error) {
+	// owner can have no banners, but its previews shouldn't be stale either
+	u.statsService.Invalidate(owner)
+	u.orgStatsService.Invalidate(owner)
+
+	banners, err := u.bannerRepo.GetOwnerActiveBanners(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("can't get owner's active banners from repo: %w", err)
+	}
+
+	type fetchKey struct {
+		kind     domain.BannerKind
+		username string
+	}
+	type fetchResult struct {
+		stats domain.GithubUserStats
+		err   error
+	}
+	fetched := make(map[fetchKey]fetchResult)
+
+	results := make([]Result, 0, len(banners))
+	for _, bannerMeta := range banners {
+		key := fetchKey{bannerMeta.Kind, domain.NormalizeGithubUsername(bannerMeta.Username)}
+		res, ok := fetched[key]
+		if !ok {
+			statsService := u.statsFor(bannerMeta.Kind)
+			statsService.Invalidate(bannerMeta.Username)
+			res.stats, res.err = statsService.RecalculateAndSync(ctx, bannerMeta.Username)
+			fetched[key] = res
+		}
+
+		if res.err != nil {
+			results = append(results, Result{bannerMeta, u.statsError(ctx, bannerMeta, res.err)})
+			continue
+		}
+		err := u.publishUpdate(ctx, bannerMeta, res.stats)
+		if err != nil {
+			err = fmt.Errorf("can't update banner: %w", err)
+		}
+		results = append(results, Result{bannerMeta, err})
+	}
+	return results, nil
+}
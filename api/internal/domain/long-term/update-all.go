@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
 )
 
 type UpdateAllConfig struct {
@@ -66,27 +67,56 @@ func (u *LTBannersUsecase) UpdateAll(ctx context.Context, cfg UpdateAllConfig) (
 // updateOne gathers stats and sends banner update request to updateRequestPublisher
 // returns readable errors, should be used only in LTBannersUsecase.UpdateAll method
 func (u *LTBannersUsecase) updateOne(ctx context.Context, bannerMeta domain.LTBannerMetadata) error {
-	stats, err := u.statsService.GetStats(ctx, bannerMeta.Username)
+	getStats := u.statsFor(bannerMeta.Kind).GetStats
+	// repositories aren't refreshed by stats worker, so they are fetched again here
+	if bannerMeta.Kind == domain.KindRepository {
+		getStats = u.repoStatsService.Refresh
+	}
+	stats, err := getStats(ctx, bannerMeta.Username)
 	if err != nil {
-		// if user is not on github -> deactivate his banner
-		if errors.Is(err, domain.ErrNotFound) {
-			u.bannerRepo.DeactivateBanner(ctx, bannerMeta.Username, bannerMeta.BannerType)
-			return fmt.Errorf("user not found on github, deactivating banner: %w", err)
+		return u.statsError(ctx, bannerMeta, err)
+	}
+	return u.publishUpdate(ctx, bannerMeta, stats)
+}
+
+// statsError deactivates banner of user, that isn't on github anymore, and returns readable error
+func (u *LTBannersUsecase) statsError(ctx context.Context, bannerMeta domain.LTBannerMetadata, err error) error {
+	// if user is not on github -> deactivate his banner
+	if errors.Is(err, domain.ErrNotFound) {
+		err = fmt.Errorf("user not found on github, deactivating banner: %w", err)
+		// banner could be deactivated concurrently, it's fine
+		if deactErr := u.bannerRepo.DeactivateBanner(ctx, bannerMeta.Username, bannerMeta.BannerType); deactErr != nil && !errors.Is(deactErr, repo.ErrNothingChanged) {
+			err = errors.Join(err, fmt.Errorf("can't deactivate banner: %w", deactErr))
 		}
-		return fmt.Errorf("can't get user's github stats: %w", err)
+		// so nginx stops serving image of deactivated banner
+		if delErr := u.storageClient.DeleteBanner(ctx, bannerMeta.UrlPath); delErr != nil {
+			err = errors.Join(err, fmt.Errorf("can't delete banner's images: %w", delErr))
+		}
+		return err
 	}
+	return fmt.Errorf("can't get user's github stats: %w", err)
+}
 
+// publishUpdate sends banner update request with stats to updateRequestPublisher
+func (u *LTBannersUsecase) publishUpdate(ctx context.Context, bannerMeta domain.LTBannerMetadata, stats domain.GithubUserStats) error {
 	ltBannerInfo := domain.LTBannerInfo{
 		BannerInfo: domain.BannerInfo{
 			Username:   bannerMeta.Username,
+			Kind:       bannerMeta.Kind,
 			BannerType: bannerMeta.BannerType,
+			Layout:     bannerMeta.Layout,
+			Motion:     bannerMeta.Motion,
 			Stats:      stats,
 		},
 		UrlPath: bannerMeta.UrlPath,
 	}
-	err = u.updateRequestPublisher.Publish(ctx, ltBannerInfo)
+	err := u.updateRequestPublisher.Publish(ctx, ltBannerInfo)
 	if err != nil {
 		return fmt.Errorf("can't publish update request: %w", err)
 	}
+	// banner could be deleted concurrently, it's fine
+	if err := u.bannerRepo.MarkRenderRequested(ctx, bannerMeta.Username, bannerMeta.BannerType); err != nil && !errors.Is(err, repo.ErrNothingFound) {
+		return fmt.Errorf("update request is published, but can't save its time: %w", err)
+	}
 	return nil
 }
@@ -3,9 +3,11 @@ package longterm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path"
 
 	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/domain/ownership"
 	"github.com/hurtki/github-banners/api/internal/repo"
 )
 
@@ -15,6 +17,10 @@ type LTBannersUsecase struct {
 	previewService         PreviewService
 	storageClient          StorageClient
 	statsService           StatsService
+	orgStatsService        StatsService
+	repoStatsService       RepoStatsService
+	ownership              OwnershipAuthorizer
+	themes                 ThemesCatalog
 }
 
 func NewLTBannersUsecase(
@@ -23,6 +29,10 @@ func NewLTBannersUsecase(
 	previewService PreviewService,
 	storageClient StorageClient,
 	statsService StatsService,
+	orgStatsService StatsService,
+	repoStatsService RepoStatsService,
+	ownership OwnershipAuthorizer,
+	themes ThemesCatalog,
 ) *LTBannersUsecase {
 	return &LTBannersUsecase{
 		bannerRepo:             bannerRepo,
@@ -30,23 +40,52 @@ func NewLTBannersUsecase(
 		previewService:         previewService,
 		storageClient:          storageClient,
 		statsService:           statsService,
+		orgStatsService:        orgStatsService,
+		repoStatsService:       repoStatsService,
+		ownership:              ownership,
+		themes:                 themes,
 	}
 }
 
 func (u *LTBannersUsecase) CreateBanner(ctx context.Context, in CreateBannerIn) (CreateBannerOut, error) {
-	bt, ok := domain.BannerTypes[in.BannerType]
+	kind, ok := domain.ParseBannerKind(in.Kind)
 	if !ok {
+		return CreateBannerOut{}, ErrInvalidKind
+	}
+
+	bt := domain.BannerType(in.BannerType)
+	if !u.themes.Has(ctx, bt) {
 		return CreateBannerOut{}, ErrInvalidBannerType
 	}
 
+	bl, ok := domain.ParseKindLayout(kind, in.Layout)
+	if !ok {
+		return CreateBannerOut{}, ErrInvalidLayout
+	}
+
+	motion, ok := domain.ParseBannerMotion(in.Motion)
+	if !ok {
+		return CreateBannerOut{}, ErrInvalidMotion
+	}
+
+	if err := u.authorize(ctx, ownerOf(in.Username), in.ManagementToken); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return CreateBannerOut{}, err
+		}
+		return CreateBannerOut{}, fmt.Errorf("%w: %w", ErrCantCreateBanner, err)
+	}
+
 	bnrMeta, err := u.bannerRepo.GetBanner(ctx, in.Username, bt)
 	if err != nil {
 		var errRepoInternal *repo.ErrRepoInternal
 		switch {
 		case errors.Is(err, repo.ErrNothingFound):
 			bnrMeta.Username = in.Username
+			bnrMeta.Kind = kind
 			bnrMeta.BannerType = bt
-			bnrMeta.UrlPath = generateUrlPath(bnrMeta.Username, bnrMeta.BannerType)
+			bnrMeta.UrlPath = generateUrlPath(bnrMeta.Username, bnrMeta.Kind, bnrMeta.BannerType)
+			bnrMeta.Layout = bl
+			bnrMeta.Motion = motion
 			bnrMeta.Active = true
 		case errors.As(err, &errRepoInternal):
 			// if db internal error occurred, we won't go to next services
@@ -56,14 +95,23 @@ func (u *LTBannersUsecase) CreateBanner(ctx context.Context, in CreateBannerIn)
 			return CreateBannerOut{}, ErrCantCreateBanner
 		}
 	} else {
-		if bnrMeta.Active {
+		// login can't be both user and organization, so existing banner never changes its kind
+		// and its url path, that contains kind, stays the same
+		if bnrMeta.Kind != kind {
+			return CreateBannerOut{}, ErrInvalidKind
+		}
+		// active banner with another layout or motion is rendered again with the new ones on the same url
+		if bnrMeta.Active && bnrMeta.Layout == bl && bnrMeta.Motion == motion {
 			return CreateBannerOut{BannerUrlPath: path.Join("/banners/", bnrMeta.UrlPath)}, nil
 		} else {
 			bnrMeta.Active = true
+			bnrMeta.Layout = bl
+			bnrMeta.Motion = motion
 		}
 	}
 
-	stats, err := u.statsService.GetStats(ctx, in.Username)
+	// full name of repository always contains slash, so it's never taken for a login
+	stats, err := u.statsFor(bnrMeta.Kind).GetStats(ctx, in.Username)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrNotFound):
@@ -74,19 +122,26 @@ func (u *LTBannersUsecase) CreateBanner(ctx context.Context, in CreateBannerIn)
 	}
 
 	// render banner
-	bnrInfo := domain.BannerInfo{Username: in.Username, BannerType: bt, Stats: stats}
+	bnrInfo := domain.BannerInfo{Username: in.Username, Kind: bnrMeta.Kind, BannerType: bt, Layout: bl, Motion: motion, Format: domain.FormatSVG, Stats: stats}
 	bnr, err := u.previewService.GetPreview(ctx, bnrInfo)
 	if err != nil {
 		return CreateBannerOut{}, ErrCantCreateBanner
 	}
 
 	// save rendered banner to storage, so it will be available instantly on returned link
-	bannerUrl, err := u.storageClient.SaveBanner(ctx, bnrMeta.UrlPath, string(bnr.Banner))
+	bannerUrl, err := u.storageClient.SaveBanner(ctx, bnrMeta.UrlPath, bnr.Banner, domain.FormatSVG)
 
 	if err != nil {
 		return CreateBannerOut{}, ErrCantCreateBanner
 	}
 
+	// png is saved best-effort, renderer stores both formats on every update anyway
+	// so failed png doesn't fail banner's creation
+	bnrInfo.Format = domain.FormatPNG
+	if pngBnr, err := u.previewService.GetPreview(ctx, bnrInfo); err == nil {
+		_, _ = u.storageClient.SaveBanner(ctx, bnrMeta.UrlPath, pngBnr.Banner, domain.FormatPNG)
+	}
+
 	// only after all steps, saving to our repo
 	err = u.bannerRepo.SaveBanner(ctx, bnrMeta)
 	if err != nil {
@@ -95,3 +150,26 @@ func (u *LTBannersUsecase) CreateBanner(ctx context.Context, in CreateBannerIn)
 
 	return CreateBannerOut{BannerUrlPath: bannerUrl}, nil
 }
+
+// statsFor returns stats service for banner's kind, blank kind is user
+func (u *LTBannersUsecase) statsFor(kind domain.BannerKind) StatsService {
+	switch kind {
+	case domain.KindOrg:
+		return u.orgStatsService
+	case domain.KindRepository:
+		return u.repoStatsService
+	}
+	return u.statsService
+}
+
+// authorize returns ErrForbidden, if caller can't manage banners of username
+func (u *LTBannersUsecase) authorize(ctx context.Context, username string, token string) error {
+	err := u.ownership.Authorize(ctx, username, token)
+	if err != nil {
+		if errors.Is(err, ownership.ErrForbidden) {
+			return ErrForbidden
+		}
+		return fmt.Errorf("can't authorize: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package longterm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+type bannerRepoFake struct {
+	banners map[string]domain.LTBannerMetadata
+}
+
+func bannerKey(username string, bt domain.BannerType) string {
+	return username + ":" + string(bt)
+}
+
+func (r *bannerRepoFake) GetActiveBanners(ctx context.Context) ([]domain.LTBannerMetadata, error) {
+	return nil, nil
+}
+
+func (r *bannerRepoFake) GetOwnerActiveBanners(ctx context.Context, owner string) ([]domain.LTBannerMetadata, error) {
+	return nil, nil
+}
+
+func (r *bannerRepoFake) SaveBanner(ctx context.Context, banner domain.LTBannerMetadata) error {
+	r.banners[bannerKey(banner.Username, banner.BannerType)] = banner
+	return nil
+}
+
+func (r *bannerRepoFake) DeactivateBanner(ctx context.Context, username string, bt domain.BannerType) error {
+	return nil
+}
+
+func (r *bannerRepoFake) GetBanner(ctx context.Context, username string, bt domain.BannerType) (domain.LTBannerMetadata, error) {
+	banner, ok := r.banners[bannerKey(username, bt)]
+	if !ok {
+		return domain.LTBannerMetadata{}, repo.ErrNothingFound
+	}
+	return banner, nil
+}
+
+func (r *bannerRepoFake) ListBanners(ctx context.Context, username string, limit, offset int) ([]domain.LTBannerMetadata, int, error) {
+	return nil, 0, nil
+}
+
+func (r *bannerRepoFake) MarkRenderRequested(ctx context.Context, username string, bt domain.BannerType) error {
+	return nil
+}
+
+type statsServiceFake struct {
+	requested []string
+}
+
+func (s *statsServiceFake) GetStats(ctx context.Context, username string) (domain.GithubUserStats, error) {
+	s.requested = append(s.requested, username)
+	return domain.GithubUserStats{TotalRepos: 1}, nil
+}
+
+func (s *statsServiceFake) RecalculateAndSync(ctx context.Context, username string) (domain.GithubUserStats, error) {
+	return s.GetStats(ctx, username)
+}
+
+func (s *statsServiceFake) Invalidate(username string) {}
+
+func (s *statsServiceFake) Refresh(ctx context.Context, username string) (domain.GithubUserStats, error) {
+	return s.GetStats(ctx, username)
+}
+
+type previewServiceFake struct {
+	rendered []domain.BannerInfo
+}
+
+func (p *previewServiceFake) GetPreview(ctx context.Context, info domain.BannerInfo) (*domain.Banner, error) {
+	p.rendered = append(p.rendered, info)
+	return &domain.Banner{Username: info.Username, BannerType: info.BannerType, Format: info.Format, Banner: []byte("banner")}, nil
+}
+
+type storageClientFake struct {
+	saved []string
+}
+
+func (s *storageClientFake) SaveBanner(ctx context.Context, urlPath string, data []byte, format domain.BannerFormat) (string, error) {
+	s.saved = append(s.saved, urlPath+"."+string(format))
+	return "/banners/" + urlPath, nil
+}
+
+func (s *storageClientFake) DeleteBanner(ctx context.Context, urlPath string) error {
+	return nil
+}
+
+type ownershipFake struct{}
+
+func (ownershipFake) Authorize(ctx context.Context, username string, token string) error {
+	return nil
+}
+
+type themesFake struct{}
+
+func (themesFake) Has(ctx context.Context, bt domain.BannerType) bool {
+	return bt == domain.TypeDark
+}
+
+type testUsecase struct {
+	*LTBannersUsecase
+	repo      *bannerRepoFake
+	userStats *statsServiceFake
+	orgStats  *statsServiceFake
+	preview   *previewServiceFake
+	storage   *storageClientFake
+}
+
+func newTestUsecase() testUsecase {
+	tu := testUsecase{
+		repo:      &bannerRepoFake{banners: map[string]domain.LTBannerMetadata{}},
+		userStats: &statsServiceFake{},
+		orgStats:  &statsServiceFake{},
+		preview:   &previewServiceFake{},
+		storage:   &storageClientFake{},
+	}
+	tu.LTBannersUsecase = NewLTBannersUsecase(
+		tu.repo, nil, tu.preview, tu.storage,
+		tu.userStats, tu.orgStats, &statsServiceFake{},
+		ownershipFake{}, themesFake{},
+	)
+	return tu
+}
+
+func TestCreateBannerNew(t *testing.T) {
+	u := newTestUsecase()
+
+	out, err := u.CreateBanner(t.Context(), CreateBannerIn{Username: "golang", Kind: "org", BannerType: "dark"})
+	require.NoError(t, err)
+	require.Equal(t, "/banners/org_golang-dark", out.BannerUrlPath)
+
+	require.Equal(t, []string{"golang"}, u.orgStats.requested)
+	require.Empty(t, u.userStats.requested)
+	require.Equal(t, []string{"org_golang-dark.svg", "org_golang-dark.png"}, u.storage.saved)
+
+	saved := u.repo.banners[bannerKey("golang", domain.TypeDark)]
+	require.Equal(t, domain.KindOrg, saved.Kind)
+	require.True(t, saved.Active)
+}
+
+func TestCreateBannerExistingWithAnotherKind(t *testing.T) {
+	u := newTestUsecase()
+	existing := domain.LTBannerMetadata{
+		Username:   "golang",
+		Kind:       domain.KindOrg,
+		BannerType: domain.TypeDark,
+		Layout:     domain.LayoutDefault,
+		Motion:     domain.MotionOn,
+		UrlPath:    "org_golang-dark",
+		Active:     true,
+	}
+	u.repo.banners[bannerKey("golang", domain.TypeDark)] = existing
+
+	// blank kind is user
+	for _, kind := range []string{"", "user", "repository"} {
+		_, err := u.CreateBanner(t.Context(), CreateBannerIn{Username: "golang", Kind: kind, BannerType: "dark"})
+		require.ErrorIs(t, err, ErrInvalidKind, kind)
+	}
+
+	// nothing was rendered or saved
+	require.Empty(t, u.preview.rendered)
+	require.Empty(t, u.storage.saved)
+	require.Equal(t, existing, u.repo.banners[bannerKey("golang", domain.TypeDark)])
+}
+
+func TestCreateBannerExistingRendersNewLayout(t *testing.T) {
+	u := newTestUsecase()
+	u.repo.banners[bannerKey("golang", domain.TypeDark)] = domain.LTBannerMetadata{
+		Username:   "golang",
+		Kind:       domain.KindOrg,
+		BannerType: domain.TypeDark,
+		Layout:     domain.LayoutDefault,
+		Motion:     domain.MotionOn,
+		UrlPath:    "org_golang-dark",
+		Active:     true,
+	}
+
+	// the same banner is returned as is
+	out, err := u.CreateBanner(t.Context(), CreateBannerIn{Username: "golang", Kind: "org", BannerType: "dark"})
+	require.NoError(t, err)
+	require.Equal(t, "/banners/org_golang-dark", out.BannerUrlPath)
+	require.Empty(t, u.preview.rendered)
+
+	out, err = u.CreateBanner(t.Context(), CreateBannerIn{Username: "golang", Kind: "org", BannerType: "dark", Layout: "compact"})
+	require.NoError(t, err)
+	require.Equal(t, "/banners/org_golang-dark", out.BannerUrlPath)
+	require.Equal(t, domain.LayoutCompact, u.preview.rendered[0].Layout)
+	require.Equal(t, domain.LayoutCompact, u.repo.banners[bannerKey("golang", domain.TypeDark)].Layout)
+}
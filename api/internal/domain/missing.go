@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// MissingGithubEntity is user, organization or repository ( by full name ), that github didn't have, when it was fetched
+type MissingGithubEntity struct {
+	Kind      BannerKind
+	Username  string
+	ExpiresAt time.Time
+}
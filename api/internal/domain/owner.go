@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// BannerOwner is a state of ownership verification of github username
+// owner, that passed verification, has TokenHash of his management token
+type BannerOwner struct {
+	Username           string
+	Challenge          string
+	ChallengeExpiresAt time.Time
+	// TokenHash is hex encoded SHA-256 of management token, empty if owner isn't verified yet
+	TokenHash  string
+	VerifiedAt *time.Time
+}
+
+func (o BannerOwner) Verified() bool {
+	return o.TokenHash != ""
+}
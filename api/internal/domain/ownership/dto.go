@@ -0,0 +1,12 @@
+package ownership
+
+import "time"
+
+type ChallengeOut struct {
+	Challenge string
+	ExpiresAt time.Time
+}
+
+type VerifyOut struct {
+	ManagementToken string
+}
@@ -0,0 +1,14 @@
+package ownership
+
+import "errors"
+
+var (
+	ErrInvalidInputs       = errors.New("invalid inputs")
+	ErrNoChallenge         = errors.New("no challenge was created for user")
+	ErrChallengeExpired    = errors.New("challenge expired")
+	ErrProofNotFound       = errors.New("challenge wasn't found in user's gists or profile README")
+	ErrForbidden           = errors.New("valid management token is required")
+	ErrCantVerifyOwner     = errors.New("can't verify owner")
+	ErrCantCreateChallenge = errors.New("can't create challenge")
+	ErrCantAuthorize       = errors.New("can't authorize")
+)
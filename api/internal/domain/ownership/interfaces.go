@@ -0,0 +1,22 @@
+package ownership
+
+import (
+	"context"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+type OwnersRepo interface {
+	// SaveChallenge doesn't replace challenge, that hasn't expired at now, and returns repo.ErrNothingChanged
+	SaveChallenge(ctx context.Context, githubUsername string, challenge string, expiresAt time.Time, now time.Time) error
+	GetOwner(ctx context.Context, githubUsername string) (domain.BannerOwner, error)
+	// SaveToken marks owner as verified and drops his challenge
+	// only if owner still has the same challenge, otherwise returns repo.ErrNothingFound
+	SaveToken(ctx context.Context, githubUsername string, challenge string, tokenHash string) error
+}
+
+// ProofChecker looks for challenge in places, where only owner of github account can put it
+type ProofChecker interface {
+	HasOwnershipProof(ctx context.Context, githubUsername string, challenge string) (bool, error)
+}
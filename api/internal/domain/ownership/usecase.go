@@ -0,0 +1,177 @@
+package ownership
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+)
+
+const challengePrefix = "github-banners-verify-"
+
+type Config struct {
+	// ChallengeTTL is time, that user has to publish challenge
+	ChallengeTTL time.Duration
+	// Required makes management token required even for usernames without verified owner
+	Required bool
+}
+
+// OwnershipUsecase proves, that user owns github account, using challenge
+// that should be published in account's public gist or profile README
+// verified owner receives management token, that is required to manage banners of this account
+type OwnershipUsecase struct {
+	repo    OwnersRepo
+	checker ProofChecker
+	config  Config
+}
+
+func NewOwnershipUsecase(repo OwnersRepo, checker ProofChecker, config Config) *OwnershipUsecase {
+	return &OwnershipUsecase{
+		repo:    repo,
+		checker: checker,
+		config:  config,
+	}
+}
+
+// CreateChallenge creates new challenge for username
+// unexpired challenge is returned as is, so concurrent request can't invalidate challenge, that is being published
+func (u *OwnershipUsecase) CreateChallenge(ctx context.Context, username string) (ChallengeOut, error) {
+	if !validUsername(username) {
+		return ChallengeOut{}, ErrInvalidInputs
+	}
+
+	now := time.Now().UTC()
+	if out, ok, err := u.activeChallenge(ctx, username, now); err != nil || ok {
+		return out, err
+	}
+
+	random, err := randomHex(16)
+	if err != nil {
+		return ChallengeOut{}, fmt.Errorf("%w: %w", ErrCantCreateChallenge, err)
+	}
+	out := ChallengeOut{
+		Challenge: challengePrefix + random,
+		ExpiresAt: now.Add(u.config.ChallengeTTL),
+	}
+
+	if err := u.repo.SaveChallenge(ctx, username, out.Challenge, out.ExpiresAt, now); err != nil {
+		// other request has just created challenge
+		if errors.Is(err, repo.ErrNothingChanged) {
+			if out, ok, err := u.activeChallenge(ctx, username, now); err != nil || ok {
+				return out, err
+			}
+		}
+		return ChallengeOut{}, fmt.Errorf("%w: %w", ErrCantCreateChallenge, err)
+	}
+	return out, nil
+}
+
+// activeChallenge returns challenge of the username, if it hasn't expired at now
+func (u *OwnershipUsecase) activeChallenge(ctx context.Context, username string, now time.Time) (ChallengeOut, bool, error) {
+	owner, err := u.repo.GetOwner(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrNothingFound) {
+			return ChallengeOut{}, false, nil
+		}
+		return ChallengeOut{}, false, fmt.Errorf("%w: %w", ErrCantCreateChallenge, err)
+	}
+	if owner.Challenge == "" || !now.Before(owner.ChallengeExpiresAt) {
+		return ChallengeOut{}, false, nil
+	}
+	return ChallengeOut{Challenge: owner.Challenge, ExpiresAt: owner.ChallengeExpiresAt}, true, nil
+}
+
+// Verify checks, that challenge was published by username and issues new management token
+// previous token of the owner stops being valid
+func (u *OwnershipUsecase) Verify(ctx context.Context, username string) (VerifyOut, error) {
+	if !validUsername(username) {
+		return VerifyOut{}, ErrInvalidInputs
+	}
+
+	owner, err := u.repo.GetOwner(ctx, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrNothingFound) {
+			return VerifyOut{}, ErrNoChallenge
+		}
+		return VerifyOut{}, fmt.Errorf("%w: %w", ErrCantVerifyOwner, err)
+	}
+	if owner.Challenge == "" {
+		return VerifyOut{}, ErrNoChallenge
+	}
+	if time.Now().After(owner.ChallengeExpiresAt) {
+		return VerifyOut{}, ErrChallengeExpired
+	}
+
+	ok, err := u.checker.HasOwnershipProof(ctx, username, owner.Challenge)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return VerifyOut{}, ErrProofNotFound
+		}
+		return VerifyOut{}, fmt.Errorf("%w: %w", ErrCantVerifyOwner, err)
+	}
+	if !ok {
+		return VerifyOut{}, ErrProofNotFound
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return VerifyOut{}, fmt.Errorf("%w: %w", ErrCantVerifyOwner, err)
+	}
+	// challenge is compared on save, so concurrent verification with the same challenge issues only one token
+	if err := u.repo.SaveToken(ctx, username, owner.Challenge, hashToken(token)); err != nil {
+		if errors.Is(err, repo.ErrNothingFound) {
+			return VerifyOut{}, ErrNoChallenge
+		}
+		return VerifyOut{}, fmt.Errorf("%w: %w", ErrCantVerifyOwner, err)
+	}
+	return VerifyOut{ManagementToken: token}, nil
+}
+
+// Authorize checks, that token is management token of username's owner
+// usernames without verified owner are managed by anyone, unless Config.Required is set
+// returns nil, ErrForbidden or ErrCantAuthorize
+func (u *OwnershipUsecase) Authorize(ctx context.Context, username string, token string) error {
+	owner, err := u.repo.GetOwner(ctx, username)
+	if err != nil && !errors.Is(err, repo.ErrNothingFound) {
+		return fmt.Errorf("%w: %w", ErrCantAuthorize, err)
+	}
+
+	if !owner.Verified() {
+		if u.config.Required {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	// hashes are compared in constant time, so token can't be guessed byte by byte
+	if token == "" || subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(owner.TokenHash)) != 1 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func validUsername(username string) bool {
+	return username != "" && url.PathEscape(username) == username
+}
+
+// hashToken returns hex encoded SHA-256 of token, only hashes are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,189 @@
+package ownership
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+type ownersRepoFake struct {
+	owners map[string]domain.BannerOwner
+}
+
+func (r *ownersRepoFake) SaveChallenge(ctx context.Context, username string, challenge string, expiresAt time.Time, now time.Time) error {
+	owner := r.owners[username]
+	if owner.Challenge != "" && now.Before(owner.ChallengeExpiresAt) {
+		return repo.ErrNothingChanged
+	}
+	owner.Username = username
+	owner.Challenge = challenge
+	owner.ChallengeExpiresAt = expiresAt
+	r.owners[username] = owner
+	return nil
+}
+
+func (r *ownersRepoFake) GetOwner(ctx context.Context, username string) (domain.BannerOwner, error) {
+	owner, ok := r.owners[username]
+	if !ok {
+		return domain.BannerOwner{}, repo.ErrNothingFound
+	}
+	return owner, nil
+}
+
+func (r *ownersRepoFake) SaveToken(ctx context.Context, username string, challenge string, tokenHash string) error {
+	owner, ok := r.owners[username]
+	if !ok || owner.Challenge != challenge {
+		return repo.ErrNothingFound
+	}
+	owner.TokenHash = tokenHash
+	owner.Challenge = ""
+	r.owners[username] = owner
+	return nil
+}
+
+type proofCheckerFake struct {
+	published map[string]string
+}
+
+func (c *proofCheckerFake) HasOwnershipProof(ctx context.Context, username string, challenge string) (bool, error) {
+	return c.published[username] == challenge, nil
+}
+
+func newTestUsecase(cfg Config) (*OwnershipUsecase, *ownersRepoFake, *proofCheckerFake) {
+	repo := &ownersRepoFake{owners: map[string]domain.BannerOwner{}}
+	checker := &proofCheckerFake{published: map[string]string{}}
+	return NewOwnershipUsecase(repo, checker, cfg), repo, checker
+}
+
+func TestVerifyIssuesManagementToken(t *testing.T) {
+	u, repo, checker := newTestUsecase(Config{ChallengeTTL: time.Hour})
+	ctx := t.Context()
+
+	challenge, err := u.CreateChallenge(ctx, "hurtki")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(challenge.Challenge, challengePrefix))
+
+	_, err = u.Verify(ctx, "hurtki")
+	require.ErrorIs(t, err, ErrProofNotFound)
+
+	checker.published["hurtki"] = challenge.Challenge
+	out, err := u.Verify(ctx, "hurtki")
+	require.NoError(t, err)
+	require.NotEmpty(t, out.ManagementToken)
+
+	// only hash of the token is stored
+	require.Equal(t, hashToken(out.ManagementToken), repo.owners["hurtki"].TokenHash)
+
+	// challenge can't be used twice
+	_, err = u.Verify(ctx, "hurtki")
+	require.ErrorIs(t, err, ErrNoChallenge)
+}
+
+func TestCreateChallengeKeepsUnexpiredChallenge(t *testing.T) {
+	u, repo, _ := newTestUsecase(Config{ChallengeTTL: time.Hour})
+	ctx := t.Context()
+
+	first, err := u.CreateChallenge(ctx, "hurtki")
+	require.NoError(t, err)
+
+	second, err := u.CreateChallenge(ctx, "hurtki")
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Equal(t, first.Challenge, repo.owners["hurtki"].Challenge)
+}
+
+func TestCreateChallengeReplacesExpiredChallenge(t *testing.T) {
+	u, repo, _ := newTestUsecase(Config{ChallengeTTL: time.Hour})
+	repo.owners["hurtki"] = domain.BannerOwner{Challenge: "old", ChallengeExpiresAt: time.Now().Add(-time.Minute)}
+
+	out, err := u.CreateChallenge(t.Context(), "hurtki")
+	require.NoError(t, err)
+	require.NotEqual(t, "old", out.Challenge)
+	require.Equal(t, out.Challenge, repo.owners["hurtki"].Challenge)
+}
+
+// ownersRepoRacingFake emulates challenge, that was created by concurrent request
+// between reading owner and saving the new challenge
+type ownersRepoRacingFake struct {
+	*ownersRepoFake
+	concurrent domain.BannerOwner
+}
+
+func (r *ownersRepoRacingFake) SaveChallenge(ctx context.Context, username string, challenge string, expiresAt time.Time, now time.Time) error {
+	r.owners[username] = r.concurrent
+	return r.ownersRepoFake.SaveChallenge(ctx, username, challenge, expiresAt, now)
+}
+
+func TestCreateChallengeReturnsConcurrentlyCreatedChallenge(t *testing.T) {
+	concurrent := domain.BannerOwner{Username: "hurtki", Challenge: "concurrent", ChallengeExpiresAt: time.Now().Add(time.Hour)}
+	repo := &ownersRepoRacingFake{ownersRepoFake: &ownersRepoFake{owners: map[string]domain.BannerOwner{}}, concurrent: concurrent}
+	u := NewOwnershipUsecase(repo, &proofCheckerFake{}, Config{ChallengeTTL: time.Hour})
+
+	out, err := u.CreateChallenge(t.Context(), "hurtki")
+	require.NoError(t, err)
+	require.Equal(t, ChallengeOut{Challenge: concurrent.Challenge, ExpiresAt: concurrent.ChallengeExpiresAt}, out)
+}
+
+// checkerReplacingChallengeFake emulates challenge, that was used by concurrent verification
+// while the proof was being checked
+type checkerReplacingChallengeFake struct {
+	repo *ownersRepoFake
+}
+
+func (c *checkerReplacingChallengeFake) HasOwnershipProof(ctx context.Context, username string, challenge string) (bool, error) {
+	owner := c.repo.owners[username]
+	owner.Challenge = ""
+	owner.TokenHash = "concurrent"
+	c.repo.owners[username] = owner
+	return true, nil
+}
+
+func TestVerifyDoesntOverwriteConcurrentVerification(t *testing.T) {
+	repo := &ownersRepoFake{owners: map[string]domain.BannerOwner{
+		"hurtki": {Challenge: "c", ChallengeExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	u := NewOwnershipUsecase(repo, &checkerReplacingChallengeFake{repo: repo}, Config{ChallengeTTL: time.Hour})
+
+	_, err := u.Verify(t.Context(), "hurtki")
+	require.ErrorIs(t, err, ErrNoChallenge)
+	require.Equal(t, "concurrent", repo.owners["hurtki"].TokenHash)
+}
+
+func TestVerifyExpiredChallenge(t *testing.T) {
+	u, repo, _ := newTestUsecase(Config{ChallengeTTL: time.Hour})
+	repo.owners["hurtki"] = domain.BannerOwner{Challenge: "c", ChallengeExpiresAt: time.Now().Add(-time.Minute)}
+
+	_, err := u.Verify(t.Context(), "hurtki")
+	require.ErrorIs(t, err, ErrChallengeExpired)
+}
+
+func TestVerifyWithoutChallenge(t *testing.T) {
+	u, _, _ := newTestUsecase(Config{ChallengeTTL: time.Hour})
+
+	_, err := u.Verify(t.Context(), "hurtki")
+	require.ErrorIs(t, err, ErrNoChallenge)
+}
+
+func TestAuthorize(t *testing.T) {
+	u, repo, _ := newTestUsecase(Config{})
+	repo.owners["hurtki"] = domain.BannerOwner{TokenHash: hashToken("token")}
+	ctx := t.Context()
+
+	require.NoError(t, u.Authorize(ctx, "hurtki", "token"))
+	require.ErrorIs(t, u.Authorize(ctx, "hurtki", "other"), ErrForbidden)
+	require.ErrorIs(t, u.Authorize(ctx, "hurtki", ""), ErrForbidden)
+
+	// username without verified owner can be managed by anyone
+	require.NoError(t, u.Authorize(ctx, "torvalds", ""))
+}
+
+func TestAuthorizeRequired(t *testing.T) {
+	u, _, _ := newTestUsecase(Config{Required: true})
+
+	require.ErrorIs(t, u.Authorize(t.Context(), "torvalds", ""), ErrForbidden)
+}
@@ -0,0 +1,14 @@
+package preview
+
+type GetPreviewIn struct {
+	Username string
+	// Kind is optional, blank means user
+	Kind       string
+	BannerType string
+	// Layout is optional, blank means default layout
+	Layout string
+	// Motion is optional, blank means animated banner
+	Motion string
+	// Format is optional, blank means svg
+	Format string
+}
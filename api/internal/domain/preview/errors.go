@@ -3,7 +3,11 @@ package preview
 import "errors"
 
 var (
+	ErrInvalidKind       = errors.New("invalid kind")
 	ErrInvalidBannerType = errors.New("invalid banner type")
+	ErrInvalidLayout     = errors.New("invalid layout")
+	ErrInvalidMotion     = errors.New("invalid motion")
+	ErrInvalidFormat     = errors.New("invalid format")
 	ErrUserDoesntExist   = errors.New("github user doesn't exist")
 	ErrInvalidInputs     = errors.New("invalid inputs")
 	ErrCantGetPreview    = errors.New("can't get preview")
@@ -2,6 +2,7 @@ package preview
 
 import (
 	"context"
+	"time"
 
 	"github.com/hurtki/github-banners/api/internal/domain"
 	"golang.org/x/sync/singleflight"
@@ -16,19 +17,37 @@ type Cache interface {
 	Set(string, *domain.Banner)
 }
 
+// Locker serializes renders of the same banner across replicas, that share cache
+type Locker interface {
+	// TryLock doesn't wait for the lock, ok is false, if it's held by other replica
+	// unlock releases taken lock
+	TryLock(ctx context.Context, key string) (unlock func(), ok bool, err error)
+}
+
+const (
+	// lockRetryInterval is a pause between tries to take lock of preview, that other replica renders
+	// cache is checked after every pause, so banner is returned as soon as other replica sets it
+	lockRetryInterval = 100 * time.Millisecond
+	// lockMaxWait limits waiting for other replica, then preview is rendered without lock
+	lockMaxWait = 5 * time.Second
+)
+
 // PreviewService is a service that caches renderer results
 // Consider it as a caching wrap for renderer infrastrcture
 // At GetPreview method it will return same result for same bannerInfo, if cache is still valid
+// renders of the same bannerInfo are deduplicated in process, and across replicas, if locker isn't nil
 type PreviewService struct {
 	renderer PreviewRenderer
 	cache    Cache
+	locker   Locker
 	g        singleflight.Group
 }
 
-func NewPreviewService(renderer PreviewRenderer, cache Cache) *PreviewService {
+func NewPreviewService(renderer PreviewRenderer, cache Cache, locker Locker) *PreviewService {
 	return &PreviewService{
 		renderer: renderer,
 		cache:    cache,
+		locker:   locker,
 		g:        singleflight.Group{},
 	}
 }
@@ -40,16 +59,66 @@ func (s *PreviewService) GetPreview(ctx context.Context, bannerInfo domain.Banne
 	}
 
 	res, err, _ := s.g.Do(hash, func() (any, error) {
-		return s.renderer.RenderPreview(ctx, bannerInfo)
+		if s.locker != nil {
+			banner, unlock := s.lock(ctx, bannerInfo, hash)
+			if banner != nil {
+				return banner, nil
+			}
+			if unlock != nil {
+				defer unlock()
+			}
+		}
+
+		banner, err := s.renderer.RenderPreview(ctx, bannerInfo)
+		if err != nil {
+			return nil, err
+		}
+		// cache is set before unlock, so replicas, that wait for the lock, find banner in it
+		if banner != nil {
+			s.cache.Set(hash, banner)
+		}
+		return banner, nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if banner, ok := res.(*domain.Banner); ok {
-		s.cache.Set(hash, banner)
+	if banner, ok := res.(*domain.Banner); ok && banner != nil {
 		return banner, nil
 	}
 	return nil, domain.ErrUnavailable
 }
+
+// lock tries to take lock of the preview, until other replica renders it
+// returns banner, if it appeared in cache, or unlock of taken lock
+// lock is only an optimization, so both are nil, if lock is unavailable, and banner is rendered without it
+func (s *PreviewService) lock(ctx context.Context, bannerInfo domain.BannerInfo, hash string) (*domain.Banner, func()) {
+	deadline := time.Now().Add(lockMaxWait)
+	for {
+		unlock, ok, err := s.locker.TryLock(ctx, hash)
+		if err != nil {
+			return nil, nil
+		}
+		// other replica could render banner, while lock was waited
+		banner, _, found := s.cache.Get(bannerInfo)
+		if found {
+			if ok {
+				unlock()
+			}
+			return banner, nil
+		}
+		if ok {
+			return nil, unlock
+		}
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
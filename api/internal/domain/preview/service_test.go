@@ -2,6 +2,7 @@ package preview
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/hurtki/github-banners/api/internal/domain"
@@ -16,7 +17,7 @@ func TestServiceGetPreviewSuccessFromCache(t *testing.T) {
 	cache := mocks.NewMockCache(ctrl)
 	renderer := mocks.NewMockPreviewRenderer(ctrl)
 
-	service := NewPreviewService(renderer, cache)
+	service := NewPreviewService(renderer, cache, nil)
 
 	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
 	expectedBanner := domain.Banner{Username: bnrInfo.Username, BannerType: bnrInfo.BannerType, Banner: []byte("some")}
@@ -34,7 +35,7 @@ func TestServiceGetPreviewSuccessNoCache(t *testing.T) {
 	cache := mocks.NewMockCache(ctrl)
 	renderer := mocks.NewMockPreviewRenderer(ctrl)
 
-	service := NewPreviewService(renderer, cache)
+	service := NewPreviewService(renderer, cache, nil)
 
 	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
 	expectedBanner := domain.Banner{Username: bnrInfo.Username, BannerType: bnrInfo.BannerType, Banner: []byte("some")}
@@ -56,7 +57,7 @@ func TestServiceGetPreviewErrorsHandling(t *testing.T) {
 	cache := mocks.NewMockCache(ctrl)
 	renderer := mocks.NewMockPreviewRenderer(ctrl)
 
-	service := NewPreviewService(renderer, cache)
+	service := NewPreviewService(renderer, cache, nil)
 
 	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
 	hash := "freferfre"
@@ -77,3 +78,94 @@ func TestServiceGetPreviewErrorsHandling(t *testing.T) {
 		require.Equal(t, er, err)
 	}
 }
+
+type lockerFake struct {
+	// busy is count of tries, when lock is held by other replica
+	busy     int
+	tries    int
+	locked   []string
+	unlocked int
+	err      error
+}
+
+func (l *lockerFake) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	l.tries++
+	if l.tries <= l.busy {
+		return nil, false, nil
+	}
+	l.locked = append(l.locked, key)
+	return func() { l.unlocked++ }, true, nil
+}
+
+func TestServiceGetPreviewRenderedByOtherReplica(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cache := mocks.NewMockCache(ctrl)
+	renderer := mocks.NewMockPreviewRenderer(ctrl)
+	locker := &lockerFake{}
+
+	service := NewPreviewService(renderer, cache, locker)
+
+	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
+	expectedBanner := domain.Banner{Username: bnrInfo.Username, BannerType: bnrInfo.BannerType, Banner: []byte("some")}
+	hash := "frefrfref"
+
+	// banner appears in cache, while lock is taken, so it isn't rendered
+	cache.EXPECT().Get(bnrInfo).Return(nil, hash, false)
+	cache.EXPECT().Get(bnrInfo).Return(&expectedBanner, hash, true)
+
+	bnr, err := service.GetPreview(t.Context(), bnrInfo)
+	require.NoError(t, err)
+	require.Equal(t, &expectedBanner, bnr)
+	require.Equal(t, []string{hash}, locker.locked)
+	require.Equal(t, 1, locker.unlocked)
+}
+
+func TestServiceGetPreviewWaitsOtherReplica(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cache := mocks.NewMockCache(ctrl)
+	renderer := mocks.NewMockPreviewRenderer(ctrl)
+	locker := &lockerFake{busy: 2}
+
+	service := NewPreviewService(renderer, cache, locker)
+
+	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
+	expectedBanner := domain.Banner{Username: bnrInfo.Username, BannerType: bnrInfo.BannerType, Banner: []byte("some")}
+	hash := "frefrfref"
+
+	// other replica holds lock and sets banner to cache, so it's returned without lock and render
+	cache.EXPECT().Get(bnrInfo).Return(nil, hash, false).Times(2)
+	cache.EXPECT().Get(bnrInfo).Return(&expectedBanner, hash, true)
+
+	bnr, err := service.GetPreview(t.Context(), bnrInfo)
+	require.NoError(t, err)
+	require.Equal(t, &expectedBanner, bnr)
+	require.Equal(t, 2, locker.tries)
+	require.Empty(t, locker.locked)
+}
+
+func TestServiceGetPreviewLockUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cache := mocks.NewMockCache(ctrl)
+	renderer := mocks.NewMockPreviewRenderer(ctrl)
+
+	service := NewPreviewService(renderer, cache, &lockerFake{err: errors.New("db is down")})
+
+	bnrInfo := domain.BannerInfo{Username: "hurtki", BannerType: domain.TypeDark}
+	expectedBanner := domain.Banner{Username: bnrInfo.Username, BannerType: bnrInfo.BannerType, Banner: []byte("some")}
+	hash := "frefrfref"
+	ctx := t.Context()
+
+	cache.EXPECT().Get(bnrInfo).Return(nil, hash, false)
+	renderer.EXPECT().RenderPreview(ctx, bnrInfo).Return(&expectedBanner, nil)
+	cache.EXPECT().Set(hash, &expectedBanner)
+
+	bnr, err := service.GetPreview(ctx, bnrInfo)
+	require.NoError(t, err)
+	require.Equal(t, &expectedBanner, bnr)
+}
@@ -15,27 +15,68 @@ type PreviewProvider interface {
 	GetPreview(ctx context.Context, bannerInfo domain.BannerInfo) (*domain.Banner, error)
 }
 
+// ThemesCatalog checks, that renderer can render banners of given type
+type ThemesCatalog interface {
+	Has(ctx context.Context, bannerType domain.BannerType) bool
+}
+
 type PreviewUsecase struct {
 	stats           StatsService
+	orgStats        StatsService
+	repoStats       StatsService
 	previewProvider PreviewProvider
+	themes          ThemesCatalog
 }
 
-func NewPreviewUsecase(stats StatsService, previewProvider PreviewProvider) *PreviewUsecase {
+func NewPreviewUsecase(stats StatsService, orgStats StatsService, repoStats StatsService, previewProvider PreviewProvider, themes ThemesCatalog) *PreviewUsecase {
 	return &PreviewUsecase{
 		stats:           stats,
+		orgStats:        orgStats,
+		repoStats:       repoStats,
 		previewProvider: previewProvider,
+		themes:          themes,
 	}
 }
 
-func (u *PreviewUsecase) GetPreview(ctx context.Context, username string, bannerType string) (*domain.Banner, error) {
-	// bannerType validation
-	bt, ok := domain.BannerTypes[bannerType]
+// GetPreview renders banner of the user, organization or repository with current stats
+// blank kind means user, blank layout means default one ( repository layout for repositories ),
+// blank motion means animated banner, blank format means svg
+func (u *PreviewUsecase) GetPreview(ctx context.Context, in GetPreviewIn) (*domain.Banner, error) {
+	kind, ok := domain.ParseBannerKind(in.Kind)
 	if !ok {
+		return nil, ErrInvalidKind
+	}
+
+	// bannerType validation
+	bt := domain.BannerType(in.BannerType)
+	if !u.themes.Has(ctx, bt) {
 		return nil, ErrInvalidBannerType
 	}
 
-	// getting user's statisctics
-	userStats, err := u.stats.GetStats(ctx, username)
+	bl, ok := domain.ParseKindLayout(kind, in.Layout)
+	if !ok {
+		return nil, ErrInvalidLayout
+	}
+
+	motion, ok := domain.ParseBannerMotion(in.Motion)
+	if !ok {
+		return nil, ErrInvalidMotion
+	}
+
+	format, ok := domain.ParseBannerFormat(in.Format)
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+
+	// getting user's, organization's or repository's statisctics
+	stats := u.stats
+	switch kind {
+	case domain.KindOrg:
+		stats = u.orgStats
+	case domain.KindRepository:
+		stats = u.repoStats
+	}
+	userStats, err := stats.GetStats(ctx, in.Username)
 
 	if err != nil {
 		switch {
@@ -51,8 +92,12 @@ func (u *PreviewUsecase) GetPreview(ctx context.Context, username string, banner
 	}
 
 	preview, err := u.previewProvider.GetPreview(ctx, domain.BannerInfo{
-		Username:   username,
+		Username:   in.Username,
+		Kind:       kind,
 		BannerType: bt,
+		Layout:     bl,
+		Motion:     motion,
+		Format:     format,
 		Stats:      userStats,
 	})
 
@@ -0,0 +1,92 @@
+package themes
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// max time of one themes list request to renderer
+	refreshTimeout = 5 * time.Second
+	// delay before next refresh, if the last one failed
+	failedRefreshRetry = 30 * time.Second
+)
+
+// builtinThemes are always embedded into renderer
+// they are used, when renderer's themes list was never received
+var builtinThemes = []domain.BannerType{domain.TypeDark, domain.TypeDefault}
+
+type ThemesProvider interface {
+	ListThemes(ctx context.Context) ([]domain.BannerType, error)
+}
+
+// Catalog is a cached list of themes, that renderer can render banners with
+// list is refreshed on demand, but not more often than once per refreshInterval
+// if renderer is unavailable, the last received list is used
+type Catalog struct {
+	provider        ThemesProvider
+	refreshInterval time.Duration
+	clock           func() time.Time
+	g               singleflight.Group
+
+	mu          sync.RWMutex
+	themes      []domain.BannerType
+	nextRefresh time.Time
+}
+
+func NewCatalog(provider ThemesProvider, refreshInterval time.Duration) *Catalog {
+	return &Catalog{
+		provider:        provider,
+		refreshInterval: refreshInterval,
+		clock:           time.Now,
+		themes:          builtinThemes,
+	}
+}
+
+// Has reports, whether theme with given name can be used as banner type
+func (c *Catalog) Has(ctx context.Context, name domain.BannerType) bool {
+	return slices.Contains(c.List(ctx), name)
+}
+
+// List returns names of all the themes sorted by name
+func (c *Catalog) List(ctx context.Context) []domain.BannerType {
+	c.mu.RLock()
+	themes, nextRefresh := c.themes, c.nextRefresh
+	c.mu.RUnlock()
+
+	if c.clock().Before(nextRefresh) {
+		return themes
+	}
+
+	res, _, _ := c.g.Do("themes", func() (any, error) {
+		return c.refresh(ctx), nil
+	})
+	return res.([]domain.BannerType)
+}
+
+func (c *Catalog) refresh(ctx context.Context) []domain.BannerType {
+	// refresh shouldn't fail because of one canceled caller, that other callers are waiting for
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+	defer cancel()
+
+	themes, err := c.provider.ListThemes(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil || len(themes) == 0 {
+		c.nextRefresh = c.clock().Add(min(failedRefreshRetry, c.refreshInterval))
+		return c.themes
+	}
+
+	themes = slices.Clone(themes)
+	slices.Sort(themes)
+	c.themes = slices.Compact(themes)
+	c.nextRefresh = c.clock().Add(c.refreshInterval)
+	return c.themes
+}
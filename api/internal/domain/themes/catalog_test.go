@@ -0,0 +1,65 @@
+package themes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type themesProviderFake struct {
+	themes []domain.BannerType
+	err    error
+	calls  int
+}
+
+func (p *themesProviderFake) ListThemes(ctx context.Context) ([]domain.BannerType, error) {
+	p.calls++
+	return p.themes, p.err
+}
+
+func newTestCatalog(provider ThemesProvider, now *time.Time) *Catalog {
+	c := NewCatalog(provider, time.Minute)
+	c.clock = func() time.Time { return *now }
+	return c
+}
+
+func TestCatalogRefreshesAfterInterval(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := &themesProviderFake{themes: []domain.BannerType{"solarized", "dark", "default"}}
+	c := newTestCatalog(provider, &now)
+
+	require.Equal(t, []domain.BannerType{"dark", "default", "solarized"}, c.List(context.Background()))
+	require.True(t, c.Has(context.Background(), "solarized"))
+	require.False(t, c.Has(context.Background(), "unknown"))
+	require.Equal(t, 1, provider.calls)
+
+	provider.themes = []domain.BannerType{"dark", "default"}
+	now = now.Add(time.Minute)
+	require.False(t, c.Has(context.Background(), "solarized"))
+	require.Equal(t, 2, provider.calls)
+}
+
+func TestCatalogKeepsLastListOnError(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := &themesProviderFake{err: domain.ErrUnavailable}
+	c := newTestCatalog(provider, &now)
+
+	// builtin themes are used until renderer responds
+	require.Equal(t, builtinThemes, c.List(context.Background()))
+
+	provider.themes, provider.err = []domain.BannerType{"dark", "default", "solarized"}, nil
+	now = now.Add(time.Minute)
+	require.True(t, c.Has(context.Background(), "solarized"))
+
+	provider.err = domain.ErrUnavailable
+	now = now.Add(time.Minute)
+	require.True(t, c.Has(context.Background(), "solarized"))
+	require.Equal(t, 3, provider.calls)
+
+	// failed refresh is retried not earlier, than in failedRefreshRetry
+	require.True(t, c.Has(context.Background(), "solarized"))
+	require.Equal(t, 3, provider.calls)
+}
@@ -0,0 +1,7 @@
+package tokens
+
+type AddTokenIn struct {
+	// host of GitHub Enterprise Server, blank is the default host
+	Host  string
+	Token string
+}
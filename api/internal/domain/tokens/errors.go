@@ -0,0 +1,13 @@
+package tokens
+
+import "errors"
+
+var (
+	ErrInvalidInputs     = errors.New("invalid inputs")
+	ErrTokenNotFound     = errors.New("token not found")
+	ErrTokenExists       = errors.New("token is already used")
+	ErrTokenRejected     = errors.New("token was rejected by github")
+	ErrCantAddToken      = errors.New("can't add token")
+	ErrCantDisableToken  = errors.New("can't disable token")
+	ErrCantRestoreTokens = errors.New("can't restore stored tokens")
+)
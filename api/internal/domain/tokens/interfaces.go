@@ -0,0 +1,24 @@
+package tokens
+
+import (
+	"context"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// TokensPool is a pool of github clients, that fetcher rotates
+type TokensPool interface {
+	Tokens() []domain.GithubTokenState
+	// AddToken returns domain.ConflictError, if token is already used, disabled token is enabled again
+	AddToken(ctx context.Context, host string, token string) (domain.GithubTokenState, error)
+	// DisableToken returns domain.ErrNotFound, if pool has no such token
+	DisableToken(id string) error
+}
+
+// TokensRepo stores tokens, added at runtime, and marks of disabled ones, so they survive restarts
+type TokensRepo interface {
+	SaveToken(ctx context.Context, token domain.GithubToken) error
+	// DisableToken marks token as disabled, mark is stored even for token, that isn't stored itself
+	DisableToken(ctx context.Context, id string, host string) error
+	GetTokens(ctx context.Context) ([]domain.GithubToken, error)
+}
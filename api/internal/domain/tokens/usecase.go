@@ -0,0 +1,127 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// TokensUsecase manages github tokens of the clients pool at runtime
+// if repo isn't nil, added tokens and disabled marks are stored, so they survive restarts
+type TokensUsecase struct {
+	pool TokensPool
+	repo TokensRepo
+}
+
+func NewTokensUsecase(pool TokensPool, repo TokensRepo) *TokensUsecase {
+	return &TokensUsecase{
+		pool: pool,
+		repo: repo,
+	}
+}
+
+// List returns all the tokens of the pool, including disabled and quarantined ones
+func (u *TokensUsecase) List(ctx context.Context) []domain.GithubTokenState {
+	return u.pool.Tokens()
+}
+
+// Add adds token to the pool, it's used right after github accepts it
+func (u *TokensUsecase) Add(ctx context.Context, in AddTokenIn) (domain.GithubTokenState, error) {
+	host := strings.ToLower(strings.TrimSpace(in.Host))
+	token := strings.TrimSpace(in.Token)
+	if !validToken(token) || strings.ContainsAny(host, "/ ") {
+		return domain.GithubTokenState{}, ErrInvalidInputs
+	}
+
+	state, err := u.pool.AddToken(ctx, host, token)
+	if err != nil {
+		var conflict *domain.ConflictError
+		switch {
+		case errors.As(err, &conflict):
+			return domain.GithubTokenState{}, ErrTokenExists
+		case errors.Is(err, domain.ErrTokenRejected):
+			return domain.GithubTokenState{}, ErrTokenRejected
+		default:
+			return domain.GithubTokenState{}, fmt.Errorf("%w: %w", ErrCantAddToken, err)
+		}
+	}
+
+	if u.repo != nil {
+		err := u.repo.SaveToken(ctx, domain.GithubToken{ID: state.ID, Host: host, Token: token, AddedAt: time.Now().UTC()})
+		if err != nil {
+			// token, that wasn't stored, isn't kept in the pool, so pool and storage don't diverge
+			_ = u.pool.DisableToken(state.ID)
+			return domain.GithubTokenState{}, fmt.Errorf("%w: %w", ErrCantAddToken, err)
+		}
+	}
+	return state, nil
+}
+
+// Disable stops using token with id, token from GITHUB_TOKENS stays disabled after restart, if repo is set
+func (u *TokensUsecase) Disable(ctx context.Context, id string) error {
+	var state *domain.GithubTokenState
+	for _, s := range u.pool.Tokens() {
+		if s.ID == id {
+			state = &s
+			break
+		}
+	}
+	if state == nil {
+		return ErrTokenNotFound
+	}
+
+	if err := u.pool.DisableToken(id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrTokenNotFound
+		}
+		return fmt.Errorf("%w: %w", ErrCantDisableToken, err)
+	}
+	if u.repo != nil {
+		if err := u.repo.DisableToken(ctx, id, state.Host); err != nil {
+			return fmt.Errorf("%w: %w", ErrCantDisableToken, err)
+		}
+	}
+	return nil
+}
+
+// Restore applies stored tokens to the pool on startup: stored tokens are added, tokens with disabled mark are disabled
+// it tries every token, tokens, that failed, are returned in one error
+func (u *TokensUsecase) Restore(ctx context.Context) error {
+	if u.repo == nil {
+		return nil
+	}
+	stored, err := u.repo.GetTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCantRestoreTokens, err)
+	}
+
+	var errs []error
+	for _, token := range stored {
+		if token.Disabled {
+			// disabled mark of token, that was removed from GITHUB_TOKENS, has nothing to disable
+			if err := u.pool.DisableToken(token.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+				errs = append(errs, fmt.Errorf("token %s: %w", token.ID, err))
+			}
+			continue
+		}
+		_, err := u.pool.AddToken(ctx, token.Host, token.Token)
+		var conflict *domain.ConflictError
+		// token can be in GITHUB_TOKENS too
+		if err != nil && !errors.As(err, &conflict) {
+			errs = append(errs, fmt.Errorf("token %s: %w", token.ID, err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("%w: %w", ErrCantRestoreTokens, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validToken checks, that token can be sent in Authorization header and written in GITHUB_TOKENS
+func validToken(token string) bool {
+	return token != "" && !strings.ContainsAny(token, " \t\r\n,=")
+}
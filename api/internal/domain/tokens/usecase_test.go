@@ -0,0 +1,142 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type tokensPoolFake struct {
+	states   []domain.GithubTokenState
+	rejected map[string]bool
+}
+
+func (p *tokensPoolFake) Tokens() []domain.GithubTokenState {
+	return p.states
+}
+
+func (p *tokensPoolFake) AddToken(ctx context.Context, host string, token string) (domain.GithubTokenState, error) {
+	if p.rejected[token] {
+		return domain.GithubTokenState{}, domain.ErrTokenRejected
+	}
+	id := domain.GithubTokenID(host, token)
+	for i, state := range p.states {
+		if state.ID == id {
+			if !state.Disabled {
+				return domain.GithubTokenState{}, domain.NewConflictError("token")
+			}
+			p.states[i].Disabled = false
+			return p.states[i], nil
+		}
+	}
+	state := domain.GithubTokenState{ID: id, Host: host, Masked: domain.MaskGithubToken(token), Source: domain.GithubTokenSourceAdmin}
+	p.states = append(p.states, state)
+	return state, nil
+}
+
+func (p *tokensPoolFake) DisableToken(id string) error {
+	for i, state := range p.states {
+		if state.ID == id {
+			p.states[i].Disabled = true
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+type tokensRepoFake struct {
+	tokens  map[string]domain.GithubToken
+	saveErr error
+}
+
+func (r *tokensRepoFake) SaveToken(ctx context.Context, token domain.GithubToken) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *tokensRepoFake) DisableToken(ctx context.Context, id string, host string) error {
+	token := r.tokens[id]
+	token.ID, token.Host, token.Disabled = id, host, true
+	r.tokens[id] = token
+	return nil
+}
+
+func (r *tokensRepoFake) GetTokens(ctx context.Context) ([]domain.GithubToken, error) {
+	tokens := []domain.GithubToken{}
+	for _, token := range r.tokens {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func TestAddAndDisableToken(t *testing.T) {
+	pool := &tokensPoolFake{rejected: map[string]bool{"revoked": true}}
+	repo := &tokensRepoFake{tokens: map[string]domain.GithubToken{}}
+	u := NewTokensUsecase(pool, repo)
+	ctx := t.Context()
+
+	state, err := u.Add(ctx, AddTokenIn{Host: " GitHub.example.com ", Token: "ghp_secret_token"})
+	require.NoError(t, err)
+	require.Equal(t, "github.example.com", state.Host)
+	require.Equal(t, "ghp_...", state.Masked)
+	require.Equal(t, "ghp_secret_token", repo.tokens[state.ID].Token)
+
+	_, err = u.Add(ctx, AddTokenIn{Host: "github.example.com", Token: "ghp_secret_token"})
+	require.ErrorIs(t, err, ErrTokenExists)
+	_, err = u.Add(ctx, AddTokenIn{Token: "revoked"})
+	require.ErrorIs(t, err, ErrTokenRejected)
+	_, err = u.Add(ctx, AddTokenIn{Token: "two,tokens"})
+	require.ErrorIs(t, err, ErrInvalidInputs)
+
+	require.NoError(t, u.Disable(ctx, state.ID))
+	require.True(t, pool.states[0].Disabled)
+	require.True(t, repo.tokens[state.ID].Disabled)
+	require.ErrorIs(t, u.Disable(ctx, "unknown"), ErrTokenNotFound)
+}
+
+func TestAddTokenNotStoredIsDisabled(t *testing.T) {
+	pool := &tokensPoolFake{}
+	u := NewTokensUsecase(pool, &tokensRepoFake{saveErr: errors.New("db is down")})
+
+	_, err := u.Add(t.Context(), AddTokenIn{Token: "ghp_secret_token"})
+	require.ErrorIs(t, err, ErrCantAddToken)
+	require.True(t, pool.states[0].Disabled)
+}
+
+func TestAddTokenWithoutRepo(t *testing.T) {
+	u := NewTokensUsecase(&tokensPoolFake{}, nil)
+
+	state, err := u.Add(t.Context(), AddTokenIn{Token: "ghp_secret_token"})
+	require.NoError(t, err)
+	require.NoError(t, u.Disable(t.Context(), state.ID))
+	require.NoError(t, u.Restore(t.Context()))
+}
+
+func TestRestoreTokens(t *testing.T) {
+	envToken := domain.GithubTokenState{ID: domain.GithubTokenID("", "ghp_env_token"), Source: domain.GithubTokenSourceEnv}
+	pool := &tokensPoolFake{states: []domain.GithubTokenState{envToken}, rejected: map[string]bool{"revoked": true}}
+	repo := &tokensRepoFake{tokens: map[string]domain.GithubToken{
+		"stored":  {ID: "stored", Token: "ghp_stored_token"},
+		"revoked": {ID: "revoked", Token: "revoked"},
+		// mark of token from GITHUB_TOKENS
+		envToken.ID: {ID: envToken.ID, Disabled: true},
+		// mark of token, that was removed from GITHUB_TOKENS
+		"removed": {ID: "removed", Disabled: true},
+	}}
+	u := NewTokensUsecase(pool, repo)
+
+	err := u.Restore(t.Context())
+	require.ErrorIs(t, err, ErrCantRestoreTokens)
+	require.ErrorIs(t, err, domain.ErrTokenRejected)
+
+	require.Len(t, pool.states, 2)
+	require.True(t, pool.states[0].Disabled)
+	require.Equal(t, domain.GithubTokenID("", "ghp_stored_token"), pool.states[1].ID)
+	require.False(t, pool.states[1].Disabled)
+}
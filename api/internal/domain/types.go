@@ -9,10 +9,13 @@ type GithubRepository struct {
 	OwnerUsername string
 	PushedAt      *time.Time
 	UpdatedAt     *time.Time
+	Name          string
 	Language      *string
 	StarsCount    int
 	Fork          bool
 	ForksCount    int
+	// bytes of code per language, filled only with LanguagesBytes mode
+	Languages map[string]int
 }
 
 type GithubUserData struct {
@@ -26,6 +29,54 @@ type GithubUserData struct {
 	Following    int
 	Repositories []GithubRepository
 	FetchedAt    time.Time
+	// ETags of github responses, that data was built from
+	// nil, if data was fetched without them ( GraphQL api )
+	ETags *GithubDataETags
+	// nil, if contributions weren't fetched yet
+	Contributions *GithubContributions
+}
+
+// GithubOrgData is a profile of organization with its public repositories
+type GithubOrgData struct {
+	Login       string
+	Name        *string
+	Description *string
+	Location    *string
+	PublicRepos int
+	// public members of organization
+	Members      int
+	Repositories []GithubRepository
+	FetchedAt    time.Time
+}
+
+// GithubRepoData is a single repository with its project's details, repository banners are built for it
+type GithubRepoData struct {
+	GithubRepository
+	Description *string
+	// open issues together with open pull requests, github counts them so
+	OpenIssues int
+	// SPDX id of the license, nil if repository has no license
+	License *string
+	// tag of the latest release, nil if repository has no releases
+	LatestRelease *string
+	FetchedAt     time.Time
+}
+
+// GithubDataETags are sent back to github in If-None-Match header,
+// 304 response costs no rate limit and means, that stored data is still actual
+type GithubDataETags struct {
+	User string
+	// pages of repositories list in the order, they were fetched
+	RepoPages []GithubReposPageETag
+}
+
+type GithubReposPageETag struct {
+	ETag string
+	// ids of repositories on the page, so they can be taken from stored data on 304
+	RepoIDs []int64
+	// count of repositories, that github returned on the page, including skipped ones
+	// it's used to find the last page on 304, because Link header isn't covered by ETag
+	Size int
 }
 
 type GithubUserStats struct {
@@ -35,10 +86,51 @@ type GithubUserStats struct {
 	TotalStars    int
 	TotalForks    int
 	Languages     map[string]int
-	FetchedAt     time.Time
+	// nil, if contributions of user are unknown
+	Contributions *GithubContributions
+	// public members, counted only for organizations
+	Members int
+	// nil for banners of users and organizations
+	Repository *GithubRepoStats
+	FetchedAt  time.Time
+}
+
+// GithubRepoStats are details of single repository, that aren't counters of GithubUserStats
+type GithubRepoStats struct {
+	OpenIssues    int
+	License       *string
+	LatestRelease *string
+	PushedAt      *time.Time
 }
 
 type ServiceConfig struct {
 	CacheTTL       time.Duration
 	RequestTimeout time.Duration
+	LanguagesMode  LanguagesMode
+	// how long request can wait for the nearest reset of clients' limits, when all of them are exhausted, 0 fails at once
+	ClientWaitTimeout time.Duration
+}
+
+// LanguagesMode chooses, how languages of user are counted
+type LanguagesMode string
+
+const (
+	// one point per repository for its primary language, costs no additional requests
+	LanguagesPrimary LanguagesMode = "primary"
+	// bytes of code of every language in repository, costs one request per pushed repository
+	LanguagesBytes LanguagesMode = "bytes"
+)
+
+var LanguagesModes = map[string]LanguagesMode{
+	"primary": LanguagesPrimary,
+	"bytes":   LanguagesBytes,
+}
+
+// ParseLanguagesMode returns LanguagesPrimary for blank mode
+func ParseLanguagesMode(v string) (LanguagesMode, bool) {
+	if v == "" {
+		return LanguagesPrimary, true
+	}
+	m, ok := LanguagesModes[v]
+	return m, ok
 }
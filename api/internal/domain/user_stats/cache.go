@@ -1,6 +1,28 @@
 package userstats
 
-// deleting the flow
-func (s *UserStatsService) PurgeUser(username string) {
+import "github.com/hurtki/github-banners/api/internal/domain"
+
+// Invalidate drops cached stats of username, so they aren't served until fetched again
+// username is removed from negative cache too, so it is fetched, even if github didn't have it
+func (s *UserStatsService) Invalidate(username string) {
 	s.cache.Delete(username)
+	if s.negative != nil {
+		s.negative.Clear(username)
+	}
+}
+
+// Invalidate drops cached stats of organization
+func (s *OrgStatsService) Invalidate(login string) {
+	s.cache.Delete(login)
+	if s.negative != nil {
+		s.negative.Clear(login)
+	}
+}
+
+// Invalidate drops cached stats of repository by its full name
+func (s *RepoStatsService) Invalidate(fullName string) {
+	s.cache.Delete(domain.NormalizeGithubUsername(fullName))
+	if s.negative != nil {
+		s.negative.Clear(fullName)
+	}
 }
@@ -1,12 +1,26 @@
 package userstats
 
-import "github.com/hurtki/github-banners/api/internal/domain"
+import (
+	"strings"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
 
 // CalculateStats aggregates repository statistics without additional API calls.
-func CalculateStats(repos []domain.GithubRepository) domain.GithubUserStats {
+// Languages are counted according to cfg.LanguagesMode, excluded languages are skipped
+func CalculateStats(repos []domain.GithubRepository, cfg Config) domain.GithubUserStats {
 	var stats domain.GithubUserStats
 	stats.Languages = make(map[string]int)
 
+	excluded := make(map[string]struct{}, len(cfg.ExcludedLanguages))
+	for _, lang := range cfg.ExcludedLanguages {
+		excluded[strings.ToLower(lang)] = struct{}{}
+	}
+	isExcluded := func(lang string) bool {
+		_, ok := excluded[strings.ToLower(lang)]
+		return ok
+	}
+
 	for _, repo := range repos {
 		if repo.Fork {
 			stats.ForkedRepos++
@@ -15,8 +29,17 @@ func CalculateStats(repos []domain.GithubRepository) domain.GithubUserStats {
 			stats.TotalStars += repo.StarsCount
 			stats.TotalForks += repo.ForksCount
 
-			if lang := repo.Language; lang != nil {
-				stats.Languages[*lang] += 1
+			switch cfg.LanguagesMode {
+			case domain.LanguagesBytes:
+				for lang, bytes := range repo.Languages {
+					if !isExcluded(lang) {
+						stats.Languages[lang] += bytes
+					}
+				}
+			default:
+				if lang := repo.Language; lang != nil && !isExcluded(*lang) {
+					stats.Languages[*lang] += 1
+				}
 			}
 		}
 	}
@@ -0,0 +1,45 @@
+package userstats
+
+import (
+	"testing"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func testRepos() []domain.GithubRepository {
+	goLang, makefile, html := "Go", "Makefile", "HTML"
+	return []domain.GithubRepository{
+		{ID: 1, Language: &goLang, StarsCount: 10, ForksCount: 1, Languages: map[string]int{"Go": 90000, "Makefile": 500}},
+		{ID: 2, Language: &makefile, StarsCount: 1, Languages: map[string]int{"Makefile": 200}},
+		{ID: 3, Language: &html, Languages: map[string]int{"HTML": 50000, "JavaScript": 7000}},
+		// forks are not counted in languages and stars
+		{ID: 4, Language: &goLang, StarsCount: 100, Fork: true, Languages: map[string]int{"Go": 1000000}},
+	}
+}
+
+func TestCalculateStatsPrimaryLanguages(t *testing.T) {
+	stats := CalculateStats(testRepos(), Config{LanguagesMode: domain.LanguagesPrimary})
+
+	require.Equal(t, 4, stats.TotalRepos)
+	require.Equal(t, 3, stats.OriginalRepos)
+	require.Equal(t, 1, stats.ForkedRepos)
+	require.Equal(t, 11, stats.TotalStars)
+	require.Equal(t, 1, stats.TotalForks)
+	require.Equal(t, map[string]int{"Go": 1, "Makefile": 1, "HTML": 1}, stats.Languages)
+}
+
+func TestCalculateStatsLanguageBytes(t *testing.T) {
+	stats := CalculateStats(testRepos(), Config{LanguagesMode: domain.LanguagesBytes})
+	require.Equal(t, map[string]int{"Go": 90000, "Makefile": 700, "HTML": 50000, "JavaScript": 7000}, stats.Languages)
+}
+
+func TestCalculateStatsExcludedLanguages(t *testing.T) {
+	cfg := Config{LanguagesMode: domain.LanguagesBytes, ExcludedLanguages: []string{"html", "Makefile"}}
+	stats := CalculateStats(testRepos(), cfg)
+	require.Equal(t, map[string]int{"Go": 90000, "JavaScript": 7000}, stats.Languages)
+
+	cfg.LanguagesMode = domain.LanguagesPrimary
+	stats = CalculateStats(testRepos(), cfg)
+	require.Equal(t, map[string]int{"Go": 1}, stats.Languages)
+}
@@ -20,5 +20,29 @@ type Cache interface {
 }
 
 type UserDataFetcher interface {
-	FetchUserData(ctx context.Context, username string) (*domain.GithubUserData, error)
+	// FetchUserData fetches user's data from github
+	// previous is a stored data of the user or nil, fetcher can use it to revalidate data instead of downloading it again
+	FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error)
+}
+
+type GithubOrgDataRepository interface {
+	SaveOrgData(ctx context.Context, orgData domain.GithubOrgData) error
+	GetOrgData(ctx context.Context, login string) (domain.GithubOrgData, error)
+}
+
+type OrgDataFetcher interface {
+	// FetchOrgData fetches organization's data from github
+	// previous is a stored data of the organization or nil
+	FetchOrgData(ctx context.Context, login string, previous *domain.GithubOrgData) (*domain.GithubOrgData, error)
+}
+
+type GithubRepoDataRepository interface {
+	SaveRepoData(ctx context.Context, repoData domain.GithubRepoData) error
+	GetRepoData(ctx context.Context, fullName string) (domain.GithubRepoData, error)
+}
+
+type RepoDataFetcher interface {
+	// FetchRepoData fetches single repository from github by its full name: owner/name
+	// previous is a stored data of the repository or nil
+	FetchRepoData(ctx context.Context, fullName string, previous *domain.GithubRepoData) (*domain.GithubRepoData, error)
 }
@@ -7,9 +7,85 @@ import (
 )
 
 type UserStatsService struct {
-	repo    GithubUserDataRepository
-	fetcher UserDataFetcher
-	cache   Cache
+	repo      GithubUserDataRepository
+	fetcher   UserDataFetcher
+	cache     Cache
+	negative  NegativeCache
+	config    Config
+	refresher *refresher
+	metrics   *metrics
+}
+
+// OrgStatsService is UserStatsService for organizations
+// it uses its own cache, so logins of users and organizations never mix
+type OrgStatsService struct {
+	repo      GithubOrgDataRepository
+	fetcher   OrgDataFetcher
+	cache     Cache
+	negative  NegativeCache
+	config    Config
+	refresher *refresher
+	metrics   *metrics
+}
+
+// RepoStatsService is UserStatsService for single repositories
+// repositories aren't refreshed by stats worker, banners worker refreshes repositories of active banners
+type RepoStatsService struct {
+	repo      GithubRepoDataRepository
+	fetcher   RepoDataFetcher
+	cache     Cache
+	negative  NegativeCache
+	config    Config
+	refresher *refresher
+	metrics   *metrics
+}
+
+type Config struct {
+	// how languages are counted, fetcher should be configured with the same mode
+	LanguagesMode domain.LanguagesMode
+	// languages, that are not counted at all ( vendored, generated or markup ones ), case insensitive
+	ExcludedLanguages []string
+	// cached stats older than SoftTTL are returned, but refreshed in background, HardTTL is how long stats are cached
+	// zero values are DefaultSoftTTL and DefaultHardTTL
+	SoftTTL time.Duration
+	HardTTL time.Duration
+	// count of background refreshes of stale stats, that run at once, and of the queued ones, for every stats service
+	// zero values are DefaultRefreshWorkers and DefaultRefreshQueueSize
+	RefreshWorkers   int
+	RefreshQueueSize int
+}
+
+func (c Config) softTTL() time.Duration {
+	if c.SoftTTL <= 0 {
+		return DefaultSoftTTL
+	}
+	return c.SoftTTL
+}
+
+func (c Config) hardTTL() time.Duration {
+	if c.HardTTL <= 0 {
+		return DefaultHardTTL
+	}
+	return c.HardTTL
+}
+
+func (c Config) refreshWorkers() int {
+	if c.RefreshWorkers <= 0 {
+		return DefaultRefreshWorkers
+	}
+	return c.RefreshWorkers
+}
+
+func (c Config) refreshQueueSize() int {
+	if c.RefreshQueueSize <= 0 {
+		return DefaultRefreshQueueSize
+	}
+	return c.RefreshQueueSize
+}
+
+// refreshTimeout limits single background refresh
+func (c Config) refreshTimeout() time.Duration {
+	return 30 * time.Second
 }
 
 type CachedStats struct {
@@ -0,0 +1,24 @@
+package userstats
+
+import (
+	"fmt"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// NegativeCache remembers users, organizations and repositories, that github doesn't have,
+// so requests of typo'd or deleted usernames don't spend github rate limit
+type NegativeCache interface {
+	Missing(kind domain.BannerKind, username string) bool
+	SetMissing(kind domain.BannerKind, username string)
+	// Clear forgets username of every kind and repositories, that it owns
+	Clear(username string)
+}
+
+// checkMissing returns domain.ErrNotFound, if negative cache has username, negative cache can be nil
+func checkMissing(negative NegativeCache, kind domain.BannerKind, username string) error {
+	if negative != nil && negative.Missing(kind, username) {
+		return fmt.Errorf("%s is cached as missing: %w", kind, domain.ErrNotFound)
+	}
+	return nil
+}
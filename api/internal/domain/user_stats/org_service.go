@@ -0,0 +1,107 @@
+package userstats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// NewOrgStatsService starts workers of background refreshes, they are stopped by Close
+func NewOrgStatsService(repo GithubOrgDataRepository, fetcher OrgDataFetcher, cache Cache, negative NegativeCache, config Config) *OrgStatsService {
+	s := &OrgStatsService{
+		repo:     repo,
+		fetcher:  fetcher,
+		cache:    cache,
+		negative: negative,
+		config:   config,
+		metrics:  &metrics{},
+	}
+	s.refresher = newRefresher(func(ctx context.Context, login string) error {
+		_, err := s.RecalculateAndSync(ctx, login)
+		return err
+	}, config, s.metrics)
+	return s
+}
+
+// GetStats returns aggregated stats of organization's public repositories
+// organizations aren't refreshed by stats worker, so refresh of stale data is queued on access
+func (s *OrgStatsService) GetStats(ctx context.Context, login string) (domain.GithubUserStats, error) {
+	cached, found := s.cache.Get(login)
+	if found {
+		if time.Since(cached.UpdatedAt) <= s.config.softTTL() {
+			s.metrics.cacheHits.Add(1)
+			return cached.Stats, nil
+		}
+
+		s.metrics.staleServed.Add(1)
+		s.refresher.schedule(login)
+		return cached.Stats, nil
+	}
+	s.metrics.cacheMisses.Add(1)
+
+	if err := checkMissing(s.negative, domain.KindOrg, login); err != nil {
+		return domain.GithubUserStats{}, err
+	}
+
+	dbData, err := s.repo.GetOrgData(ctx, login)
+	if err == nil {
+		stats := s.calculate(dbData)
+		// stored data is as old as its fetch, so it's refreshed on the next access after soft TTL
+		s.cache.Set(login, &CachedStats{
+			Stats:     stats,
+			UpdatedAt: dbData.FetchedAt,
+		}, s.config.hardTTL())
+		return stats, nil
+	}
+
+	return s.RecalculateAndSync(ctx, login)
+}
+
+// fetch api -> save db -> calc stats -> write cache
+func (s *OrgStatsService) RecalculateAndSync(ctx context.Context, login string) (domain.GithubUserStats, error) {
+	var previous *domain.GithubOrgData
+	if stored, err := s.repo.GetOrgData(ctx, login); err == nil {
+		previous = &stored
+	}
+
+	data, err := s.fetcher.FetchOrgData(ctx, login, previous)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) && s.negative != nil {
+			s.negative.SetMissing(domain.KindOrg, login)
+		}
+		return domain.GithubUserStats{}, fmt.Errorf("can't fetch data for organization: %w", err)
+	}
+
+	stats := s.calculate(*data)
+	if err := s.repo.SaveOrgData(ctx, *data); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return domain.GithubUserStats{}, err
+		}
+	}
+	s.cache.Set(login, &CachedStats{
+		Stats:     stats,
+		UpdatedAt: time.Now(),
+	}, s.config.hardTTL())
+
+	return stats, nil
+}
+
+// Metrics returns counters of cache and background refreshes
+func (s *OrgStatsService) Metrics() Metrics {
+	return s.metrics.snapshot()
+}
+
+// Close cancels running background refreshes and drops queued ones
+func (s *OrgStatsService) Close(ctx context.Context) error {
+	return s.refresher.close(ctx)
+}
+
+func (s *OrgStatsService) calculate(data domain.GithubOrgData) domain.GithubUserStats {
+	stats := CalculateStats(data.Repositories, s.config)
+	stats.Members = data.Members
+	stats.FetchedAt = data.FetchedAt
+	return stats
+}
@@ -0,0 +1,146 @@
+package userstats
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// Metrics are counters of stats service since start
+type Metrics struct {
+	// fresh stats were returned from cache
+	CacheHits int64 `json:"cache_hits"`
+	// stats weren't in cache
+	CacheMisses int64 `json:"cache_misses"`
+	// stale stats were returned from cache, while they are refreshed in background
+	StaleServed int64 `json:"stale_served"`
+	// background refreshes, that were queued
+	RefreshScheduled int64 `json:"refresh_scheduled"`
+	// background refreshes, that weren't queued, because the same username is already queued
+	RefreshDeduped int64 `json:"refresh_deduped"`
+	// background refreshes, that weren't queued, because queue is full
+	RefreshDropped   int64 `json:"refresh_dropped"`
+	RefreshSucceeded int64 `json:"refresh_succeeded"`
+	RefreshFailed    int64 `json:"refresh_failed"`
+}
+
+type metrics struct {
+	cacheHits, cacheMisses, staleServed              atomic.Int64
+	refreshScheduled, refreshDeduped, refreshDropped atomic.Int64
+	refreshSucceeded, refreshFailed                  atomic.Int64
+}
+
+func (m *metrics) snapshot() Metrics {
+	return Metrics{
+		CacheHits:        m.cacheHits.Load(),
+		CacheMisses:      m.cacheMisses.Load(),
+		StaleServed:      m.staleServed.Load(),
+		RefreshScheduled: m.refreshScheduled.Load(),
+		RefreshDeduped:   m.refreshDeduped.Load(),
+		RefreshDropped:   m.refreshDropped.Load(),
+		RefreshSucceeded: m.refreshSucceeded.Load(),
+		RefreshFailed:    m.refreshFailed.Load(),
+	}
+}
+
+// refresher runs background refreshes of stale stats with bounded count of workers
+// every username is queued at most once, until its refresh is finished
+type refresher struct {
+	refresh func(ctx context.Context, username string) error
+	timeout time.Duration
+	metrics *metrics
+
+	queue   chan string
+	mu      sync.Mutex
+	pending map[string]struct{}
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+func newRefresher(refresh func(ctx context.Context, username string) error, cfg Config, metrics *metrics) *refresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &refresher{
+		refresh: refresh,
+		timeout: cfg.refreshTimeout(),
+		metrics: metrics,
+		queue:   make(chan string, cfg.refreshQueueSize()),
+		pending: make(map[string]struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for range cfg.refreshWorkers() {
+		r.wg.Go(r.work)
+	}
+	return r
+}
+
+// schedule queues refresh of username, unless it's already queued or queue is full
+func (r *refresher) schedule(username string) {
+	key := domain.NormalizeGithubUsername(username)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ctx.Err() != nil {
+		return
+	}
+	if _, ok := r.pending[key]; ok {
+		r.metrics.refreshDeduped.Add(1)
+		return
+	}
+	select {
+	case r.queue <- key:
+		r.pending[key] = struct{}{}
+		r.metrics.refreshScheduled.Add(1)
+	default:
+		r.metrics.refreshDropped.Add(1)
+	}
+}
+
+func (r *refresher) work() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case username := <-r.queue:
+			// select could choose queue over closed ctx
+			if r.ctx.Err() != nil {
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+			if err := r.refresh(ctx, username); err != nil {
+				r.metrics.refreshFailed.Add(1)
+			} else {
+				r.metrics.refreshSucceeded.Add(1)
+			}
+			cancel()
+
+			r.mu.Lock()
+			delete(r.pending, username)
+			r.mu.Unlock()
+		}
+	}
+}
+
+// close cancels running refreshes, drops queued ones and waits for workers
+func (r *refresher) close(ctx context.Context) error {
+	r.mu.Lock()
+	r.cancel()
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package userstats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// NewRepoStatsService starts workers of background refreshes, they are stopped by Close
+func NewRepoStatsService(repo GithubRepoDataRepository, fetcher RepoDataFetcher, cache Cache, negative NegativeCache, config Config) *RepoStatsService {
+	s := &RepoStatsService{
+		repo:     repo,
+		fetcher:  fetcher,
+		cache:    cache,
+		negative: negative,
+		config:   config,
+		metrics:  &metrics{},
+	}
+	s.refresher = newRefresher(func(ctx context.Context, fullName string) error {
+		_, err := s.RecalculateAndSync(ctx, fullName)
+		return err
+	}, config, s.metrics)
+	return s
+}
+
+// GetStats returns stats of single repository by its full name: owner/name
+// refresh of stale data is queued on access, like organizations' one
+func (s *RepoStatsService) GetStats(ctx context.Context, fullName string) (domain.GithubUserStats, error) {
+	fullName = domain.NormalizeGithubUsername(fullName)
+	cached, found := s.cache.Get(fullName)
+	if found {
+		if time.Since(cached.UpdatedAt) <= s.config.softTTL() {
+			s.metrics.cacheHits.Add(1)
+			return cached.Stats, nil
+		}
+
+		s.metrics.staleServed.Add(1)
+		s.refresher.schedule(fullName)
+		return cached.Stats, nil
+	}
+	s.metrics.cacheMisses.Add(1)
+
+	if err := checkMissing(s.negative, domain.KindRepository, fullName); err != nil {
+		return domain.GithubUserStats{}, err
+	}
+
+	dbData, err := s.repo.GetRepoData(ctx, fullName)
+	if err == nil {
+		stats := s.calculate(dbData)
+		// stored data is as old as its fetch, so it's refreshed on the next access after soft TTL
+		s.cache.Set(fullName, &CachedStats{
+			Stats:     stats,
+			UpdatedAt: dbData.FetchedAt,
+		}, s.config.hardTTL())
+		return stats, nil
+	}
+
+	return s.RecalculateAndSync(ctx, fullName)
+}
+
+// Refresh is used by banners worker: it fetches repository again, unless it was fetched within soft TTL
+// so banners of the same repository with different themes cost one fetch
+func (s *RepoStatsService) Refresh(ctx context.Context, fullName string) (domain.GithubUserStats, error) {
+	fullName = domain.NormalizeGithubUsername(fullName)
+	if cached, found := s.cache.Get(fullName); found && time.Since(cached.UpdatedAt) <= s.config.softTTL() {
+		return cached.Stats, nil
+	}
+	if err := checkMissing(s.negative, domain.KindRepository, fullName); err != nil {
+		return domain.GithubUserStats{}, err
+	}
+	return s.RecalculateAndSync(ctx, fullName)
+}
+
+// fetch api -> save db -> calc stats -> write cache
+func (s *RepoStatsService) RecalculateAndSync(ctx context.Context, fullName string) (domain.GithubUserStats, error) {
+	fullName = domain.NormalizeGithubUsername(fullName)
+	var previous *domain.GithubRepoData
+	if stored, err := s.repo.GetRepoData(ctx, fullName); err == nil {
+		previous = &stored
+	}
+
+	data, err := s.fetcher.FetchRepoData(ctx, fullName, previous)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) && s.negative != nil {
+			s.negative.SetMissing(domain.KindRepository, fullName)
+		}
+		return domain.GithubUserStats{}, fmt.Errorf("can't fetch data for repository: %w", err)
+	}
+
+	stats := s.calculate(*data)
+	if err := s.repo.SaveRepoData(ctx, *data); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return domain.GithubUserStats{}, err
+		}
+	}
+	s.cache.Set(fullName, &CachedStats{
+		Stats:     stats,
+		UpdatedAt: time.Now(),
+	}, s.config.hardTTL())
+
+	return stats, nil
+}
+
+// Metrics returns counters of cache and background refreshes
+func (s *RepoStatsService) Metrics() Metrics {
+	return s.metrics.snapshot()
+}
+
+// Close cancels running background refreshes and drops queued ones
+func (s *RepoStatsService) Close(ctx context.Context) error {
+	return s.refresher.close(ctx)
+}
+
+// calculate counts repository's own stars, forks and languages, even if it's a fork
+// languages are always counted in bytes, excluded languages are skipped
+func (s *RepoStatsService) calculate(data domain.GithubRepoData) domain.GithubUserStats {
+	repo := data.GithubRepository
+	repo.Fork = false
+	stats := CalculateStats([]domain.GithubRepository{repo}, Config{
+		LanguagesMode:     domain.LanguagesBytes,
+		ExcludedLanguages: s.config.ExcludedLanguages,
+	})
+	if data.Fork {
+		stats.OriginalRepos, stats.ForkedRepos = 0, 1
+	}
+	stats.Repository = &domain.GithubRepoStats{
+		OpenIssues:    data.OpenIssues,
+		License:       data.License,
+		LatestRelease: data.LatestRelease,
+		PushedAt:      data.PushedAt,
+	}
+	stats.FetchedAt = data.FetchedAt
+	return stats
+}
@@ -11,46 +11,61 @@ import (
 )
 
 const (
-	SoftTTL = 10 * time.Minute
-	HardTTL = 24 * time.Hour
+	DefaultSoftTTL          = 10 * time.Minute
+	DefaultHardTTL          = 24 * time.Hour
+	DefaultRefreshWorkers   = 4
+	DefaultRefreshQueueSize = 100
 )
 
-func NewUserStatsService(repo GithubUserDataRepository, fetcher UserDataFetcher, cache Cache) *UserStatsService {
-	return &UserStatsService{
-		repo:    repo,
-		fetcher: fetcher,
-		cache:   cache,
+// NewUserStatsService starts workers of background refreshes, they are stopped by Close
+// negative cache can be nil
+func NewUserStatsService(repo GithubUserDataRepository, fetcher UserDataFetcher, cache Cache, negative NegativeCache, config Config) *UserStatsService {
+	s := &UserStatsService{
+		repo:     repo,
+		fetcher:  fetcher,
+		cache:    cache,
+		negative: negative,
+		config:   config,
+		metrics:  &metrics{},
 	}
+	s.refresher = newRefresher(func(ctx context.Context, username string) error {
+		_, err := s.RecalculateAndSync(ctx, username)
+		return err
+	}, config, s.metrics)
+	return s
 }
 
+// GetStats returns cached stats, stats older than soft TTL are returned too, but their refresh is queued
+// refreshes are deduplicated per user, so popular banner costs one fetch
 func (s *UserStatsService) GetStats(ctx context.Context, username string) (domain.GithubUserStats, error) {
 	cached, found := s.cache.Get(username)
 	if found {
-		// fresh <10mins
 		age := time.Since(cached.UpdatedAt)
-		if age <= SoftTTL {
+		if age <= s.config.softTTL() {
+			s.metrics.cacheHits.Add(1)
 			return cached.Stats, nil
 		}
 
-		// state >10mins but <24 hours
-		go func() {
-			// usage of context.Background(), cause this operation is idependent of parent context
-			timeoutCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
-			_, _ = s.RecalculateAndSync(timeoutCtx, username)
-		}()
+		s.metrics.staleServed.Add(1)
+		s.refresher.schedule(username)
 		return cached.Stats, nil
 	}
+	s.metrics.cacheMisses.Add(1)
+
+	if err := checkMissing(s.negative, domain.KindUser, username); err != nil {
+		return domain.GithubUserStats{}, err
+	}
 
 	// checking database if cache missed
 	dbData, err := s.repo.GetUserData(ctx, username)
 	if err == nil {
-		stats := CalculateStats(dbData.Repositories)
+		stats := CalculateStats(dbData.Repositories, s.config)
+		stats.Contributions = dbData.Contributions
 		stats.FetchedAt = dbData.FetchedAt
 		s.cache.Set(username, &CachedStats{
 			Stats:     stats,
 			UpdatedAt: time.Now(),
-		}, HardTTL)
+		}, s.config.hardTTL())
 		return stats, nil
 	}
 
@@ -59,13 +74,23 @@ func (s *UserStatsService) GetStats(ctx context.Context, username string) (domai
 
 // fetch api -> save db -> calc stats -> write cache
 func (s *UserStatsService) RecalculateAndSync(ctx context.Context, username string) (domain.GithubUserStats, error) {
+	// stored data lets fetcher make conditional requests, that cost no rate limit, if nothing changed
+	var previous *domain.GithubUserData
+	if stored, err := s.repo.GetUserData(ctx, username); err == nil {
+		previous = &stored
+	}
+
 	// fetching raw data from github
-	data, err := s.fetcher.FetchUserData(ctx, username)
+	data, err := s.fetcher.FetchUserData(ctx, username, previous)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) && s.negative != nil {
+			s.negative.SetMissing(domain.KindUser, username)
+		}
 		return domain.GithubUserStats{}, fmt.Errorf("can't fetch data for user: %w", err)
 	}
 
-	stats := CalculateStats(data.Repositories)
+	stats := CalculateStats(data.Repositories, s.config)
+	stats.Contributions = data.Contributions
 	stats.FetchedAt = data.FetchedAt
 	// updating database with the new raw data
 	if err := s.repo.SaveUserData(ctx, *data); err != nil {
@@ -77,11 +102,21 @@ func (s *UserStatsService) RecalculateAndSync(ctx context.Context, username stri
 	s.cache.Set(username, &CachedStats{
 		Stats:     stats,
 		UpdatedAt: time.Now(),
-	}, HardTTL)
+	}, s.config.hardTTL())
 
 	return stats, nil
 }
 
+// Metrics returns counters of cache and background refreshes
+func (s *UserStatsService) Metrics() Metrics {
+	return s.metrics.snapshot()
+}
+
+// Close cancels running background refreshes and drops queued ones
+func (s *UserStatsService) Close(ctx context.Context) error {
+	return s.refresher.close(ctx)
+}
+
 func (s *UserStatsService) RefreshAll(ctx context.Context, cfg WorkerConfig) (<-chan string, <-chan error) {
 	if cfg.BatchSize <= 0 {
 		cfg.BatchSize = 10
@@ -0,0 +1,264 @@
+package userstats
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheFake struct {
+	mu      sync.Mutex
+	entries map[string]*CachedStats
+}
+
+func (c *cacheFake) Get(username string) (*CachedStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[domain.NormalizeGithubUsername(username)]
+	return entry, ok
+}
+
+func (c *cacheFake) Set(username string, entry *CachedStats, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[domain.NormalizeGithubUsername(username)] = entry
+}
+
+func (c *cacheFake) Delete(username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, domain.NormalizeGithubUsername(username))
+}
+
+type userRepoFake struct{}
+
+func (r userRepoFake) SaveUserData(ctx context.Context, userData domain.GithubUserData) error {
+	return nil
+}
+
+func (r userRepoFake) GetUserData(ctx context.Context, username string) (domain.GithubUserData, error) {
+	return domain.GithubUserData{}, domain.ErrNotFound
+}
+
+func (r userRepoFake) GetAllUsernames(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// fetcherFake blocks every fetch until release is closed
+type fetcherFake struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *fetcherFake) FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error) {
+	f.calls.Add(1)
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &domain.GithubUserData{Username: username, FetchedAt: time.Now()}, nil
+}
+
+func newStaleService(cfg Config) (*UserStatsService, *fetcherFake, *cacheFake) {
+	cache := &cacheFake{entries: map[string]*CachedStats{
+		"hurtki": {UpdatedAt: time.Now().Add(-time.Hour)},
+	}}
+	fetcher := &fetcherFake{release: make(chan struct{})}
+	s := NewUserStatsService(userRepoFake{}, fetcher, cache, nil, cfg)
+	return s, fetcher, cache
+}
+
+func TestGetStatsDedupesStaleRefresh(t *testing.T) {
+	s, fetcher, cache := newStaleService(Config{SoftTTL: time.Minute})
+	t.Cleanup(func() { s.Close(context.Background()) })
+
+	for _, username := range []string{"hurtki", "HurtKi", "HURTKI"} {
+		_, err := s.GetStats(t.Context(), username)
+		require.NoError(t, err)
+	}
+	close(fetcher.release)
+
+	require.Eventually(t, func() bool { return s.Metrics().RefreshSucceeded == 1 }, time.Second, 5*time.Millisecond)
+	require.EqualValues(t, 1, fetcher.calls.Load())
+	require.Equal(t, Metrics{StaleServed: 3, RefreshScheduled: 1, RefreshDeduped: 2, RefreshSucceeded: 1}, s.Metrics())
+
+	// refreshed stats are fresh
+	entry, _ := cache.Get("hurtki")
+	require.WithinDuration(t, time.Now(), entry.UpdatedAt, time.Second)
+	_, err := s.GetStats(t.Context(), "hurtki")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, s.Metrics().CacheHits)
+}
+
+func TestGetStatsDropsRefreshOfFullQueue(t *testing.T) {
+	s, _, cache := newStaleService(Config{RefreshWorkers: 1, RefreshQueueSize: 1})
+	cache.Set("torvalds", &CachedStats{UpdatedAt: time.Now().Add(-time.Hour)}, time.Hour)
+	cache.Set("octocat", &CachedStats{UpdatedAt: time.Now().Add(-time.Hour)}, time.Hour)
+
+	// worker takes hurtki and blocks, torvalds waits in queue, octocat doesn't fit
+	s.GetStats(t.Context(), "hurtki")
+	require.Eventually(t, func() bool { return len(s.refresher.queue) == 0 }, time.Second, time.Millisecond)
+	s.GetStats(t.Context(), "torvalds")
+	s.GetStats(t.Context(), "octocat")
+	require.EqualValues(t, 1, s.Metrics().RefreshDropped)
+
+	// running refresh is cancelled on close
+	require.NoError(t, s.Close(context.Background()))
+	require.EqualValues(t, 1, s.Metrics().RefreshFailed)
+}
+
+type orgRepoFake struct{}
+
+func (r orgRepoFake) SaveOrgData(ctx context.Context, orgData domain.GithubOrgData) error {
+	return nil
+}
+
+func (r orgRepoFake) GetOrgData(ctx context.Context, login string) (domain.GithubOrgData, error) {
+	return domain.GithubOrgData{}, domain.ErrNotFound
+}
+
+func (f *fetcherFake) FetchOrgData(ctx context.Context, login string, previous *domain.GithubOrgData) (*domain.GithubOrgData, error) {
+	if _, err := f.FetchUserData(ctx, login, nil); err != nil {
+		return nil, err
+	}
+	return &domain.GithubOrgData{Login: login, FetchedAt: time.Now()}, nil
+}
+
+type repoRepoFake struct{}
+
+func (r repoRepoFake) SaveRepoData(ctx context.Context, repoData domain.GithubRepoData) error {
+	return nil
+}
+
+func (r repoRepoFake) GetRepoData(ctx context.Context, fullName string) (domain.GithubRepoData, error) {
+	return domain.GithubRepoData{}, domain.ErrNotFound
+}
+
+func (f *fetcherFake) FetchRepoData(ctx context.Context, fullName string, previous *domain.GithubRepoData) (*domain.GithubRepoData, error) {
+	if _, err := f.FetchUserData(ctx, fullName, nil); err != nil {
+		return nil, err
+	}
+	return &domain.GithubRepoData{FetchedAt: time.Now()}, nil
+}
+
+// statsService is common part of organization and repository services
+type statsService interface {
+	GetStats(ctx context.Context, username string) (domain.GithubUserStats, error)
+	Metrics() Metrics
+	Close(ctx context.Context) error
+}
+
+func TestOrgAndRepoGetStatsDedupeStaleRefresh(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		usernames []string
+		service   func(fetcher *fetcherFake, cache *cacheFake) statsService
+	}{
+		{
+			name:      "organization",
+			username:  "gophers",
+			usernames: []string{"gophers", "Gophers", "GOPHERS"},
+			service: func(fetcher *fetcherFake, cache *cacheFake) statsService {
+				return NewOrgStatsService(orgRepoFake{}, fetcher, cache, nil, Config{SoftTTL: time.Minute})
+			},
+		},
+		{
+			name:      "repository",
+			username:  "hurtki/github-banners",
+			usernames: []string{"hurtki/github-banners", "HurtKi/GitHub-Banners", "HURTKI/GITHUB-BANNERS"},
+			service: func(fetcher *fetcherFake, cache *cacheFake) statsService {
+				return NewRepoStatsService(repoRepoFake{}, fetcher, cache, nil, Config{SoftTTL: time.Minute})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &cacheFake{entries: map[string]*CachedStats{
+				tt.username: {UpdatedAt: time.Now().Add(-time.Hour)},
+			}}
+			fetcher := &fetcherFake{release: make(chan struct{})}
+			s := tt.service(fetcher, cache)
+			t.Cleanup(func() { s.Close(context.Background()) })
+
+			for _, username := range tt.usernames {
+				_, err := s.GetStats(t.Context(), username)
+				require.NoError(t, err)
+			}
+			close(fetcher.release)
+
+			require.Eventually(t, func() bool { return s.Metrics().RefreshSucceeded == 1 }, time.Second, 5*time.Millisecond)
+			require.EqualValues(t, 1, fetcher.calls.Load())
+			require.Equal(t, Metrics{StaleServed: 3, RefreshScheduled: 1, RefreshDeduped: 2, RefreshSucceeded: 1}, s.Metrics())
+
+			_, err := s.GetStats(t.Context(), tt.username)
+			require.NoError(t, err)
+			require.EqualValues(t, 1, s.Metrics().CacheHits)
+		})
+	}
+}
+
+func TestOrgStatsCloseCancelsRefresh(t *testing.T) {
+	cache := &cacheFake{entries: map[string]*CachedStats{
+		"gophers": {UpdatedAt: time.Now().Add(-time.Hour)},
+	}}
+	fetcher := &fetcherFake{release: make(chan struct{})}
+	s := NewOrgStatsService(orgRepoFake{}, fetcher, cache, nil, Config{RefreshWorkers: 1})
+
+	s.GetStats(t.Context(), "gophers")
+	require.Eventually(t, func() bool { return fetcher.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, s.Close(context.Background()))
+	require.Equal(t, Metrics{StaleServed: 1, RefreshScheduled: 1, RefreshFailed: 1}, s.Metrics())
+}
+
+type negativeCacheFake struct {
+	missing map[string]bool
+}
+
+func (c *negativeCacheFake) Missing(kind domain.BannerKind, username string) bool {
+	return c.missing[string(kind)+":"+domain.NormalizeGithubUsername(username)]
+}
+
+func (c *negativeCacheFake) SetMissing(kind domain.BannerKind, username string) {
+	c.missing[string(kind)+":"+domain.NormalizeGithubUsername(username)] = true
+}
+
+func (c *negativeCacheFake) Clear(username string) {
+	delete(c.missing, string(domain.KindUser)+":"+domain.NormalizeGithubUsername(username))
+}
+
+type notFoundFetcherFake struct {
+	calls int
+}
+
+func (f *notFoundFetcherFake) FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error) {
+	f.calls++
+	return nil, domain.ErrNotFound
+}
+
+func TestGetStatsCachesMissingUser(t *testing.T) {
+	fetcher := &notFoundFetcherFake{}
+	negative := &negativeCacheFake{missing: map[string]bool{}}
+	s := NewUserStatsService(userRepoFake{}, fetcher, &cacheFake{entries: map[string]*CachedStats{}}, negative, Config{})
+	t.Cleanup(func() { s.Close(context.Background()) })
+
+	for _, username := range []string{"typo", "TYPO"} {
+		_, err := s.GetStats(t.Context(), username)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	require.Equal(t, 1, fetcher.calls)
+
+	// webhook or admin says, that user exists
+	s.Invalidate("typo")
+	_, err := s.GetStats(t.Context(), "typo")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.Equal(t, 2, fetcher.calls)
+}
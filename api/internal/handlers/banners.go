@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/hurtki/github-banners/api/internal/domain"
 	longterm "github.com/hurtki/github-banners/api/internal/domain/long-term"
 	"github.com/hurtki/github-banners/api/internal/domain/preview"
@@ -13,33 +15,47 @@ import (
 )
 
 type PreviewUsecase interface {
-	GetPreview(ctx context.Context, username string, bannerType string) (*domain.Banner, error)
+	GetPreview(ctx context.Context, in preview.GetPreviewIn) (*domain.Banner, error)
 }
 
 type BannersHandler struct {
-	logger    logger.Logger
+	responder
 	preview   PreviewUsecase
 	ltBanners LTBannersUsecase
+	ownership OwnershipUsecase
 }
 
-func NewBannersHandler(logger logger.Logger, previewUsecase PreviewUsecase, ltBannersUsecase LTBannersUsecase) *BannersHandler {
+func NewBannersHandler(logger logger.Logger, previewUsecase PreviewUsecase, ltBannersUsecase LTBannersUsecase, ownershipUsecase OwnershipUsecase) *BannersHandler {
 	return &BannersHandler{
-		logger:    logger.With("service", "banners-handler"),
+		responder: responder{logger: logger.With("service", "banners-handler")},
 		preview:   previewUsecase,
 		ltBanners: ltBannersUsecase,
+		ownership: ownershipUsecase,
 	}
 }
 
 func (h *BannersHandler) Preview(rw http.ResponseWriter, req *http.Request) {
 	fn := "internal.handlers.BannersHandler.Preview"
-	username := req.URL.Query().Get("username")
-	bannerType := req.URL.Query().Get("type")
-
-	banner, err := h.preview.GetPreview(req.Context(), username, bannerType)
+	banner, err := h.preview.GetPreview(req.Context(), preview.GetPreviewIn{
+		Username:   req.URL.Query().Get("username"),
+		Kind:       req.URL.Query().Get("kind"),
+		BannerType: req.URL.Query().Get("type"),
+		Layout:     req.URL.Query().Get("layout"),
+		Motion:     req.URL.Query().Get("motion"),
+		Format:     requestedFormat(req),
+	})
 	if err != nil {
 		switch {
+		case errors.Is(err, preview.ErrInvalidKind):
+			h.error(rw, http.StatusBadRequest, "invalid kind")
 		case errors.Is(err, preview.ErrInvalidBannerType):
 			h.error(rw, http.StatusBadRequest, "invalid banner type")
+		case errors.Is(err, preview.ErrInvalidLayout):
+			h.error(rw, http.StatusBadRequest, "invalid layout")
+		case errors.Is(err, preview.ErrInvalidMotion):
+			h.error(rw, http.StatusBadRequest, "invalid motion")
+		case errors.Is(err, preview.ErrInvalidFormat):
+			h.error(rw, http.StatusBadRequest, "invalid format")
 		case errors.Is(err, preview.ErrUserDoesntExist):
 			h.error(rw, http.StatusNotFound, "user not found on github")
 		case errors.Is(err, preview.ErrInvalidInputs):
@@ -54,7 +70,7 @@ func (h *BannersHandler) Preview(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	rw.Header().Add("Content-Type", "image/svg+xml")
+	rw.Header().Add("Content-Type", banner.Format.ContentType())
 	rw.WriteHeader(http.StatusOK)
 	_, err = rw.Write(banner.Banner)
 	if err != nil {
@@ -64,6 +80,9 @@ func (h *BannersHandler) Preview(rw http.ResponseWriter, req *http.Request) {
 
 type LTBannersUsecase interface {
 	CreateBanner(ctx context.Context, in longterm.CreateBannerIn) (longterm.CreateBannerOut, error)
+	GetBanner(ctx context.Context, username string, bannerType string) (longterm.BannerOut, error)
+	ListBanners(ctx context.Context, in longterm.ListBannersIn) (longterm.ListBannersOut, error)
+	DeleteBanner(ctx context.Context, in longterm.DeleteBannerIn) error
 }
 
 func (h *BannersHandler) Create(rw http.ResponseWriter, req *http.Request) {
@@ -75,15 +94,27 @@ func (h *BannersHandler) Create(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 	out, err := h.ltBanners.CreateBanner(req.Context(), longterm.CreateBannerIn{
-		Username:   reqDto.Username,
-		BannerType: reqDto.BannerType,
+		Username:        reqDto.Username,
+		Kind:            reqDto.Kind,
+		BannerType:      reqDto.BannerType,
+		Layout:          reqDto.Layout,
+		Motion:          reqDto.Motion,
+		ManagementToken: managementToken(req),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, longterm.ErrUserDoesntExist):
 			h.error(rw, http.StatusNotFound, "user doesn't exist")
+		case errors.Is(err, longterm.ErrInvalidKind):
+			h.error(rw, http.StatusBadRequest, "invalid kind")
 		case errors.Is(err, longterm.ErrInvalidBannerType):
 			h.error(rw, http.StatusBadRequest, "invalid banner type")
+		case errors.Is(err, longterm.ErrInvalidLayout):
+			h.error(rw, http.StatusBadRequest, "invalid layout")
+		case errors.Is(err, longterm.ErrInvalidMotion):
+			h.error(rw, http.StatusBadRequest, "invalid motion")
+		case errors.Is(err, longterm.ErrForbidden):
+			h.error(rw, http.StatusForbidden, "valid management token is required")
 		case errors.Is(err, longterm.ErrCantCreateBanner):
 			h.logger.Error("failed to create long-term banner", "source", fn, "err", err)
 			h.error(rw, http.StatusInternalServerError, "can't create banner")
@@ -103,3 +134,112 @@ func (h *BannersHandler) Create(rw http.ResponseWriter, req *http.Request) {
 		h.error(rw, http.StatusInternalServerError, "can't create banner")
 	}
 }
+
+func (h *BannersHandler) Get(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.Get"
+	out, err := h.ltBanners.GetBanner(req.Context(), bannerUsername(req), chi.URLParam(req, "type"))
+	if err != nil {
+		switch {
+		case errors.Is(err, longterm.ErrInvalidBannerType):
+			h.error(rw, http.StatusBadRequest, "invalid banner type")
+		case errors.Is(err, longterm.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, longterm.ErrBannerNotFound):
+			h.error(rw, http.StatusNotFound, "banner not found")
+		case errors.Is(err, longterm.ErrCantGetBanner):
+			h.logger.Error("failed to get long-term banner", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't get banner")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't get banner")
+		}
+		return
+	}
+
+	h.json(rw, NewBannerResponse(out))
+}
+
+func (h *BannersHandler) List(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.List"
+	query := req.URL.Query()
+	in := longterm.ListBannersIn{Username: query.Get("username")}
+
+	var err error
+	if v := query.Get("limit"); v != "" {
+		if in.Limit, err = strconv.Atoi(v); err != nil {
+			h.error(rw, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if in.Offset, err = strconv.Atoi(v); err != nil {
+			h.error(rw, http.StatusBadRequest, "invalid offset")
+			return
+		}
+	}
+
+	out, err := h.ltBanners.ListBanners(req.Context(), in)
+	if err != nil {
+		switch {
+		case errors.Is(err, longterm.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, longterm.ErrCantGetBanner):
+			h.logger.Error("failed to list long-term banners", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't list banners")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't list banners")
+		}
+		return
+	}
+
+	resDto := ListBannersResponse{
+		Banners: make([]BannerResponse, 0, len(out.Banners)),
+		Total:   out.Total,
+		Limit:   out.Limit,
+		Offset:  out.Offset,
+	}
+	for _, b := range out.Banners {
+		resDto.Banners = append(resDto.Banners, NewBannerResponse(b))
+	}
+	h.json(rw, resDto)
+}
+
+func (h *BannersHandler) Delete(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.Delete"
+	err := h.ltBanners.DeleteBanner(req.Context(), longterm.DeleteBannerIn{
+		Username:        bannerUsername(req),
+		BannerType:      chi.URLParam(req, "type"),
+		ManagementToken: managementToken(req),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, longterm.ErrInvalidBannerType):
+			h.error(rw, http.StatusBadRequest, "invalid banner type")
+		case errors.Is(err, longterm.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, longterm.ErrBannerNotFound):
+			h.error(rw, http.StatusNotFound, "banner not found")
+		case errors.Is(err, longterm.ErrForbidden):
+			h.error(rw, http.StatusForbidden, "valid management token is required")
+		case errors.Is(err, longterm.ErrCantDeleteBanner):
+			h.logger.Error("failed to delete long-term banner", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't delete banner")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't delete banner")
+		}
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// bannerUsername returns username of the banner from the route
+// repositories' banners are routed with owner and repository's name, they are joined into full name
+func bannerUsername(req *http.Request) string {
+	if owner := chi.URLParam(req, "owner"); owner != "" {
+		return owner + "/" + chi.URLParam(req, "repo")
+	}
+	return chi.URLParam(req, "username")
+}
@@ -1,10 +1,115 @@
 package handlers
 
+import (
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	longterm "github.com/hurtki/github-banners/api/internal/domain/long-term"
+)
+
 type CreateBannerRequest struct {
 	Username   string `json:"username"`
+	Kind       string `json:"kind"`
 	BannerType string `json:"type"`
+	Layout     string `json:"layout"`
+	Motion     string `json:"motion"`
 }
 
 type CreateBannerResponse struct {
 	BannerUrlPath string `json:"url"`
 }
+
+type BannerResponse struct {
+	Username              string     `json:"username"`
+	Kind                  string     `json:"kind"`
+	BannerType            string     `json:"type"`
+	Layout                string     `json:"layout"`
+	Motion                string     `json:"motion"`
+	BannerUrlPath         string     `json:"url"`
+	Active                bool       `json:"active"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	LastRenderRequestedAt *time.Time `json:"last_render_requested_at"`
+}
+
+func NewBannerResponse(out longterm.BannerOut) BannerResponse {
+	return BannerResponse{
+		Username:              out.Username,
+		Kind:                  out.Kind,
+		BannerType:            out.BannerType,
+		Layout:                out.Layout,
+		Motion:                out.Motion,
+		BannerUrlPath:         out.BannerUrlPath,
+		Active:                out.Active,
+		CreatedAt:             out.CreatedAt,
+		UpdatedAt:             out.UpdatedAt,
+		LastRenderRequestedAt: out.LastRenderRequestedAt,
+	}
+}
+
+type ListBannersResponse struct {
+	Banners []BannerResponse `json:"banners"`
+	Total   int              `json:"total"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+type ChallengeResponse struct {
+	Challenge    string    `json:"challenge"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Instructions string    `json:"instructions"`
+}
+
+type VerifyOwnershipResponse struct {
+	ManagementToken string `json:"management_token"`
+}
+
+type AddGithubTokenRequest struct {
+	// host of GitHub Enterprise Server, blank is the default host
+	Host  string `json:"host"`
+	Token string `json:"token"`
+}
+
+type GithubTokenResponse struct {
+	ID               string     `json:"id"`
+	Host             string     `json:"host"`
+	Token            string     `json:"token"`
+	Source           string     `json:"source"`
+	Remaining        int        `json:"remaining"`
+	ResetsAt         time.Time  `json:"resets_at"`
+	GraphQLRemaining int        `json:"graphql_remaining"`
+	GraphQLResetsAt  time.Time  `json:"graphql_resets_at"`
+	CooldownUntil    *time.Time `json:"cooldown_until"`
+	Quarantined      bool       `json:"quarantined"`
+	Disabled         bool       `json:"disabled"`
+}
+
+// NewGithubTokenResponse shows token only masked
+func NewGithubTokenResponse(state domain.GithubTokenState) GithubTokenResponse {
+	return GithubTokenResponse{
+		ID:               state.ID,
+		Host:             state.Host,
+		Token:            state.Masked,
+		Source:           string(state.Source),
+		Remaining:        state.Remaining,
+		ResetsAt:         state.ResetsAt,
+		GraphQLRemaining: state.GraphQLRemaining,
+		GraphQLResetsAt:  state.GraphQLResetsAt,
+		CooldownUntil:    state.CooldownUntil,
+		Quarantined:      state.Quarantined,
+		Disabled:         state.Disabled,
+	}
+}
+
+type ListGithubTokensResponse struct {
+	Tokens []GithubTokenResponse `json:"tokens"`
+}
+
+// GithubWebhookPayload is the part of github event, that is used: owner of repository
+type GithubWebhookPayload struct {
+	Repository *struct {
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
@@ -3,12 +3,19 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
 )
 
+// responder writes json responses, it's embedded by handlers, so they don't depend on each other
+type responder struct {
+	logger logger.Logger
+}
+
 // errror is used to write error in json
 // if error, when marshaling appears, handles and logs it
-func (h *BannersHandler) error(rw http.ResponseWriter, statusCode int, message string) {
-	fn := "internal.handlers.BannersHandler.error"
+func (h responder) error(rw http.ResponseWriter, statusCode int, message string) {
+	fn := "internal.handlers.responder.error"
 	rw.Header().Set("Content-Type", "application/json")
 	res, err := json.Marshal(map[string]string{"error": message})
 
@@ -29,3 +36,20 @@ func (h *BannersHandler) error(rw http.ResponseWriter, statusCode int, message s
 		return
 	}
 }
+
+// json writes 200 response with dto encoded in json
+func (h responder) json(rw http.ResponseWriter, dto any) {
+	fn := "internal.handlers.responder.json"
+	res, err := json.Marshal(dto)
+	if err != nil {
+		h.logger.Error("can't marshal response", "dto", dto, "err", err, "source", fn)
+		h.error(rw, http.StatusInternalServerError, "server error occurred")
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(res); err != nil {
+		h.logger.Warn("can't write response", "err", err, "source", fn)
+	}
+}
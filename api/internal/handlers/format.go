@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// requestedFormat returns banner's format from "format" query parameter
+// if it's not set, the first of supported media types in Accept header is used
+// blank format is returned, if client doesn't ask for any, so usecase uses the default one
+func requestedFormat(req *http.Request) string {
+	if f := req.URL.Query().Get("format"); f != "" {
+		return f
+	}
+
+	for part := range strings.SplitSeq(req.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for name, format := range domain.BannerFormats {
+			if mediaType == format.ContentType() {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/api/internal/domain/ownership"
+)
+
+type OwnershipUsecase interface {
+	CreateChallenge(ctx context.Context, username string) (ownership.ChallengeOut, error)
+	Verify(ctx context.Context, username string) (ownership.VerifyOut, error)
+}
+
+func (h *BannersHandler) CreateChallenge(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.CreateChallenge"
+	out, err := h.ownership.CreateChallenge(req.Context(), chi.URLParam(req, "username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ownership.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, ownership.ErrCantCreateChallenge):
+			h.logger.Error("failed to create ownership challenge", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't create challenge")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't create challenge")
+		}
+		return
+	}
+
+	h.json(rw, ChallengeResponse{
+		Challenge:    out.Challenge,
+		ExpiresAt:    out.ExpiresAt,
+		Instructions: "publish a public gist with the challenge in its description or file name, or put the challenge into your profile README, then call verify endpoint",
+	})
+}
+
+func (h *BannersHandler) VerifyOwnership(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.VerifyOwnership"
+	out, err := h.ownership.Verify(req.Context(), chi.URLParam(req, "username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ownership.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, ownership.ErrNoChallenge):
+			h.error(rw, http.StatusNotFound, "challenge not found")
+		case errors.Is(err, ownership.ErrChallengeExpired):
+			h.error(rw, http.StatusGone, "challenge expired")
+		case errors.Is(err, ownership.ErrProofNotFound):
+			h.error(rw, http.StatusForbidden, "challenge wasn't found in user's gists or profile README")
+		case errors.Is(err, ownership.ErrCantVerifyOwner):
+			h.logger.Error("failed to verify owner", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't verify owner")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't verify owner")
+		}
+		return
+	}
+
+	h.json(rw, VerifyOwnershipResponse{ManagementToken: out.ManagementToken})
+}
+
+// managementToken gets token from "Authorization: Bearer <token>" header
+func managementToken(req *http.Request) string {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
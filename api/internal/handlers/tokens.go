@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/domain/tokens"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+type TokensUsecase interface {
+	List(ctx context.Context) []domain.GithubTokenState
+	Add(ctx context.Context, in tokens.AddTokenIn) (domain.GithubTokenState, error)
+	Disable(ctx context.Context, id string) error
+}
+
+// MissingCache is negative cache of usernames, that github doesn't have
+type MissingCache interface {
+	// Clear forgets username of every kind and repositories, that it owns
+	Clear(username string)
+}
+
+// AdminHandler manages github tokens pool and negative cache, every request should have "Authorization: Bearer <admin token>"
+type AdminHandler struct {
+	responder
+	tokens     TokensUsecase
+	missing    MissingCache
+	adminToken string
+}
+
+func NewAdminHandler(logger logger.Logger, tokensUsecase TokensUsecase, missingCache MissingCache, adminToken string) *AdminHandler {
+	return &AdminHandler{
+		responder:  responder{logger: logger.With("service", "admin-handler")},
+		tokens:     tokensUsecase,
+		missing:    missingCache,
+		adminToken: adminToken,
+	}
+}
+
+// Authorize is a middleware, that rejects requests without valid admin token
+// blank admin token rejects all the requests
+func (h *AdminHandler) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		token := managementToken(req)
+		if h.adminToken == "" || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			h.error(rw, http.StatusUnauthorized, "valid admin token is required")
+			return
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func (h *AdminHandler) ListTokens(rw http.ResponseWriter, req *http.Request) {
+	states := h.tokens.List(req.Context())
+	res := make([]GithubTokenResponse, 0, len(states))
+	for _, state := range states {
+		res = append(res, NewGithubTokenResponse(state))
+	}
+	h.json(rw, ListGithubTokensResponse{Tokens: res})
+}
+
+func (h *AdminHandler) AddToken(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.AdminHandler.AddToken"
+	reqDto := AddGithubTokenRequest{}
+	defer req.Body.Close()
+	if err := json.NewDecoder(req.Body).Decode(&reqDto); err != nil {
+		h.error(rw, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	state, err := h.tokens.Add(req.Context(), tokens.AddTokenIn{Host: reqDto.Host, Token: reqDto.Token})
+	if err != nil {
+		switch {
+		case errors.Is(err, tokens.ErrInvalidInputs):
+			h.error(rw, http.StatusBadRequest, "invalid inputs")
+		case errors.Is(err, tokens.ErrTokenExists):
+			h.error(rw, http.StatusConflict, "token is already used")
+		case errors.Is(err, tokens.ErrTokenRejected):
+			h.error(rw, http.StatusUnprocessableEntity, "token was rejected by github")
+		case errors.Is(err, tokens.ErrCantAddToken):
+			h.logger.Error("failed to add github token", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't add token")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't add token")
+		}
+		return
+	}
+	h.json(rw, NewGithubTokenResponse(state))
+}
+
+func (h *AdminHandler) DisableToken(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.AdminHandler.DisableToken"
+	err := h.tokens.Disable(req.Context(), chi.URLParam(req, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, tokens.ErrTokenNotFound):
+			h.error(rw, http.StatusNotFound, "token not found")
+		case errors.Is(err, tokens.ErrCantDisableToken):
+			h.logger.Error("failed to disable github token", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't disable token")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't disable token")
+		}
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// ForgetMissing removes username from negative cache, so it's fetched from github on the next request
+func (h *AdminHandler) ForgetMissing(rw http.ResponseWriter, req *http.Request) {
+	username := chi.URLParam(req, "username")
+	if username == "" {
+		h.error(rw, http.StatusBadRequest, "invalid username")
+		return
+	}
+	h.missing.Clear(username)
+	rw.WriteHeader(http.StatusNoContent)
+}
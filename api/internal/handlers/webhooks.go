@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// maxWebhookPayload is the biggest payload, that github delivers
+const maxWebhookPayload = 25 << 20
+
+// refreshEvents are github events, that change stats of repository's owner
+var refreshEvents = map[string]bool{
+	"push":       true,
+	"star":       true,
+	"fork":       true,
+	"repository": true,
+	"public":     true,
+}
+
+type RefreshScheduler interface {
+	// Push schedules refresh of owner's banners, returns false, if it's already scheduled
+	Push(owner string) bool
+}
+
+// WebhooksHandler receives github webhooks, every delivery should be signed with webhook secret
+type WebhooksHandler struct {
+	responder
+	refresh RefreshScheduler
+	secret  []byte
+}
+
+func NewWebhooksHandler(logger logger.Logger, refreshScheduler RefreshScheduler, secret string) *WebhooksHandler {
+	return &WebhooksHandler{
+		responder: responder{logger: logger.With("service", "webhooks-handler")},
+		refresh:   refreshScheduler,
+		secret:    []byte(secret),
+	}
+}
+
+// Github handles events of repositories: refresh of repository's owner is scheduled and 202 is returned
+// other events ( like ping ) are acknowledged with 204
+func (h *WebhooksHandler) Github(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.WebhooksHandler.Github"
+	defer req.Body.Close()
+	payload, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxWebhookPayload))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.error(rw, http.StatusRequestEntityTooLarge, "payload is too large")
+			return
+		}
+		h.error(rw, http.StatusBadRequest, "can't read payload")
+		return
+	}
+
+	if !h.validSignature(payload, req.Header.Get("X-Hub-Signature-256")) {
+		h.error(rw, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	event := req.Header.Get("X-GitHub-Event")
+	if !refreshEvents[event] {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	reqDto := GithubWebhookPayload{}
+	if err := json.Unmarshal(payload, &reqDto); err != nil {
+		h.error(rw, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if reqDto.Repository == nil || reqDto.Repository.Owner.Login == "" {
+		h.error(rw, http.StatusBadRequest, "event has no repository owner")
+		return
+	}
+
+	// deliveries of GitHub Enterprise Server name their host, its usernames are namespaced by it
+	host := strings.ToLower(req.Header.Get("X-GitHub-Enterprise-Host"))
+	owner := domain.JoinGithubHost(host, reqDto.Repository.Owner.Login)
+	scheduled := h.refresh.Push(owner)
+	h.logger.Debug("github event received", "source", fn, "event", event, "owner", owner, "scheduled", scheduled)
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// validSignature checks header "sha256=<hex hmac of payload>"
+func (h *WebhooksHandler) validSignature(payload []byte, header string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
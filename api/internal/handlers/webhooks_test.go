@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const webhookSecret = "webhook-secret"
+
+type LoggerMock struct{}
+
+func (m LoggerMock) Debug(a string, b ...any)    {}
+func (m LoggerMock) Info(a string, b ...any)     {}
+func (m LoggerMock) Warn(a string, b ...any)     {}
+func (m LoggerMock) Error(a string, b ...any)    {}
+func (m LoggerMock) With(a ...any) logger.Logger { return m }
+
+type schedulerFake struct {
+	pushed []string
+}
+
+func (s *schedulerFake) Push(owner string) bool {
+	s.pushed = append(s.pushed, owner)
+	return true
+}
+
+func signPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookRequest(event, payload, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	return req
+}
+
+const pushPayload = `{"ref":"refs/heads/main","repository":{"full_name":"HurtKi/github-banners","owner":{"login":"HurtKi"}}}`
+
+func TestGithubWebhookRefreshesOwner(t *testing.T) {
+	scheduler := &schedulerFake{}
+	h := NewWebhooksHandler(LoggerMock{}, scheduler, webhookSecret)
+
+	rec := httptest.NewRecorder()
+	h.Github(rec, webhookRequest("push", pushPayload, signPayload(webhookSecret, pushPayload)))
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Equal(t, []string{"HurtKi"}, scheduler.pushed)
+}
+
+func TestGithubWebhookEnterpriseHost(t *testing.T) {
+	scheduler := &schedulerFake{}
+	h := NewWebhooksHandler(LoggerMock{}, scheduler, webhookSecret)
+
+	req := webhookRequest("push", pushPayload, signPayload(webhookSecret, pushPayload))
+	req.Header.Set("X-GitHub-Enterprise-Host", "GitHub.Example.com")
+	rec := httptest.NewRecorder()
+	h.Github(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Equal(t, []string{"github.example.com:HurtKi"}, scheduler.pushed)
+}
+
+func TestGithubWebhookIgnoredEvent(t *testing.T) {
+	scheduler := &schedulerFake{}
+	h := NewWebhooksHandler(LoggerMock{}, scheduler, webhookSecret)
+	payload := `{"zen":"Keep it logically awesome.","hook_id":1}`
+
+	rec := httptest.NewRecorder()
+	h.Github(rec, webhookRequest("ping", payload, signPayload(webhookSecret, payload)))
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, scheduler.pushed)
+}
+
+func TestGithubWebhookInvalidSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing", signature: ""},
+		{name: "without prefix", signature: strings.TrimPrefix(signPayload(webhookSecret, pushPayload), "sha256=")},
+		{name: "sha1", signature: "sha1=" + strings.TrimPrefix(signPayload(webhookSecret, pushPayload), "sha256=")},
+		{name: "not hex", signature: "sha256=zzzz"},
+		{name: "other secret", signature: signPayload("other-secret", pushPayload)},
+		{name: "other payload", signature: signPayload(webhookSecret, `{"repository":{"owner":{"login":"other"}}}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := &schedulerFake{}
+			h := NewWebhooksHandler(LoggerMock{}, scheduler, webhookSecret)
+
+			rec := httptest.NewRecorder()
+			h.Github(rec, webhookRequest("push", pushPayload, tt.signature))
+
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+			require.Empty(t, scheduler.pushed)
+		})
+	}
+}
+
+func TestGithubWebhookInvalidPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "invalid json", payload: `{"repository":`},
+		{name: "without repository", payload: `{"ref":"refs/heads/main"}`},
+		{name: "without owner", payload: `{"repository":{"full_name":"hurtki/github-banners"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := &schedulerFake{}
+			h := NewWebhooksHandler(LoggerMock{}, scheduler, webhookSecret)
+
+			rec := httptest.NewRecorder()
+			h.Github(rec, webhookRequest("push", tt.payload, signPayload(webhookSecret, tt.payload)))
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			require.Empty(t, scheduler.pushed)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// github accepts app's JWT for 10 minutes at most
+	appJWTLifetime = 9 * time.Minute
+	// issue time is set in the past, so clock drift with github doesn't make JWT invalid
+	appJWTClockDrift = time.Minute
+	// installation token lives an hour, it's refreshed this time before expiration
+	installationTokenEarlyExpiry = 5 * time.Minute
+	// timeout of requests, that exchange app's JWT
+	appRequestTimeout = 10 * time.Second
+)
+
+var ErrInvalidAppPrivateKey = errors.New("invalid github app private key, expected PEM encoded RSA key")
+
+// AppConfig describes GitHub App, that fetcher's clients are authenticated as
+type AppConfig struct {
+	ID int64
+	// path to PEM encoded private key of the app
+	PrivateKeyPath string
+	// installations, that get own client, empty list means all the installations of the app
+	InstallationIDs []int64
+}
+
+// appTokenSources returns one source of installation tokens per installation of the app
+// every installation becomes separate client with its own rate limit
+func appTokenSources(ctx context.Context, cfg AppConfig, newClient clientFactory) ([]namedTokenSource, error) {
+	pemKey, err := os.ReadFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read github app private key: %w", err)
+	}
+	key, err := parseAppPrivateKey(pemKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtSource := oauth2.ReuseTokenSourceWithExpiry(nil, &appJWTSource{appID: cfg.ID, key: key}, appJWTClockDrift)
+	appClient := newClient(oauth2.NewClient(context.Background(), jwtSource))
+
+	ids := cfg.InstallationIDs
+	if len(ids) == 0 {
+		ids, err = listInstallationIDs(ctx, appClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sources := make([]namedTokenSource, 0, len(ids))
+	for _, id := range ids {
+		sources = append(sources, namedTokenSource{
+			TokenSource: oauth2.ReuseTokenSourceWithExpiry(nil, &installationTokenSource{app: appClient, installationID: id}, installationTokenEarlyExpiry),
+			id:          "app-" + strconv.FormatInt(cfg.ID, 10) + "-" + strconv.FormatInt(id, 10),
+			name:        "app " + strconv.FormatInt(cfg.ID, 10) + " installation " + strconv.FormatInt(id, 10),
+			source:      domain.GithubTokenSourceApp,
+		})
+	}
+	return sources, nil
+}
+
+func listInstallationIDs(ctx context.Context, appClient *github.Client) ([]int64, error) {
+	ids := []int64{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		timeoutCtx, cancel := context.WithTimeout(ctx, appRequestTimeout)
+		installations, res, err := appClient.Apps.ListInstallations(timeoutCtx, opts)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("can't list github app installations: %w", err)
+		}
+		for _, installation := range installations {
+			ids = append(ids, installation.GetID())
+		}
+		if res.NextPage == 0 {
+			return ids, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+// parseAppPrivateKey parses private key, that github generates ( PKCS#1 ), or PKCS#8 one
+func parseAppPrivateKey(pemKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, ErrInvalidAppPrivateKey
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidAppPrivateKey
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidAppPrivateKey
+	}
+	return key, nil
+}
+
+// appJWTSource mints JWTs, that authenticate requests as the app itself
+type appJWTSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+}
+
+func (s *appJWTSource) Token() (*oauth2.Token, error) {
+	now := time.Now()
+	jwt, err := signAppJWT(s.appID, s.key, now)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: jwt, Expiry: now.Add(appJWTLifetime)}, nil
+}
+
+// signAppJWT returns RS256 signed JWT, issued by the app
+func signAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("can't sign github app jwt: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationTokenSource exchanges app's JWT for installation token
+type installationTokenSource struct {
+	app            *github.Client
+	installationID int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), appRequestTimeout)
+	defer cancel()
+
+	token, _, err := s.app.Apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't create token of github app installation %d: %w", s.installationID, err)
+	}
+	return &oauth2.Token{AccessToken: token.GetToken(), Expiry: token.GetExpiresAt().Time}, nil
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "app.pem")
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, pemKey, 0o600))
+	return key, path
+}
+
+func TestSignAppJWT(t *testing.T) {
+	key, _ := newAppKey(t)
+	now := time.Unix(1700000000, 0)
+
+	jwt, err := signAppJWT(42, key, now)
+	require.NoError(t, err)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	claims := map[string]any{}
+	require.NoError(t, json.Unmarshal(rawClaims, &claims))
+	require.Equal(t, "42", claims["iss"])
+	require.Equal(t, float64(now.Add(-time.Minute).Unix()), claims["iat"])
+	require.Equal(t, float64(now.Add(9*time.Minute).Unix()), claims["exp"])
+}
+
+func TestParseAppPrivateKeyInvalid(t *testing.T) {
+	_, err := parseAppPrivateKey([]byte("not a key"))
+	require.ErrorIs(t, err, ErrInvalidAppPrivateKey)
+}
+
+func TestAppInstallationsJoinClientsPool(t *testing.T) {
+	key, keyPath := newAppKey(t)
+	tokensCreated := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/installations", func(rw http.ResponseWriter, r *http.Request) {
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		fmt.Fprint(rw, `[{"id":5}]`)
+	})
+	mux.HandleFunc("POST /app/installations/5/access_tokens", func(rw http.ResponseWriter, r *http.Request) {
+		tokensCreated++
+		fmt.Fprintf(rw, `{"token":"installation-token","expires_at":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /rate_limit", func(rw http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer installation-token", r.Header.Get("Authorization"))
+		fmt.Fprintf(rw, `{"resources":{"core":{"limit":15000,"remaining":15000,"reset":%d}}}`, time.Now().Add(time.Hour).Unix())
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	newClient := func(httpClient *http.Client) *github.Client {
+		client := github.NewClient(httpClient)
+		client.BaseURL, _ = url.Parse(srv.URL + "/")
+		return client
+	}
+
+	sources, err := appTokenSources(context.Background(), AppConfig{ID: 42, PrivateKeyPath: keyPath}, newClient)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+
+	f := newFetcher(sources, "", newClient, &domain.ServiceConfig{RequestTimeout: time.Second}, logger.NewLogger("error", "json"))
+	require.Len(t, f.clients, 1)
+	require.Equal(t, 15000, f.clients[0].Remaining)
+
+	// token isn't created again until it's about to expire
+	require.NotNil(t, f.acquireClient(context.Background()))
+	_, _, err = f.clients[0].Client.RateLimit.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, tokensCreated)
+}
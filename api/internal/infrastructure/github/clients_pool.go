@@ -2,83 +2,255 @@ package github
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/google/go-github/v81/github"
 )
 
-// acquireClient finds client with at least one request available.
+// rateResource is a github's rate limit resource, every resource has its own budget per token
+type rateResource string
+
+const (
+	// coreResource is spent by REST api, one request costs one unit
+	coreResource rateResource = "core"
+	// graphqlResource is spent by GraphQL api, one query costs points, depending on its complexity
+	graphqlResource rateResource = "graphql"
+)
+
+// budget returns pointers to client's fields, that store rate limit info of resource
+// should be called with client's mutex locked
+func (cl *GithubClient) budget(resource rateResource) (remaining *int, resetsAt *time.Time) {
+	if resource == graphqlResource {
+		return &cl.GraphQLRemaining, &cl.GraphQLResetsAt
+	}
+	return &cl.Remaining, &cl.ResetsAt
+}
+
+const (
+	// cooldown after secondary rate limit, that came without Retry-After header
+	secondaryLimitCooldown = time.Minute
+	// cooldown after failed request of client's limits, so broken client isn't requested on every acquire
+	limitsErrorCooldown = 30 * time.Second
+)
+
+// acquireClient finds client with the most core requests available.
 // Acquires, that one request will be sent using it.
-// If all clients are out of requests, returns nil.
+// If all clients are out of requests, returns nil ( see acquire ).
 func (f *Fetcher) acquireClient(ctx context.Context) *GithubClient {
-	fn := "internal.infrastructure.github.Fetcher.findClient"
-	for _, cl := range f.clients {
-		cl.mu.Lock()
+	return f.acquire(ctx, coreResource, 1)
+}
 
-		if cl.Remaining > 0 {
-			cl.Remaining-- // acquiring one request
-			cl.mu.Unlock()
+// acquire finds client with the most units of resource available ( at least cost ) and acquires them,
+// so load is spread across the clients instead of draining them one by one.
+// Quarantined and disabled clients and clients in cooldown are skipped.
+// If all clients are out of units, it waits for the nearest reset or cooldown end up to ClientWaitTimeout
+// ( and context's deadline ), returns nil, if there is nothing to wait for or it's too far.
+func (f *Fetcher) acquire(ctx context.Context, resource rateResource, cost int) *GithubClient {
+	var waitDeadline time.Time
+	if f.config.ClientWaitTimeout > 0 {
+		waitDeadline = time.Now().Add(f.config.ClientWaitTimeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(waitDeadline) {
+			waitDeadline = ctxDeadline
+		}
+	}
 
+	for {
+		cl, availableAt := f.pick(ctx, resource, cost)
+		if cl != nil {
 			return cl
 		}
-		// if the ResetTime we store is already in past
-		// then updating Remaining field
-		if cl.ResetsAt.Before(time.Now().UTC()) { // github returns ResetsAt header at UTC
-			// here unlock to do net call
-			cl.mu.Unlock()
-
-			ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
-			rl, _, err := cl.Client.RateLimit.Get(ctx)
-			cancel()
-			if err != nil {
-				f.logger.Error("found client, that its Reset time is before Now(), error occurred when getting its rate limit, skipping", "err", err, "source", fn)
-				continue
+		if waitDeadline.IsZero() || availableAt.IsZero() || availableAt.After(waitDeadline) {
+			return nil
+		}
+
+		// reset time of github can be a bit behind local clock, so waiting at least a second before asking again
+		timer := time.NewTimer(max(time.Until(availableAt), time.Second))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// pick acquires cost units on the client with the most units remaining
+// if no client has enough units, returns nil and the nearest time, when some client can have them ( zero, if never )
+func (f *Fetcher) pick(ctx context.Context, resource rateResource, cost int) (*GithubClient, time.Time) {
+	for {
+		best, bestRemaining := (*GithubClient)(nil), -1
+		var availableAt time.Time
+		for _, cl := range f.snapshot() {
+			remaining, nextAt := f.available(ctx, cl, resource, cost)
+			if remaining >= cost && remaining > bestRemaining {
+				best, bestRemaining = cl, remaining
 			}
-			// after net call, we are having new source of truth
-			// locking mutex for changes
-			cl.mu.Lock()
-
-			cl.ResetsAt = rl.Core.Reset.Time
-			cl.Remaining = rl.Core.Remaining
-			if cl.Remaining > 0 {
-				cl.Remaining-- // acquires one request for client
-				cl.mu.Unlock()
-				return cl
+			if remaining < cost && !nextAt.IsZero() && (availableAt.IsZero() || nextAt.Before(availableAt)) {
+				availableAt = nextAt
 			}
-			// if new Remaining is still 0, then continuing with next client
-			cl.mu.Unlock()
-			continue
 		}
-		// if ResetTime is in future, unlock mutex to continue with next client
+		if best == nil {
+			return nil, availableAt
+		}
+
+		best.mu.Lock()
+		remaining, _ := best.budget(resource)
+		if *remaining >= cost {
+			*remaining -= cost // acquiring units for one request
+			best.mu.Unlock()
+			return best, time.Time{}
+		}
+		// units were acquired by concurrent request, between the scan and now, so scanning again
+		best.mu.Unlock()
+	}
+}
+
+// available returns units of resource, that client has remaining, and time, when it can have them, if it's unusable now
+// limits of client are requested again, if their reset time is already in past
+func (f *Fetcher) available(ctx context.Context, cl *GithubClient, resource rateResource, cost int) (int, time.Time) {
+	fn := "internal.infrastructure.github.Fetcher.available"
+	cl.mu.Lock()
+	if cl.Quarantined || cl.Disabled {
+		cl.mu.Unlock()
+		return 0, time.Time{}
+	}
+	if now := time.Now(); now.Before(cl.CooldownUntil) {
+		cooldownUntil := cl.CooldownUntil
+		cl.mu.Unlock()
+		return 0, cooldownUntil
+	}
+	remaining, resetsAt := cl.budget(resource)
+	// if the ResetTime we store is already in past
+	// then updating Remaining field
+	if *remaining >= cost || resetsAt.After(time.Now().UTC()) { // github returns ResetsAt header at UTC
+		units, nextAt := *remaining, *resetsAt
 		cl.mu.Unlock()
+		return units, nextAt
+	}
+	// here unlock to do net call
+	cl.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
+	rl, res, err := cl.Client.RateLimit.Get(ctx)
+	cancel()
+	if err != nil {
+		f.logger.Error("found client, that its Reset time is before Now(), error occurred when getting its rate limit, skipping", "err", err, "resource", resource, "source", fn)
+		f.updateClientWithDoneResponse(cl, res, err)
+		cl.mu.Lock()
+		defer cl.mu.Unlock()
+		if cl.Quarantined {
+			return 0, time.Time{}
+		}
+		if cl.CooldownUntil.Before(time.Now()) {
+			cl.CooldownUntil = time.Now().Add(limitsErrorCooldown)
+		}
+		return 0, cl.CooldownUntil
+	}
+	// after net call, we are having new source of truth
+	// locking mutex for changes
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	setClientLimits(cl, rl)
+	return *remaining, *resetsAt
+}
+
+// setClientLimits copies budgets of all the tracked resources from rate limits response
+// should be called with client's mutex locked
+func setClientLimits(cl *GithubClient, rl *github.RateLimits) {
+	if rl == nil {
+		return
+	}
+	if rl.Core != nil {
+		cl.Remaining = rl.Core.Remaining
+		cl.ResetsAt = rl.Core.Reset.Time
+	}
+	if rl.GraphQL != nil {
+		cl.GraphQLRemaining = rl.GraphQL.Remaining
+		cl.GraphQLResetsAt = rl.GraphQL.Reset.Time
 	}
-	return nil
 }
 
-// UpdateClientWithResponse tries to get rate limit headers from response.
+// updateClientWithDoneResponse tries to get rate limit headers from response.
 // Updates client's fields using this reponse's headers.
-func (f *Fetcher) updateClientWithDoneResponse(cl *GithubClient, githubRes *github.Response) {
+// Headers are applied to the budget of resource, that github reports in X-RateLimit-Resource.
+// Error of the request updates client's health: secondary rate limit puts client into cooldown,
+// 401 response quarantines it.
+func (f *Fetcher) updateClientWithDoneResponse(cl *GithubClient, githubRes *github.Response, reqErr error) {
+	f.updateClientHealth(cl, reqErr)
 	if githubRes == nil || githubRes.Response == nil {
 		return
 	}
 	res := githubRes.Response
 
+	resource := coreResource
+	if res.Header.Get("X-RateLimit-Resource") == string(graphqlResource) {
+		resource = graphqlResource
+	}
+
 	cl.mu.Lock()
 	defer cl.mu.Unlock()
+	remaining, resetsAt := cl.budget(resource)
 
 	resetUnix, err := strconv.ParseInt(
 		res.Header.Get("X-RateLimit-Reset"), 10, 64,
 	)
 	if err == nil {
-		cl.ResetsAt = time.Unix(resetUnix, 0)
+		*resetsAt = time.Unix(resetUnix, 0)
 	}
 
-	remaining, err := strconv.ParseInt(
+	parsedRemaining, err := strconv.ParseInt(
 		res.Header.Get("X-RateLimit-Remaining"), 10, 64,
 	)
 	if err == nil {
-		cl.Remaining = int(remaining)
+		*remaining = int(parsedRemaining)
 	}
 
 }
+
+// updateClientHealth puts client into cooldown or quarantine by error of its request
+// primary rate limit isn't handled here, it's tracked by headers of the response
+func (f *Fetcher) updateClientHealth(cl *GithubClient, err error) {
+	fn := "internal.infrastructure.github.Fetcher.updateClientHealth"
+	if err == nil {
+		return
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		cooldown := secondaryLimitCooldown
+		if abuseErr.RetryAfter != nil && *abuseErr.RetryAfter > 0 {
+			cooldown = *abuseErr.RetryAfter
+		}
+		f.cooldownClient(cl, cooldown, fn)
+		return
+	}
+
+	var resErr *github.ErrorResponse
+	if !errors.As(err, &resErr) || resErr.Response == nil {
+		return
+	}
+	switch status := resErr.Response.StatusCode; {
+	case status == http.StatusUnauthorized:
+		cl.mu.Lock()
+		cl.Quarantined = true
+		cl.mu.Unlock()
+		f.logger.Error("client's token was rejected, quarantining it", "source", fn)
+	// go-github recognizes secondary rate limit only by documentation url of 403 response
+	case status == http.StatusTooManyRequests || status == http.StatusForbidden && resErr.Response.Header.Get("Retry-After") != "":
+		cooldown := secondaryLimitCooldown
+		if seconds, err := strconv.Atoi(resErr.Response.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			cooldown = time.Duration(seconds) * time.Second
+		}
+		f.cooldownClient(cl, cooldown, fn)
+	}
+}
+
+func (f *Fetcher) cooldownClient(cl *GithubClient, cooldown time.Duration, fn string) {
+	cl.mu.Lock()
+	cl.CooldownUntil = time.Now().Add(cooldown)
+	cl.mu.Unlock()
+	f.logger.Warn("client hit secondary rate limit, cooling it down", "cooldown", cooldown.String(), "source", fn)
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPool returns fetcher with clients, that have remaining core requests, all of them send requests to mux
+func newTestPool(t *testing.T, mux *http.ServeMux, remaining ...int) *Fetcher {
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	baseURL, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+
+	f := &Fetcher{
+		config: &domain.ServiceConfig{RequestTimeout: time.Second},
+		logger: logger.NewLogger("error", "json"),
+	}
+	for _, r := range remaining {
+		client := github.NewClient(srv.Client())
+		client.BaseURL = baseURL
+		f.clients = append(f.clients, &GithubClient{
+			Client:    client,
+			Remaining: r,
+			ResetsAt:  time.Now().Add(time.Hour),
+		})
+	}
+	return f
+}
+
+func TestAcquirePicksLeastLoadedClient(t *testing.T) {
+	f := newTestPool(t, http.NewServeMux(), 5, 10, 7)
+
+	cl := f.acquireClient(context.Background())
+	require.Same(t, f.clients[1], cl)
+	require.Equal(t, 9, cl.Remaining)
+
+	// cooling down and quarantined clients are skipped, even if they have more requests
+	f.clients[1].CooldownUntil = time.Now().Add(time.Minute)
+	f.clients[2].Quarantined = true
+	require.Same(t, f.clients[0], f.acquireClient(context.Background()))
+}
+
+func TestAcquireWithoutWaitFailsAtOnce(t *testing.T) {
+	f := newTestPool(t, http.NewServeMux(), 0)
+
+	require.Nil(t, f.acquireClient(context.Background()))
+}
+
+func TestAcquireWaitsForReset(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rate_limit", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(rw, `{"resources":{"core":{"limit":5000,"remaining":5000,"reset":%d}}}`, time.Now().Add(time.Hour).Unix())
+	})
+	f := newTestPool(t, mux, 0)
+	f.clients[0].ResetsAt = time.Now().Add(100 * time.Millisecond)
+	f.config.ClientWaitTimeout = 5 * time.Second
+
+	cl := f.acquireClient(context.Background())
+	require.Same(t, f.clients[0], cl)
+	require.Equal(t, 4999, cl.Remaining)
+
+	// reset, that is further than wait timeout, isn't waited for
+	cl.Remaining = 0
+	cl.ResetsAt = time.Now().Add(time.Hour)
+	require.Nil(t, f.acquireClient(context.Background()))
+}
+
+func TestSecondaryRateLimitCoolsClientDown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/abused", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Retry-After", "30")
+		rw.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(rw, `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`)
+	})
+	mux.HandleFunc("GET /users/throttled", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(rw, `{"message":"Too many requests"}`)
+	})
+	f := newTestPool(t, mux, 10, 10)
+
+	_, err := f.getConditional(context.Background(), "users/abused", "", &github.User{})
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+	cooling := f.clients[0]
+	require.WithinDuration(t, time.Now().Add(30*time.Second), cooling.CooldownUntil, 2*time.Second)
+
+	_, err = f.getConditional(context.Background(), "users/throttled", "", &github.User{})
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+	require.WithinDuration(t, time.Now().Add(secondaryLimitCooldown), f.clients[1].CooldownUntil, 2*time.Second)
+
+	require.Nil(t, f.acquireClient(context.Background()))
+}
+
+func TestUnauthorizedClientIsQuarantined(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(rw, `{"message":"Bad credentials"}`)
+	})
+	f := newTestPool(t, mux, 10, 5)
+
+	_, err := f.getConditional(context.Background(), "users/hurtki", "", &github.User{})
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+	require.True(t, f.clients[0].Quarantined)
+	require.Same(t, f.clients[1], f.acquireClient(context.Background()))
+}
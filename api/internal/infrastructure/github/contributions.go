@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// contributionsQueryCost is a cost of contributionsQuery in GraphQL points
+const contributionsQueryCost = 1
+
+// fetchContributions fetches contribution activity of user with GraphQL api, REST api doesn't have it
+func (f *Fetcher) fetchContributions(ctx context.Context, username string) (*domain.GithubContributions, error) {
+	var out contributionsResult
+	if err := f.queryGraphQL(ctx, contributionsQuery, map[string]any{"login": username}, contributionsQueryCost, &out); err != nil {
+		return nil, err
+	}
+	if out.User == nil || out.User.ContributionsCollection == nil {
+		return nil, domain.ErrNotFound
+	}
+	return out.User.ContributionsCollection.toDomain(), nil
+}
+
+// fetchContributionsOrPrevious doesn't fail the whole fetch, when contributions are unavailable
+// ( e.g. GraphQL points are over ), previous contributions are kept instead
+func (f *Fetcher) fetchContributionsOrPrevious(ctx context.Context, username string, previous *domain.GithubUserData) *domain.GithubContributions {
+	fn := "internal.infrastructure.github.Fetcher.fetchContributionsOrPrevious"
+
+	contributions, err := f.fetchContributions(ctx, username)
+	if err == nil {
+		return contributions
+	}
+	f.logger.Warn("can't fetch contributions, keeping previous ones", "username", username, "err", err, "source", fn)
+	if previous == nil {
+		return nil
+	}
+	return previous.Contributions
+}
+
+// toDomain converts contributions and counts streaks from the calendar, nil for nil contributions
+func (c *graphqlContributions) toDomain() *domain.GithubContributions {
+	if c == nil {
+		return nil
+	}
+
+	var days []domain.ContributionDay
+	for _, week := range c.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			date, err := time.Parse(time.DateOnly, day.Date)
+			if err != nil {
+				continue
+			}
+			days = append(days, domain.ContributionDay{Date: date, Count: day.ContributionCount})
+		}
+	}
+	current, longest := domain.CountStreaks(days)
+
+	return &domain.GithubContributions{
+		Total:         c.ContributionCalendar.TotalContributions,
+		Commits:       c.TotalCommitContributions,
+		PullRequests:  c.TotalPullRequestContributions,
+		Issues:        c.TotalIssueContributions,
+		Reviews:       c.TotalPullRequestReviewContributions,
+		CurrentStreak: current,
+		LongestStreak: longest,
+	}
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,86 +17,203 @@ import (
 
 type Fetcher struct {
 	clients []*GithubClient
-	config  *domain.ServiceConfig
-	logger  logger.Logger
+	// guards clients list, clients can be added at runtime
+	clientsMu sync.RWMutex
+	// host of the clients, blank for the default one
+	host      string
+	newClient clientFactory
+	config    *domain.ServiceConfig
+	logger    logger.Logger
 }
 
 // GithubClient is a wrap of GithubClient that contains rate limit info about client
 type GithubClient struct {
 	Client *github.Client
+	// id of client's token, see domain.GithubTokenID
+	ID string
+	// name is safe to be logged: masked token or installation of the app
+	Name   string
+	Source domain.GithubTokenSource
 	// Requests, that client has remaining, until limit
 	Remaining int
 	// time, when limit will reset
 	ResetsAt time.Time
+	// GraphQL points, that client has remaining, GraphQL api has its own budget
+	GraphQLRemaining int
+	// time, when GraphQL points will reset
+	GraphQLResetsAt time.Time
+	// client isn't used until this time, after secondary rate limit ( abuse detection ) or failed request of its limits
+	CooldownUntil time.Time
+	// client's token was rejected with 401 ( revoked or expired ), it isn't used anymore
+	Quarantined bool
+	// client was disabled by admin, it isn't used until it's added again
+	Disabled bool
 
-	// mutex for concurrent changes of Remaining and ResetsAt fields
+	// mutex for concurrent changes of client's fields
 	mu sync.Mutex
 }
 
 func NewFetcher(tokens []string, config *domain.ServiceConfig, logger logger.Logger) *Fetcher {
-	clients := []*GithubClient{}
-	initLogger := logger.With("service", "fetcher initialization function")
-	for _, token := range tokens {
-		var client *github.Client
+	return newFetcher(staticTokenSources("", tokens, domain.GithubTokenSourceEnv), "", github.NewClient, config, logger)
+}
+
+// NewEnterpriseFetcher returns fetcher, which clients are pointed at GitHub Enterprise Server
+// baseURL is a url of the instance ( "/api/v3/" suffix is added, if it's missing ), blank uploadURL is the same as baseURL
+// host is only used in logs, to tell clients of different instances apart
+func NewEnterpriseFetcher(tokens []string, host, baseURL, uploadURL string, config *domain.ServiceConfig, logger logger.Logger) (*Fetcher, error) {
+	newClient, err := enterpriseClientFactory(baseURL, uploadURL)
+	if err != nil {
+		return nil, err
+	}
+	return newFetcher(staticTokenSources(host, tokens, domain.GithubTokenSourceEnv), host, newClient, config, logger), nil
+}
+
+// clientFactory builds github client, that sends requests with httpClient, it's pointed at api url of the host
+type clientFactory func(httpClient *http.Client) *github.Client
+
+func enterpriseClientFactory(baseURL, uploadURL string) (clientFactory, error) {
+	if uploadURL == "" {
+		uploadURL = baseURL
+	}
+	// urls are checked once, before any token is used
+	if _, err := github.NewClient(nil).WithEnterpriseURLs(baseURL, uploadURL); err != nil {
+		return nil, fmt.Errorf("invalid github enterprise urls: %w", err)
+	}
+	return func(httpClient *http.Client) *github.Client {
+		client, _ := github.NewClient(httpClient).WithEnterpriseURLs(baseURL, uploadURL)
+		return client
+	}, nil
+}
+
+// namedTokenSource is a source of client's tokens, name is safe to be logged
+type namedTokenSource struct {
+	oauth2.TokenSource
+	id     string
+	name   string
+	source domain.GithubTokenSource
+}
 
+// staticTokenSources returns sources of personal access tokens of host, they are logged masked
+func staticTokenSources(host string, tokens []string, source domain.GithubTokenSource) []namedTokenSource {
+	sources := []namedTokenSource{}
+	for _, token := range tokens {
 		if token == "" {
 			continue
 		}
-		tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-		tokenClient := oauth2.NewClient(context.Background(), tokenSource)
-		client = github.NewClient(tokenClient)
+		sources = append(sources, namedTokenSource{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+			id:          domain.GithubTokenID(host, token),
+			name:        domain.MaskGithubToken(token),
+			source:      source,
+		})
+	}
+	return sources
+}
 
-		timeoutCtx, cancel := context.WithTimeout(context.Background(), time.Second*3)
-		clLimit, _, err := client.RateLimit.Get(timeoutCtx)
-		cancel()
-		if err != nil {
-			initLogger.Error("unexpected error, when getting client's rate limit, skipping it", "err", err)
-			continue
-		}
-		cl := &GithubClient{
-			Client:    client,
-			Remaining: clLimit.Core.Remaining,
-			ResetsAt:  clLimit.Core.Reset.Time,
-			mu:        sync.Mutex{},
-		}
-		initLogger.Info("new client created",
-			"remaining", cl.Remaining,
-			"resets_in", time.Until(cl.ResetsAt).String(),
-			"token", fmt.Sprintf("%s...", token[:len(token)/4]),
-		)
+func newFetcher(sources []namedTokenSource, host string, newClient clientFactory, config *domain.ServiceConfig, logger logger.Logger) *Fetcher {
+	fetcherLogger := logger.With("service", "github-fetcher")
+	if host != "" {
+		fetcherLogger = fetcherLogger.With("host", host)
+	}
+	f := &Fetcher{
+		host:      host,
+		newClient: newClient,
+		config:    config,
+		logger:    fetcherLogger,
+	}
 
-		clients = append(clients, cl)
+	initLogger := logger.With("service", "fetcher initialization function")
+	if host != "" {
+		initLogger = initLogger.With("host", host)
 	}
-	if len(clients) == 0 {
+	for _, source := range sources {
+		if _, err := f.addClient(context.Background(), source); err != nil {
+			initLogger.Error("unexpected error, when getting client's rate limit, skipping it", "err", err, "token", source.name)
+		}
+	}
+	if len(f.clients) == 0 {
 		initLogger.Warn("initialized Fetcher without clients")
 	} else {
-		initLogger.Info("initialized Fetcher", "clients count", len(clients))
+		initLogger.Info("initialized Fetcher", "clients count", len(f.clients))
 	}
+	return f
+}
+
+// addClient creates client, that is authenticated by source, and adds it to the pool
+// client's limits are requested first, so token, that github doesn't accept, isn't added
+func (f *Fetcher) addClient(ctx context.Context, source namedTokenSource) (*GithubClient, error) {
+	tokenClient := oauth2.NewClient(context.Background(), source)
+	client := f.newClient(tokenClient)
 
-	return &Fetcher{
-		clients: clients,
-		config:  config,
-		logger:  logger.With("service", "github-fetcher"),
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Second*3)
+	clLimit, _, err := client.RateLimit.Get(timeoutCtx)
+	cancel()
+	if err != nil {
+		var resErr *github.ErrorResponse
+		if errors.As(err, &resErr) && resErr.Response != nil && resErr.Response.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("%w: %w", domain.ErrTokenRejected, err)
+		}
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+	cl := &GithubClient{
+		Client: client,
+		ID:     source.id,
+		Name:   source.name,
+		Source: source.source,
+		mu:     sync.Mutex{},
 	}
+	setClientLimits(cl, clLimit)
+	f.logger.Info("new client created",
+		"remaining", cl.Remaining,
+		"resets_in", time.Until(cl.ResetsAt).String(),
+		"graphql_remaining", cl.GraphQLRemaining,
+		"token", source.name,
+	)
+
+	f.clientsMu.Lock()
+	f.clients = append(f.clients, cl)
+	f.clientsMu.Unlock()
+	return cl, nil
 }
 
-// FetchUser fetches the user data from GitHub
-func (f *Fetcher) fetchUser(ctx context.Context, username string) (*github.User, error) {
-	if username != url.PathEscape(username) {
-		return nil, domain.ErrNotFound
-	}
+// snapshot returns current list of clients, it can be iterated without holding the lock
+func (f *Fetcher) snapshot() []*GithubClient {
+	f.clientsMu.RLock()
+	defer f.clientsMu.RUnlock()
+	return f.clients
+}
+
+// reposPerPage is a size of repositories list page, max that github allows
+const reposPerPage = 100
 
+// getConditional sends GET request to github api and decodes response into v
+// if etag isn't blank, it's sent in If-None-Match header, on 304 response v stays untouched,
+// caller should check response's status code
+func (f *Fetcher) getConditional(ctx context.Context, urlStr string, etag string, v any) (*github.Response, error) {
+	fn := "internal.infrastructure.github.Fetcher.getConditional"
 	cl := f.acquireClient(ctx)
 	if cl == nil {
 		f.logger.Warn("can't find available client for github api request")
 		return nil, domain.ErrUnavailable
 	}
 
+	req, err := cl.Client.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		f.logger.Error("can't build github api request", "err", err, "source", fn)
+		return nil, domain.ErrUnavailable
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
 	defer cancel()
 
-	user, res, err := cl.Client.Users.Get(ctx, username)
-	f.updateClientWithDoneResponse(cl, res)
+	res, err := cl.Client.Do(ctx, req, v)
+	f.updateClientWithDoneResponse(cl, res, err)
+	if res != nil && res.StatusCode == http.StatusNotModified {
+		return res, nil
+	}
 	if err != nil {
 		if er, ok := err.(*github.ErrorResponse); ok {
 			if er.Response.StatusCode == http.StatusNotFound {
@@ -104,114 +222,237 @@ func (f *Fetcher) fetchUser(ctx context.Context, username string) (*github.User,
 		}
 		return nil, domain.ErrUnavailable
 	}
-
-	return user, err
+	return res, nil
 }
 
-// FetchRepositories fetches all repositories for a user (paginated)
-func (f *Fetcher) fetchRepositories(ctx context.Context, username string) ([]*github.Repository, error) {
+// fetchUser fetches the user data from GitHub
+// returns nil user, if github answered, that it wasn't modified since the etag
+func (f *Fetcher) fetchUser(ctx context.Context, username string, etag string) (*github.User, string, error) {
+	if username != url.PathEscape(username) {
+		return nil, "", domain.ErrNotFound
+	}
+
+	user := &github.User{}
+	res, err := f.getConditional(ctx, "users/"+username, etag, user)
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	return user, res.Header.Get("ETag"), nil
+}
 
+// fetchRepositories fetches all repositories for a user (paginated)
+// pages, that github answered with 304 for, are taken from previous data
+func (f *Fetcher) fetchRepositories(ctx context.Context, username string, previous *domain.GithubUserData) ([]domain.GithubRepository, []domain.GithubReposPageETag, error) {
 	if username != url.PathEscape(username) {
-		return nil, domain.ErrNotFound
-	}
-
-	var allRepos []*github.Repository
-	opts := &github.RepositoryListByUserOptions{
-		Type:        "owner",
-		Sort:        "updated",
-		ListOptions: github.ListOptions{PerPage: 100},
-	}
-
-	for {
-		// every page acquire a new client for one request
-		cl := f.acquireClient(ctx)
-		if cl == nil {
-			f.logger.Warn("can't find available client for github api request")
-			if allRepos == nil {
-				return nil, domain.ErrUnavailable
-			} else {
-				// even if we already collected couple repositories it shouldn't be returned
-				// because Fetcher is used as source of truth
-				return nil, domain.ErrUnavailable
-			}
+		return nil, nil, domain.ErrNotFound
+	}
+
+	var prevPages []domain.GithubReposPageETag
+	prevRepos := map[int64]domain.GithubRepository{}
+	if previous != nil {
+		if previous.ETags != nil {
+			prevPages = previous.ETags.RepoPages
+		}
+		for _, repo := range previous.Repositories {
+			prevRepos[repo.ID] = repo
 		}
-		timeoutCtx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
-		repos, resp, err := cl.Client.Repositories.ListByUser(timeoutCtx, username, opts)
-		cancel()
+	}
+
+	var allRepos []domain.GithubRepository
+	var pages []domain.GithubReposPageETag
 
-		f.updateClientWithDoneResponse(cl, resp)
+	for page := 1; ; page++ {
+		pageURL := fmt.Sprintf("users/%s/repos?type=owner&sort=updated&per_page=%d&page=%d", username, reposPerPage, page)
+		etag := ""
+		// page stored without its size has size less, than count of its repositories
+		// it's fetched again, because without size the last page can't be found on 304
+		if page <= len(prevPages) && prevPages[page-1].Size >= len(prevPages[page-1].RepoIDs) {
+			etag = prevPages[page-1].ETag
+		}
+
+		// every page acquires a new client for one request
+		// even if we already collected couple repositories they shouldn't be returned on error
+		// because Fetcher is used as source of truth
+		var repos []*github.Repository
+		res, err := f.getConditional(ctx, pageURL, etag, &repos)
 		if err != nil {
-			if er, ok := err.(*github.ErrorResponse); ok {
-				if er.Response.StatusCode == http.StatusNotFound {
-					return nil, domain.ErrNotFound
+			return nil, nil, err
+		}
+
+		if res.StatusCode == http.StatusNotModified {
+			if pageRepos, ok := reposByIDs(prevRepos, prevPages[page-1].RepoIDs); ok {
+				allRepos = append(allRepos, pageRepos...)
+				pages = append(pages, prevPages[page-1])
+				// Link header isn't covered by ETag, so next page is expected only after the full one
+				// size is compared, because skipped repositories aren't listed in RepoIDs
+				if prevPages[page-1].Size < reposPerPage {
+					break
 				}
+				continue
+			}
+			// stored data doesn't have some repositories of the page, so it's fetched again without ETag
+			res, err = f.getConditional(ctx, pageURL, "", &repos)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		pageETag := domain.GithubReposPageETag{ETag: res.Header.Get("ETag"), Size: len(repos)}
+		for _, repo := range repos {
+			domainRepo, ok := repoToDomain(repo)
+			if !ok {
+				continue
 			}
-			return nil, domain.ErrUnavailable
+			allRepos = append(allRepos, domainRepo)
+			pageETag.RepoIDs = append(pageETag.RepoIDs, domainRepo.ID)
 		}
+		pages = append(pages, pageETag)
 
-		allRepos = append(allRepos, repos...)
-		if resp.NextPage == 0 {
+		if res.NextPage == 0 {
 			break
 		}
-		opts.Page = resp.NextPage
 	}
-	return allRepos, nil
+	return allRepos, pages, nil
+}
+
+// reposByIDs returns repositories with given ids in the same order
+// false, if at least one of them is missing or was stored without name
+func reposByIDs(repos map[int64]domain.GithubRepository, ids []int64) ([]domain.GithubRepository, bool) {
+	res := make([]domain.GithubRepository, 0, len(ids))
+	for _, id := range ids {
+		repo, ok := repos[id]
+		if !ok || repo.Name == "" {
+			return nil, false
+		}
+		res = append(res, repo)
+	}
+	return res, true
+}
+
+// repoToDomain converts github repository, false if it has no owner
+func repoToDomain(repo *github.Repository) (domain.GithubRepository, bool) {
+	if repo == nil {
+		return domain.GithubRepository{}, false
+	}
+	owner := repo.GetOwner()
+	if owner == nil || owner.GetLogin() == "" {
+		return domain.GithubRepository{}, false
+	}
+
+	var pushedAt *time.Time = nil
+	var updatedAt *time.Time = nil
+	if repo.PushedAt != nil {
+		pushedAt = repo.PushedAt.GetTime()
+	}
+	if repo.UpdatedAt != nil {
+		updatedAt = repo.UpdatedAt.GetTime()
+	}
+
+	return domain.GithubRepository{
+		ID:            repo.GetID(),
+		OwnerUsername: owner.GetLogin(),
+		Name:          repo.GetName(),
+		PushedAt:      pushedAt,
+		UpdatedAt:     updatedAt,
+		Language:      repo.Language,
+		StarsCount:    repo.GetStargazersCount(),
+		Fork:          repo.GetFork(),
+		ForksCount:    repo.GetForksCount(),
+	}, true
+}
+
+// fillLanguages fetches bytes of code per language for not forked repositories
+// languages of repository, that wasn't pushed since previous fetch, are taken from previous data
+func (f *Fetcher) fillLanguages(ctx context.Context, repos []domain.GithubRepository, previous []domain.GithubRepository) error {
+	prevRepos := map[int64]domain.GithubRepository{}
+	for _, repo := range previous {
+		prevRepos[repo.ID] = repo
+	}
+
+	for i := range repos {
+		if repos[i].Fork {
+			continue
+		}
+		if prev, ok := prevRepos[repos[i].ID]; ok && prev.Languages != nil && samePushedAt(prev.PushedAt, repos[i].PushedAt) {
+			repos[i].Languages = prev.Languages
+			continue
+		}
+
+		languages := map[string]int{}
+		_, err := f.getConditional(ctx, fmt.Sprintf("repos/%s/%s/languages", url.PathEscape(repos[i].OwnerUsername), url.PathEscape(repos[i].Name)), "", &languages)
+		if errors.Is(err, domain.ErrNotFound) {
+			// repository was deleted or renamed after listing
+			repos[i].Languages = map[string]int{}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		repos[i].Languages = languages
+	}
+	return nil
+}
+
+func samePushedAt(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
 
-// FetchUserStats fetches user and repositories together
-func (f *Fetcher) FetchUserData(ctx context.Context, username string) (*domain.GithubUserData, error) {
-	user, err := f.fetchUser(ctx, username)
+// FetchUserData fetches user and repositories together
+// previous is a stored data of the user ( can be nil ), its ETags are sent in If-None-Match,
+// so parts of data, that weren't modified, cost no rate limit and are taken from it
+func (f *Fetcher) FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error) {
+	userETag := ""
+	if previous != nil && previous.ETags != nil {
+		userETag = previous.ETags.User
+	}
+
+	user, userETag, err := f.fetchUser(ctx, username, userETag)
 	if err != nil {
 		return nil, err
 	}
 
-	repos, err := f.fetchRepositories(ctx, username)
+	repos, pages, err := f.fetchRepositories(ctx, username, previous)
 	if err != nil {
 		return nil, err
 	}
 
-	domainRepos := make([]domain.GithubRepository, len(repos))
-	for i := range len(repos) {
-		if repos[i] == nil {
-			continue
+	if f.config.LanguagesMode == domain.LanguagesBytes {
+		var prevRepos []domain.GithubRepository
+		if previous != nil {
+			prevRepos = previous.Repositories
 		}
-		var pushedAt *time.Time = nil
-		var updatedAt *time.Time = nil
-		if repos[i].PushedAt != nil {
-			pushedAt = repos[i].PushedAt.GetTime()
-		}
-		if repos[i].UpdatedAt != nil {
-			updatedAt = repos[i].UpdatedAt.GetTime()
+		if err := f.fillLanguages(ctx, repos, prevRepos); err != nil {
+			return nil, err
 		}
+	}
 
-		owner := repos[i].GetOwner()
-		if owner == nil || owner.GetLogin() == "" {
-			continue
+	var data domain.GithubUserData
+	if user != nil {
+		data = domain.GithubUserData{
+			Username:    user.GetLogin(),
+			Bio:         user.Bio,
+			Name:        user.Name,
+			Company:     user.Company,
+			Location:    user.Location,
+			PublicRepos: user.GetPublicRepos(),
+			Followers:   user.GetFollowers(),
+			Following:   user.GetFollowing(),
 		}
+	} else {
+		// profile wasn't modified since previous fetch
+		data = *previous
+	}
 
-		domainRepos[i] = domain.GithubRepository{
-			ID:            repos[i].GetID(),
-			OwnerUsername: repos[i].GetOwner().GetLogin(),
-			PushedAt:      pushedAt,
-			UpdatedAt:     updatedAt,
-			Language:      repos[i].Language,
-			StarsCount:    repos[i].GetStargazersCount(),
-			Fork:          repos[i].GetFork(),
-			ForksCount:    repos[i].GetForksCount(),
-		}
-	}
-
-	return &domain.GithubUserData{
-		Username:     user.GetLogin(),
-		Bio:          user.Bio,
-		Name:         user.Name,
-		Company:      user.Company,
-		Location:     user.Location,
-		PublicRepos:  user.GetPublicRepos(),
-		Followers:    user.GetFollowers(),
-		Following:    user.GetFollowing(),
-		Repositories: domainRepos,
-		// sets the FetchedAt field to time when it was fetched
-		FetchedAt: time.Now(),
-	}, nil
+	data.Repositories = repos
+	data.ETags = &domain.GithubDataETags{User: userETag, RepoPages: pages}
+	data.Contributions = f.fetchContributionsOrPrevious(ctx, username, previous)
+	// sets the FetchedAt field to time when it was fetched
+	data.FetchedAt = time.Now()
+	return &data, nil
 }
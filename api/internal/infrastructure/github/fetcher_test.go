@@ -0,0 +1,286 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFetcher returns fetcher with one client, that sends requests to local stand-in of github api
+func newTestFetcher(t *testing.T, mux *http.ServeMux) *Fetcher {
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(srv.Client())
+	baseURL, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+
+	return &Fetcher{
+		clients: []*GithubClient{{
+			Client:           client,
+			Remaining:        10,
+			ResetsAt:         time.Now().Add(time.Hour),
+			GraphQLRemaining: 10,
+			GraphQLResetsAt:  time.Now().Add(time.Hour),
+		}},
+		config: &domain.ServiceConfig{RequestTimeout: time.Second},
+		logger: logger.NewLogger("error", "json"),
+	}
+}
+
+func TestFetcherFetchUserData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("ETag", `"user"`)
+		fmt.Fprint(rw, `{"login":"hurtki","name":"Hurt Ki","public_repos":2,"followers":5,"following":1}`)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-RateLimit-Resource", "core")
+		rw.Header().Set("X-RateLimit-Remaining", "7")
+		rw.Header().Set("ETag", `"page`+r.URL.Query().Get("page")+`"`)
+		if r.URL.Query().Get("page") == "1" {
+			rw.Header().Set("Link", fmt.Sprintf(`<http://%s/users/hurtki/repos?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(rw, `[{"id":1,"owner":{"login":"hurtki"},"language":"Go","stargazers_count":3,"forks_count":1}]`)
+			return
+		}
+		fmt.Fprint(rw, `[{"id":2,"owner":{"login":"hurtki"},"fork":true,"pushed_at":"2025-01-01T00:00:00Z"}]`)
+	})
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, graphqlContributionsResponse)
+	})
+	f := newTestFetcher(t, mux)
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.NoError(t, err)
+	require.Equal(t, "hurtki", data.Username)
+	require.Equal(t, "Hurt Ki", *data.Name)
+	require.Equal(t, 2, data.PublicRepos)
+	require.Equal(t, 5, data.Followers)
+	require.Len(t, data.Repositories, 2)
+	require.Equal(t, "Go", *data.Repositories[0].Language)
+	require.Equal(t, 3, data.Repositories[0].StarsCount)
+	require.True(t, data.Repositories[1].Fork)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *data.Repositories[1].PushedAt)
+
+	require.Equal(t, &domain.GithubDataETags{
+		User: `"user"`,
+		RepoPages: []domain.GithubReposPageETag{
+			{ETag: `"page1"`, RepoIDs: []int64{1}, Size: 1},
+			{ETag: `"page2"`, RepoIDs: []int64{2}, Size: 1},
+		},
+	}, data.ETags)
+	require.Equal(t, &domain.GithubContributions{
+		Total: 6, Commits: 4, PullRequests: 1, Issues: 1, CurrentStreak: 2, LongestStreak: 2,
+	}, data.Contributions)
+
+	// budgets are taken from the last responses of their resources
+	require.Equal(t, 7, f.clients[0].Remaining)
+	require.Equal(t, 8, f.clients[0].GraphQLRemaining)
+}
+
+func TestFetcherFetchUserDataContributionsUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"login":"hurtki"}`)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[]`)
+	})
+	f := newTestFetcher(t, mux)
+	f.clients[0].GraphQLRemaining = 0
+	previous := newStoredUserData()
+	previous.ETags = nil
+	previous.Contributions = &domain.GithubContributions{Total: 100, CurrentStreak: 3}
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	require.Equal(t, previous.Contributions, data.Contributions)
+}
+
+// newStoredUserData returns data, that was saved after the previous fetch
+func newStoredUserData() *domain.GithubUserData {
+	name := "Stored Name"
+	return &domain.GithubUserData{
+		Username:    "hurtki",
+		Name:        &name,
+		PublicRepos: 2,
+		Repositories: []domain.GithubRepository{
+			{ID: 1, OwnerUsername: "hurtki", Name: "banners", StarsCount: 10},
+			{ID: 2, OwnerUsername: "hurtki", Name: "dotfiles", StarsCount: 20},
+		},
+		ETags: &domain.GithubDataETags{
+			User:      `"user"`,
+			RepoPages: []domain.GithubReposPageETag{{ETag: `"page1"`, RepoIDs: []int64{2, 1}, Size: 2}},
+		},
+	}
+}
+
+func TestFetcherFetchUserDataNotModified(t *testing.T) {
+	var ifNoneMatch []string
+	mux := http.NewServeMux()
+	notModified := func(rw http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		rw.Header().Set("X-RateLimit-Remaining", "9")
+		rw.WriteHeader(http.StatusNotModified)
+	}
+	mux.HandleFunc("GET /users/hurtki", notModified)
+	mux.HandleFunc("GET /users/hurtki/repos", notModified)
+	f := newTestFetcher(t, mux)
+	previous := newStoredUserData()
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"user"`, `"page1"`}, ifNoneMatch)
+
+	// everything is taken from stored data, repositories keep order of the page
+	require.Equal(t, "Stored Name", *data.Name)
+	require.Equal(t, []domain.GithubRepository{previous.Repositories[1], previous.Repositories[0]}, data.Repositories)
+	require.Equal(t, previous.ETags, data.ETags)
+	require.False(t, data.FetchedAt.IsZero())
+	require.Equal(t, 9, f.clients[0].Remaining)
+}
+
+func TestFetcherFetchUserDataPageMissingInStoredData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("ETag", `"user2"`)
+		fmt.Fprint(rw, `{"login":"hurtki","name":"New Name"}`)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("ETag", `"page1"`)
+		fmt.Fprint(rw, `[{"id":2,"owner":{"login":"hurtki"},"stargazers_count":21},{"id":1,"owner":{"login":"hurtki"}}]`)
+	})
+	f := newTestFetcher(t, mux)
+	previous := newStoredUserData()
+	// stored data lost one of the repositories of the page
+	previous.Repositories = previous.Repositories[:1]
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	require.Equal(t, "New Name", *data.Name)
+	require.Len(t, data.Repositories, 2)
+	require.Equal(t, 21, data.Repositories[0].StarsCount)
+	require.Equal(t, &domain.GithubDataETags{
+		User:      `"user2"`,
+		RepoPages: []domain.GithubReposPageETag{{ETag: `"page1"`, RepoIDs: []int64{2, 1}, Size: 2}},
+	}, data.ETags)
+}
+
+func TestFetcherFetchUserDataNotModifiedPageWithSkippedRepos(t *testing.T) {
+	var ifNoneMatch []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotModified)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		rw.WriteHeader(http.StatusNotModified)
+	})
+	f := newTestFetcher(t, mux)
+	previous := newStoredUserData()
+	// the first page was full, but some of its repositories were skipped
+	previous.ETags.RepoPages = []domain.GithubReposPageETag{
+		{ETag: `"page1"`, RepoIDs: []int64{2}, Size: reposPerPage},
+		{ETag: `"page2"`, RepoIDs: []int64{1}, Size: 1},
+	}
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"page1"`, `"page2"`}, ifNoneMatch)
+	require.Equal(t, []domain.GithubRepository{previous.Repositories[1], previous.Repositories[0]}, data.Repositories)
+	require.Equal(t, previous.ETags, data.ETags)
+}
+
+func TestFetcherFetchUserDataPageStoredWithoutSize(t *testing.T) {
+	var ifNoneMatch []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotModified)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		rw.Header().Set("ETag", `"page1"`)
+		fmt.Fprint(rw, `[{"id":2,"name":"dotfiles","owner":{"login":"hurtki"}},{"id":1,"name":"banners","owner":{"login":"hurtki"}}]`)
+	})
+	f := newTestFetcher(t, mux)
+	previous := newStoredUserData()
+	previous.ETags.RepoPages[0].Size = 0
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	// without size the end of the list is unknown, so page is fetched without ETag
+	require.Equal(t, []string{""}, ifNoneMatch)
+	require.Len(t, data.Repositories, 2)
+	require.Equal(t, []domain.GithubReposPageETag{{ETag: `"page1"`, RepoIDs: []int64{2, 1}, Size: 2}}, data.ETags.RepoPages)
+}
+
+func TestFetcherFetchUserDataLanguageBytes(t *testing.T) {
+	pushed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var languageRequests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"login":"hurtki"}`)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[
+			{"id":1,"name":"banners","owner":{"login":"hurtki"},"pushed_at":"2025-01-01T00:00:00Z"},
+			{"id":2,"name":"dotfiles","owner":{"login":"hurtki"},"pushed_at":"2025-02-01T00:00:00Z"},
+			{"id":3,"name":"fork","owner":{"login":"hurtki"},"fork":true}
+		]`)
+	})
+	mux.HandleFunc("GET /repos/hurtki/{repo}/languages", func(rw http.ResponseWriter, r *http.Request) {
+		languageRequests = append(languageRequests, r.PathValue("repo"))
+		fmt.Fprint(rw, `{"Shell":300,"Makefile":20}`)
+	})
+	f := newTestFetcher(t, mux)
+	f.config.LanguagesMode = domain.LanguagesBytes
+
+	previous := newStoredUserData()
+	previous.ETags = nil
+	previous.Repositories[0].PushedAt = &pushed
+	previous.Repositories[0].Languages = map[string]int{"Go": 1000}
+	// dotfiles was pushed since previous fetch
+	previous.Repositories[1].PushedAt = &pushed
+	previous.Repositories[1].Languages = map[string]int{"Shell": 100}
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", previous)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dotfiles"}, languageRequests)
+	require.Equal(t, map[string]int{"Go": 1000}, data.Repositories[0].Languages)
+	require.Equal(t, map[string]int{"Shell": 300, "Makefile": 20}, data.Repositories[1].Languages)
+	require.Nil(t, data.Repositories[2].Languages)
+}
+
+func TestFetcherFetchUserDataNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/ghost", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `{"message":"Not Found"}`)
+	})
+	f := newTestFetcher(t, mux)
+
+	_, err := f.FetchUserData(context.Background(), "ghost", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFetcherOutOfRequests(t *testing.T) {
+	f := newTestFetcher(t, http.NewServeMux())
+	f.clients[0].Remaining = 0
+
+	_, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+}
@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// cost of userDataQuery in GraphQL points, connection of 100 nodes costs one point
+const userDataQueryCost = 1
+
+// GraphQLFetcher fetches user data using GitHub GraphQL v4 api
+// one query returns profile together with page of 100 repositories,
+// so it spends one request less than Fetcher and doesn't touch core rate limit at all
+type GraphQLFetcher struct {
+	// pool is a REST fetcher, which clients are shared, every client tracks GraphQL budget separately
+	pool   *Fetcher
+	logger logger.Logger
+}
+
+func NewGraphQLFetcher(pool *Fetcher, logger logger.Logger) *GraphQLFetcher {
+	return &GraphQLFetcher{
+		pool:   pool,
+		logger: logger.With("service", "github-graphql-fetcher"),
+	}
+}
+
+// FetchUserData fetches user and all the repositories, following the pages of repositories connection
+// GraphQL api doesn't support conditional requests, so previous data isn't used
+func (f *GraphQLFetcher) FetchUserData(ctx context.Context, username string, _ *domain.GithubUserData) (*domain.GithubUserData, error) {
+	if username != url.PathEscape(username) {
+		return nil, domain.ErrNotFound
+	}
+
+	var profile *graphqlUser
+	var repos []graphqlRepository
+	cursor := ""
+
+	for {
+		user, err := f.fetchPage(ctx, username, cursor)
+		if err != nil {
+			// even if we already collected couple repositories it shouldn't be returned
+			// because fetcher is used as source of truth
+			return nil, err
+		}
+		if profile == nil {
+			profile = user
+		}
+
+		repos = append(repos, user.Repositories.Nodes...)
+		if !user.Repositories.PageInfo.HasNextPage || user.Repositories.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor = user.Repositories.PageInfo.EndCursor
+	}
+
+	domainRepos := make([]domain.GithubRepository, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Owner.Login == "" {
+			continue
+		}
+		var language *string
+		if repo.PrimaryLanguage != nil {
+			language = &repo.PrimaryLanguage.Name
+		}
+		var languages map[string]int
+		if repo.Languages != nil {
+			languages = make(map[string]int, len(repo.Languages.Edges))
+			for _, edge := range repo.Languages.Edges {
+				languages[edge.Node.Name] += edge.Size
+			}
+		}
+		domainRepos = append(domainRepos, domain.GithubRepository{
+			ID:            repo.DatabaseID,
+			OwnerUsername: repo.Owner.Login,
+			Name:          repo.Name,
+			PushedAt:      repo.PushedAt,
+			UpdatedAt:     repo.UpdatedAt,
+			Language:      language,
+			StarsCount:    repo.StargazerCount,
+			Fork:          repo.IsFork,
+			ForksCount:    repo.ForkCount,
+			Languages:     languages,
+		})
+	}
+
+	return &domain.GithubUserData{
+		Username:     profile.Login,
+		Bio:          profile.Bio,
+		Name:         profile.Name,
+		Company:      profile.Company,
+		Location:     profile.Location,
+		PublicRepos:  profile.Repositories.TotalCount,
+		Followers:    profile.Followers.TotalCount,
+		Following:    profile.Following.TotalCount,
+		Repositories: domainRepos,
+		// nil, if contributions weren't requested
+		Contributions: profile.ContributionsCollection.toDomain(),
+		// sets the FetchedAt field to time when it was fetched
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// fetchPage runs userDataQuery for one page of repositories, empty cursor means the first page
+func (f *GraphQLFetcher) fetchPage(ctx context.Context, username string, cursor string) (*graphqlUser, error) {
+	fn := "internal.infrastructure.github.GraphQLFetcher.fetchPage"
+
+	variables := map[string]any{
+		"login":         username,
+		"cursor":        nil,
+		"withLanguages": f.pool.config.LanguagesMode == domain.LanguagesBytes,
+		// contributions don't depend on repositories, so they are fetched only with the first page
+		"withContributions": cursor == "",
+	}
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	// every page acquires a new client for one query
+	var out userDataResult
+	if err := f.pool.queryGraphQL(ctx, userDataQuery, variables, userDataQueryCost, &out); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			f.logger.Warn("can't fetch page of user data", "err", err, "source", fn)
+		}
+		return nil, err
+	}
+	if out.User == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return out.User, nil
+}
@@ -0,0 +1,162 @@
+package github
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// userDataQuery fetches user's profile and one page of owned public repositories
+// rateLimit is requested to keep GraphQL points budget of the client up to date, it doesn't cost anything
+// languages are included only with LanguagesBytes mode, they don't change cost of the query
+// contributions are included only with the first page
+const userDataQuery = `query($login: String!, $cursor: String, $withLanguages: Boolean!, $withContributions: Boolean!) {
+  rateLimit { cost remaining resetAt }
+  user(login: $login) {
+    login
+    name
+    bio
+    company
+    location
+    followers { totalCount }
+    following { totalCount }
+    ` + contributionsCollectionFields + ` @include(if: $withContributions)
+    repositories(first: 100, after: $cursor, ownerAffiliations: OWNER, privacy: PUBLIC, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId
+        name
+        owner { login }
+        isFork
+        stargazerCount
+        forkCount
+        primaryLanguage { name }
+        pushedAt
+        updatedAt
+        languages(first: 100, orderBy: {field: SIZE, direction: DESC}) @include(if: $withLanguages) {
+          edges { size node { name } }
+        }
+      }
+    }
+  }
+}`
+
+// contributionsQuery fetches only contribution activity of user
+const contributionsQuery = `query($login: String!) {
+  rateLimit { cost remaining resetAt }
+  user(login: $login) {
+    ` + contributionsCollectionFields + `
+  }
+}`
+
+// contributionsCollectionFields is a contribution activity in the last year, calendar is used to count streaks
+const contributionsCollectionFields = `contributionsCollection {
+      contributionCalendar {
+        totalContributions
+        weeks { contributionDays { date contributionCount } }
+      }
+      totalCommitContributions
+      totalPullRequestContributions
+      totalIssueContributions
+      totalPullRequestReviewContributions
+    }`
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type graphqlRateLimit struct {
+	Cost      int       `json:"cost"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+type graphqlTotalCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type graphqlRepository struct {
+	DatabaseID int64  `json:"databaseId"`
+	Name       string `json:"name"`
+	Owner      struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	IsFork          bool `json:"isFork"`
+	StargazerCount  int  `json:"stargazerCount"`
+	ForkCount       int  `json:"forkCount"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	PushedAt  *time.Time `json:"pushedAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+	Languages *struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+}
+
+type graphqlUser struct {
+	Login     string            `json:"login"`
+	Name      *string           `json:"name"`
+	Bio       *string           `json:"bio"`
+	Company   *string           `json:"company"`
+	Location  *string           `json:"location"`
+	Followers graphqlTotalCount `json:"followers"`
+	Following graphqlTotalCount `json:"following"`
+	// nil, if it wasn't included into query
+	ContributionsCollection *graphqlContributions `json:"contributionsCollection"`
+	Repositories            struct {
+		TotalCount int `json:"totalCount"`
+		PageInfo   struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		Nodes []graphqlRepository `json:"nodes"`
+	} `json:"repositories"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+// graphqlRateLimitResult is a part of every query's data
+type graphqlRateLimitResult struct {
+	RateLimit *graphqlRateLimit `json:"rateLimit"`
+}
+
+type userDataResult struct {
+	User *graphqlUser `json:"user"`
+}
+
+type contributionsResult struct {
+	User *struct {
+		ContributionsCollection *graphqlContributions `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+type graphqlContributions struct {
+	ContributionCalendar struct {
+		TotalContributions int `json:"totalContributions"`
+		Weeks              []struct {
+			ContributionDays []struct {
+				Date              string `json:"date"`
+				ContributionCount int    `json:"contributionCount"`
+			} `json:"contributionDays"`
+		} `json:"weeks"`
+	} `json:"contributionCalendar"`
+	TotalCommitContributions            int `json:"totalCommitContributions"`
+	TotalPullRequestContributions       int `json:"totalPullRequestContributions"`
+	TotalIssueContributions             int `json:"totalIssueContributions"`
+	TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+}
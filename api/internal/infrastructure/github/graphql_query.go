@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v81/github"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// graphqlPath returns path of GraphQL endpoint relative to client's base url
+// api.github.com serves it at /graphql, GitHub Enterprise Server at /api/graphql, next to REST's /api/v3/
+func graphqlPath(client *github.Client) string {
+	if strings.HasSuffix(client.BaseURL.Path, "/api/v3/") {
+		return "../graphql"
+	}
+	return "graphql"
+}
+
+// queryGraphQL runs GraphQL query with client, that has at least cost GraphQL points available
+// query's data is decoded into out, NOT_FOUND error of the query becomes domain.ErrNotFound
+func (f *Fetcher) queryGraphQL(ctx context.Context, query string, variables map[string]any, cost int, out any) error {
+	fn := "internal.infrastructure.github.Fetcher.queryGraphQL"
+
+	cl := f.acquire(ctx, graphqlResource, cost)
+	if cl == nil {
+		f.logger.Warn("can't find client with available graphql points", "source", fn)
+		return domain.ErrUnavailable
+	}
+
+	req, err := cl.Client.NewRequest(http.MethodPost, graphqlPath(cl.Client), graphqlRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		f.logger.Error("can't build graphql request", "err", err, "source", fn)
+		return domain.ErrUnavailable
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
+	defer cancel()
+
+	var res graphqlResponse
+	githubRes, err := cl.Client.Do(timeoutCtx, req, &res)
+	f.updateClientWithDoneResponse(cl, githubRes, err)
+	if err != nil {
+		f.logger.Warn("graphql request failed", "err", err, "source", fn)
+		return domain.ErrUnavailable
+	}
+
+	var rl graphqlRateLimitResult
+	if len(res.Data) != 0 && json.Unmarshal(res.Data, &rl) == nil {
+		f.updateClientWithRateLimit(cl, rl.RateLimit)
+	}
+
+	for _, gqlErr := range res.Errors {
+		if gqlErr.Type == "NOT_FOUND" {
+			return domain.ErrNotFound
+		}
+	}
+	if len(res.Errors) != 0 {
+		f.logger.Warn("graphql query returned errors", "err", res.Errors[0].Message, "type", res.Errors[0].Type, "source", fn)
+		return domain.ErrUnavailable
+	}
+
+	if err := json.Unmarshal(res.Data, out); err != nil {
+		f.logger.Warn("can't decode graphql query's data", "err", err, "source", fn)
+		return domain.ErrUnavailable
+	}
+	return nil
+}
+
+// updateClientWithRateLimit applies rateLimit object from query's response to client's GraphQL budget
+func (f *Fetcher) updateClientWithRateLimit(cl *GithubClient, rl *graphqlRateLimit) {
+	if rl == nil {
+		return
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.GraphQLRemaining = rl.Remaining
+	if !rl.ResetAt.IsZero() {
+		cl.GraphQLResetsAt = rl.ResetAt
+	}
+}
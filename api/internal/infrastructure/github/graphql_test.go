@@ -0,0 +1,182 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const graphqlFirstPage = `{"data":{
+  "rateLimit":{"cost":1,"remaining":4999,"resetAt":"2030-01-01T00:00:00Z"},
+  "user":{
+    "login":"hurtki","name":"Hurt Ki","bio":null,"company":null,"location":"Earth",
+    "followers":{"totalCount":5},"following":{"totalCount":1},
+    "contributionsCollection":{
+      "contributionCalendar":{"totalContributions":3,"weeks":[{"contributionDays":[{"date":"2025-01-01","contributionCount":3}]}]},
+      "totalCommitContributions":1,"totalPullRequestContributions":1,"totalIssueContributions":0,"totalPullRequestReviewContributions":1
+    },
+    "repositories":{
+      "totalCount":2,
+      "pageInfo":{"hasNextPage":true,"endCursor":"cursor1"},
+      "nodes":[{"databaseId":1,"owner":{"login":"hurtki"},"isFork":false,"stargazerCount":3,"forkCount":1,
+        "primaryLanguage":{"name":"Go"},"pushedAt":"2025-01-01T00:00:00Z","updatedAt":"2025-01-02T00:00:00Z"}]
+    }
+  }
+}}`
+
+const graphqlSecondPage = `{"data":{
+  "rateLimit":{"cost":1,"remaining":4998,"resetAt":"2030-01-01T00:00:00Z"},
+  "user":{
+    "login":"hurtki",
+    "followers":{"totalCount":5},"following":{"totalCount":1},
+    "repositories":{
+      "totalCount":2,
+      "pageInfo":{"hasNextPage":false,"endCursor":"cursor2"},
+      "nodes":[{"databaseId":2,"owner":{"login":"hurtki"},"isFork":true,"stargazerCount":0,"forkCount":0,
+        "primaryLanguage":null,"pushedAt":null,"updatedAt":null}]
+    }
+  }
+}}`
+
+// graphqlContributionsResponse is a response to contributionsQuery, the last day of calendar is today
+const graphqlContributionsResponse = `{"data":{
+  "rateLimit":{"cost":1,"remaining":8,"resetAt":"2030-01-01T00:00:00Z"},
+  "user":{"contributionsCollection":{
+    "contributionCalendar":{"totalContributions":6,"weeks":[
+      {"contributionDays":[{"date":"2025-01-01","contributionCount":1},{"date":"2025-01-02","contributionCount":0}]},
+      {"contributionDays":[{"date":"2025-01-03","contributionCount":2},{"date":"2025-01-04","contributionCount":3},{"date":"2025-01-05","contributionCount":0}]}
+    ]},
+    "totalCommitContributions":4,"totalPullRequestContributions":1,"totalIssueContributions":1,"totalPullRequestReviewContributions":0
+  }}
+}}`
+
+func TestGraphQLFetcherFetchUserData(t *testing.T) {
+	var cursors []any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "hurtki", req.Variables["login"])
+		require.Equal(t, false, req.Variables["withLanguages"])
+		cursors = append(cursors, req.Variables["cursor"])
+		// contributions are requested only with the first page
+		require.Equal(t, req.Variables["cursor"] == nil, req.Variables["withContributions"])
+
+		rw.Header().Set("X-RateLimit-Resource", "graphql")
+		if req.Variables["cursor"] == nil {
+			fmt.Fprint(rw, graphqlFirstPage)
+			return
+		}
+		fmt.Fprint(rw, graphqlSecondPage)
+	})
+	pool := newTestFetcher(t, mux)
+	f := NewGraphQLFetcher(pool, logger.NewLogger("error", "json"))
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.NoError(t, err)
+	require.Equal(t, []any{nil, "cursor1"}, cursors)
+
+	require.Equal(t, "hurtki", data.Username)
+	require.Equal(t, "Hurt Ki", *data.Name)
+	require.Nil(t, data.Bio)
+	require.Equal(t, "Earth", *data.Location)
+	require.Equal(t, 2, data.PublicRepos)
+	require.Equal(t, 5, data.Followers)
+	require.Equal(t, 1, data.Following)
+
+	pushed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	goLang := "Go"
+	require.Equal(t, []domain.GithubRepository{
+		{ID: 1, OwnerUsername: "hurtki", PushedAt: &pushed, UpdatedAt: &updated, Language: &goLang, StarsCount: 3, ForksCount: 1},
+		{ID: 2, OwnerUsername: "hurtki", Fork: true},
+	}, data.Repositories)
+	require.Equal(t, &domain.GithubContributions{
+		Total: 3, Commits: 1, PullRequests: 1, Reviews: 1, CurrentStreak: 1, LongestStreak: 1,
+	}, data.Contributions)
+
+	// GraphQL budget is taken from rateLimit of the last query, core budget isn't touched
+	require.Equal(t, 4998, pool.clients[0].GraphQLRemaining)
+	require.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), pool.clients[0].GraphQLResetsAt)
+	require.Equal(t, 10, pool.clients[0].Remaining)
+}
+
+func TestGraphQLFetcherLanguageBytes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, true, req.Variables["withLanguages"])
+		fmt.Fprint(rw, `{"data":{"user":{"login":"hurtki","repositories":{"totalCount":1,"pageInfo":{"hasNextPage":false},
+			"nodes":[{"databaseId":1,"name":"banners","owner":{"login":"hurtki"},
+				"languages":{"edges":[{"size":1000,"node":{"name":"Go"}},{"size":20,"node":{"name":"Makefile"}}]}}]}}}}`)
+	})
+	pool := newTestFetcher(t, mux)
+	pool.config.LanguagesMode = domain.LanguagesBytes
+	f := NewGraphQLFetcher(pool, logger.NewLogger("error", "json"))
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.NoError(t, err)
+	require.Equal(t, "banners", data.Repositories[0].Name)
+	require.Equal(t, map[string]int{"Go": 1000, "Makefile": 20}, data.Repositories[0].Languages)
+}
+
+func TestGraphQLFetcherNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"data":{"user":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a User with the login of 'ghost'."}]}`)
+	})
+	f := NewGraphQLFetcher(newTestFetcher(t, mux), logger.NewLogger("error", "json"))
+
+	_, err := f.FetchUserData(context.Background(), "ghost", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestGraphQLFetcherQueryError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"errors":[{"type":"MAX_NODE_LIMIT_EXCEEDED","message":"too many nodes"}]}`)
+	})
+	f := NewGraphQLFetcher(newTestFetcher(t, mux), logger.NewLogger("error", "json"))
+
+	_, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+}
+
+func TestGraphQLFetcherOutOfPoints(t *testing.T) {
+	pool := newTestFetcher(t, http.NewServeMux())
+	pool.clients[0].GraphQLRemaining = 0
+	f := NewGraphQLFetcher(pool, logger.NewLogger("error", "json"))
+
+	_, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+	// core requests are still available, GraphQL budget is separate
+	require.Equal(t, 10, pool.clients[0].Remaining)
+}
+
+func TestGraphQLFetcherRefreshesResetBudget(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rate_limit", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"resources":{"core":{"limit":5000,"remaining":100,"reset":1893456000},"graphql":{"limit":5000,"remaining":5000,"reset":1893456000}}}`)
+	})
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, graphqlSecondPage)
+	})
+	pool := newTestFetcher(t, mux)
+	pool.clients[0].GraphQLRemaining = 0
+	pool.clients[0].GraphQLResetsAt = time.Now().Add(-time.Minute)
+	f := NewGraphQLFetcher(pool, logger.NewLogger("error", "json"))
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.NoError(t, err)
+	require.Len(t, data.Repositories, 1)
+	require.Equal(t, 4998, pool.clients[0].GraphQLRemaining)
+	require.Equal(t, 100, pool.clients[0].Remaining)
+}
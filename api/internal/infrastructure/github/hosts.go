@@ -0,0 +1,201 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// HostsConfig describes github hosts, that HostsFetcher sends requests to
+type HostsConfig struct {
+	// tokens of the default host, token written as "host=token" is mapped to GitHub Enterprise Server at https://host/
+	Tokens []string
+	// api url and upload url of the default host, blank BaseURL is api.github.com
+	BaseURL   string
+	UploadURL string
+	// users data is fetched with GraphQL api instead of REST one
+	GraphQL bool
+	// GitHub App of the default host, its installations are added to the tokens' clients, nil if app isn't used
+	App *AppConfig
+}
+
+// userDataFetcher is implemented by both Fetcher and GraphQLFetcher
+type userDataFetcher interface {
+	FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error)
+}
+
+// hostFetchers are fetchers of one github host, they share its clients
+type hostFetchers struct {
+	rest  *Fetcher
+	users userDataFetcher
+}
+
+// HostsFetcher routes requests to fetcher of the host, that username is namespaced with ( see domain.SplitGithubHost )
+// fetched data is returned with namespaced usernames, so the same login on two hosts is stored and cached separately
+type HostsFetcher struct {
+	hosts map[string]hostFetchers
+	// guards hosts, fetcher of new host can be added at runtime together with its token
+	mu sync.RWMutex
+	// serializes changes of the tokens, see AddToken
+	tokensMu sync.Mutex
+
+	graphql bool
+	config  *domain.ServiceConfig
+	logger  logger.Logger
+}
+
+func NewHostsFetcher(cfg HostsConfig, config *domain.ServiceConfig, logger logger.Logger) (*HostsFetcher, error) {
+	tokens := map[string][]string{"": nil}
+	for _, token := range cfg.Tokens {
+		host, hostToken, ok := strings.Cut(token, "=")
+		if !ok {
+			tokens[""] = append(tokens[""], token)
+			continue
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		tokens[host] = append(tokens[host], strings.TrimSpace(hostToken))
+	}
+
+	f := &HostsFetcher{hosts: map[string]hostFetchers{}, graphql: cfg.GraphQL, config: config, logger: logger}
+	for host, hostTokens := range tokens {
+		newClient := github.NewClient
+		var err error
+		switch {
+		case host != "":
+			newClient, err = enterpriseClientFactory("https://"+host+"/", "")
+		case cfg.BaseURL != "":
+			newClient, err = enterpriseClientFactory(cfg.BaseURL, cfg.UploadURL)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't create fetcher of host %q: %w", host, err)
+		}
+
+		sources := staticTokenSources(host, hostTokens, domain.GithubTokenSourceEnv)
+		if host == "" && cfg.App != nil {
+			appSources, err := appTokenSources(context.Background(), *cfg.App, newClient)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, appSources...)
+		}
+		f.add(host, newFetcher(sources, host, newClient, config, logger))
+	}
+	return f, nil
+}
+
+// add registers fetcher of host, blank host is the default one
+func (f *HostsFetcher) add(host string, rest *Fetcher) {
+	var users userDataFetcher = rest
+	if f.graphql {
+		users = NewGraphQLFetcher(rest, f.logger)
+	}
+	f.mu.Lock()
+	f.hosts[host] = hostFetchers{rest: rest, users: users}
+	f.mu.Unlock()
+}
+
+// route returns fetchers of username's host and login on that host
+// username of the host, that has no tokens, is treated as not existing one
+func (f *HostsFetcher) route(username string) (hostFetchers, string, string, error) {
+	host, login := domain.SplitGithubHost(username)
+	f.mu.RLock()
+	fetchers, ok := f.hosts[domain.NormalizeGithubUsername(host)]
+	f.mu.RUnlock()
+	if !ok {
+		return hostFetchers{}, "", "", domain.ErrNotFound
+	}
+	return fetchers, domain.NormalizeGithubUsername(host), login, nil
+}
+
+func (f *HostsFetcher) FetchUserData(ctx context.Context, username string, previous *domain.GithubUserData) (*domain.GithubUserData, error) {
+	fetchers, host, login, err := f.route(username)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		prev := *previous
+		prev.Username = withHost("", prev.Username)
+		prev.Repositories = reposWithHost("", prev.Repositories)
+		previous = &prev
+	}
+
+	data, err := fetchers.users.FetchUserData(ctx, login, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Username = withHost(host, data.Username)
+	data.Repositories = reposWithHost(host, data.Repositories)
+	return data, nil
+}
+
+func (f *HostsFetcher) FetchOrgData(ctx context.Context, login string, previous *domain.GithubOrgData) (*domain.GithubOrgData, error) {
+	fetchers, host, hostLogin, err := f.route(login)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		prev := *previous
+		prev.Login = withHost("", prev.Login)
+		prev.Repositories = reposWithHost("", prev.Repositories)
+		previous = &prev
+	}
+
+	data, err := fetchers.rest.FetchOrgData(ctx, hostLogin, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Login = withHost(host, data.Login)
+	data.Repositories = reposWithHost(host, data.Repositories)
+	return data, nil
+}
+
+func (f *HostsFetcher) FetchRepoData(ctx context.Context, fullName string, previous *domain.GithubRepoData) (*domain.GithubRepoData, error) {
+	fetchers, host, hostFullName, err := f.route(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		prev := *previous
+		prev.OwnerUsername = withHost("", prev.OwnerUsername)
+		previous = &prev
+	}
+
+	data, err := fetchers.rest.FetchRepoData(ctx, hostFullName, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.OwnerUsername = withHost(host, data.OwnerUsername)
+	return data, nil
+}
+
+func (f *HostsFetcher) HasOwnershipProof(ctx context.Context, username string, challenge string) (bool, error) {
+	fetchers, _, login, err := f.route(username)
+	if err != nil {
+		return false, err
+	}
+	return fetchers.rest.HasOwnershipProof(ctx, login, challenge)
+}
+
+// withHost namespaces name by host, name can be already namespaced by any host
+func withHost(host string, name string) string {
+	_, login := domain.SplitGithubHost(name)
+	return domain.JoinGithubHost(host, login)
+}
+
+// reposWithHost returns copy of repos with owners namespaced by host
+func reposWithHost(host string, repos []domain.GithubRepository) []domain.GithubRepository {
+	if repos == nil {
+		return nil
+	}
+	namespaced := make([]domain.GithubRepository, len(repos))
+	for i, repo := range repos {
+		repo.OwnerUsername = withHost(host, repo.OwnerUsername)
+		namespaced[i] = repo
+	}
+	return namespaced
+}
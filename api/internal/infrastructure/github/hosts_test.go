@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHostsFetcher() *HostsFetcher {
+	return &HostsFetcher{
+		hosts:  map[string]hostFetchers{},
+		config: &domain.ServiceConfig{RequestTimeout: time.Second},
+		logger: logger.NewLogger("error", "json"),
+	}
+}
+
+// newUserMux returns stand-in of github api with one user, that has one repository
+func newUserMux(name string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/hurtki", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(rw, `{"login":"hurtki","name":%q}`, name)
+	})
+	mux.HandleFunc("GET /users/hurtki/repos", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[{"id":1,"name":"banners","owner":{"login":"hurtki"}}]`)
+	})
+	mux.HandleFunc("POST /graphql", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, graphqlContributionsResponse)
+	})
+	return mux
+}
+
+func TestHostsFetcherRoutesByHost(t *testing.T) {
+	f := newTestHostsFetcher()
+	f.add("", newTestFetcher(t, newUserMux("Public")))
+	f.add("github.example.com", newTestFetcher(t, newUserMux("Enterprise")))
+
+	data, err := f.FetchUserData(context.Background(), "hurtki", nil)
+	require.NoError(t, err)
+	require.Equal(t, "hurtki", data.Username)
+	require.Equal(t, "Public", *data.Name)
+	require.Equal(t, "hurtki", data.Repositories[0].OwnerUsername)
+
+	data, err = f.FetchUserData(context.Background(), "GitHub.example.com:hurtki", nil)
+	require.NoError(t, err)
+	require.Equal(t, "github.example.com:hurtki", data.Username)
+	require.Equal(t, "Enterprise", *data.Name)
+	require.Equal(t, "github.example.com:hurtki", data.Repositories[0].OwnerUsername)
+
+	// previous data is namespaced, but fetcher of the host gets it with plain logins
+	data, err = f.FetchUserData(context.Background(), "github.example.com:hurtki", data)
+	require.NoError(t, err)
+	require.Equal(t, "github.example.com:hurtki", data.Username)
+}
+
+func TestHostsFetcherUnknownHost(t *testing.T) {
+	f := newTestHostsFetcher()
+	f.add("", newTestFetcher(t, newUserMux("Public")))
+
+	_, err := f.FetchUserData(context.Background(), "github.example.com:hurtki", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = f.FetchRepoData(context.Background(), "github.example.com:hurtki/banners", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestHostsFetcherFetchRepoData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/hurtki/banners", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, ghRepoResponse)
+	})
+	mux.HandleFunc("GET /repos/hurtki/banners/releases/latest", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /repos/hurtki/banners/languages", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"Go":9000}`)
+	})
+	f := newTestHostsFetcher()
+	f.add("github.example.com", newTestFetcher(t, mux))
+
+	data, err := f.FetchRepoData(context.Background(), "github.example.com:hurtki/banners", nil)
+	require.NoError(t, err)
+	require.Equal(t, "github.example.com:hurtki", data.OwnerUsername)
+	require.Equal(t, "banners", data.Name)
+}
+
+func TestGraphqlPath(t *testing.T) {
+	require.Equal(t, "graphql", graphqlPath(github.NewClient(nil)))
+
+	client, err := github.NewClient(nil).WithEnterpriseURLs("https://github.example.com/", "")
+	require.NoError(t, err)
+	endpoint, err := client.BaseURL.Parse(graphqlPath(client))
+	require.NoError(t, err)
+	require.Equal(t, "https://github.example.com/api/graphql", endpoint.String())
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// FetchOrgData fetches organization's profile, count of its public members and its public repositories
+// previous is a stored data of the organization ( can be nil ), languages of not pushed repositories are taken from it
+func (f *Fetcher) FetchOrgData(ctx context.Context, login string, previous *domain.GithubOrgData) (*domain.GithubOrgData, error) {
+	if login != url.PathEscape(login) {
+		return nil, domain.ErrNotFound
+	}
+
+	org := &github.Organization{}
+	if _, err := f.getConditional(ctx, "orgs/"+login, "", org); err != nil {
+		return nil, err
+	}
+
+	members, err := f.countOrgMembers(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := f.fetchOrgRepositories(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.config.LanguagesMode == domain.LanguagesBytes {
+		var prevRepos []domain.GithubRepository
+		if previous != nil {
+			prevRepos = previous.Repositories
+		}
+		if err := f.fillLanguages(ctx, repos, prevRepos); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.GithubOrgData{
+		Login:        org.GetLogin(),
+		Name:         org.Name,
+		Description:  org.Description,
+		Location:     org.Location,
+		PublicRepos:  org.GetPublicRepos(),
+		Members:      members,
+		Repositories: repos,
+		// sets the FetchedAt field to time when it was fetched
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// countOrgMembers counts public members with one request:
+// with one member per page, number of the last page is a count of members
+func (f *Fetcher) countOrgMembers(ctx context.Context, login string) (int, error) {
+	var members []*github.User
+	res, err := f.getConditional(ctx, fmt.Sprintf("orgs/%s/public_members?per_page=1", login), "", &members)
+	if err != nil {
+		return 0, err
+	}
+	if res.LastPage != 0 {
+		return res.LastPage, nil
+	}
+	return len(members), nil
+}
+
+// fetchOrgRepositories fetches all public repositories of organization (paginated)
+func (f *Fetcher) fetchOrgRepositories(ctx context.Context, login string) ([]domain.GithubRepository, error) {
+	var allRepos []domain.GithubRepository
+	for page := 1; ; page++ {
+		var repos []*github.Repository
+		res, err := f.getConditional(ctx, fmt.Sprintf("orgs/%s/repos?type=public&sort=updated&per_page=%d&page=%d", login, reposPerPage, page), "", &repos)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range repos {
+			if domainRepo, ok := repoToDomain(repo); ok {
+				allRepos = append(allRepos, domainRepo)
+			}
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+	}
+	return allRepos, nil
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcherFetchOrgData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/gophers", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"login":"gophers","name":"Gophers","description":"We like Go","public_repos":2}`)
+	})
+	mux.HandleFunc("GET /orgs/gophers/public_members", func(rw http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "1", r.URL.Query().Get("per_page"))
+		rw.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/gophers/public_members?per_page=1&page=2>; rel="next", <http://%s/orgs/gophers/public_members?per_page=1&page=42>; rel="last"`, r.Host, r.Host))
+		fmt.Fprint(rw, `[{"login":"hurtki"}]`)
+	})
+	mux.HandleFunc("GET /orgs/gophers/repos", func(rw http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "public", r.URL.Query().Get("type"))
+		if r.URL.Query().Get("page") == "1" {
+			rw.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/gophers/repos?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(rw, `[{"id":1,"name":"gopher","owner":{"login":"gophers"},"language":"Go","stargazers_count":30}]`)
+			return
+		}
+		fmt.Fprint(rw, `[{"id":2,"name":"fork","owner":{"login":"gophers"},"fork":true}]`)
+	})
+	f := newTestFetcher(t, mux)
+
+	data, err := f.FetchOrgData(context.Background(), "gophers", nil)
+	require.NoError(t, err)
+	require.Equal(t, "gophers", data.Login)
+	require.Equal(t, "Gophers", *data.Name)
+	require.Equal(t, "We like Go", *data.Description)
+	require.Equal(t, 2, data.PublicRepos)
+	require.Equal(t, 42, data.Members)
+	require.Len(t, data.Repositories, 2)
+	require.Equal(t, 30, data.Repositories[0].StarsCount)
+	require.True(t, data.Repositories[1].Fork)
+	require.False(t, data.FetchedAt.IsZero())
+}
+
+func TestFetcherFetchOrgDataOneMemberPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/tiny", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"login":"tiny"}`)
+	})
+	mux.HandleFunc("GET /orgs/tiny/public_members", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[]`)
+	})
+	mux.HandleFunc("GET /orgs/tiny/repos", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `[]`)
+	})
+	f := newTestFetcher(t, mux)
+
+	data, err := f.FetchOrgData(context.Background(), "tiny", nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, data.Members)
+	require.Empty(t, data.Repositories)
+}
+
+func TestFetcherFetchOrgDataNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/ghost", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `{"message":"Not Found"}`)
+	})
+	f := newTestFetcher(t, mux)
+
+	_, err := f.FetchOrgData(context.Background(), "ghost", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// HasOwnershipProof looks for challenge in user's public gists ( description or file name )
+// and in user's profile README ( repository named as user )
+// returns domain.ErrNotFound, if user doesn't exist
+func (f *Fetcher) HasOwnershipProof(ctx context.Context, username string, challenge string) (bool, error) {
+	if username != url.PathEscape(username) {
+		return false, domain.ErrNotFound
+	}
+
+	found, err := f.challengeInGists(ctx, username, challenge)
+	if err != nil || found {
+		return found, err
+	}
+	return f.challengeInProfileReadme(ctx, username, challenge)
+}
+
+// challengeInGists checks only the first page of gists, challenge gist should be the latest one
+func (f *Fetcher) challengeInGists(ctx context.Context, username string, challenge string) (bool, error) {
+	cl := f.acquireClient(ctx)
+	if cl == nil {
+		f.logger.Warn("can't find available client for github api request")
+		return false, domain.ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
+	defer cancel()
+
+	gists, res, err := cl.Client.Gists.List(ctx, username, &github.GistListOptions{ListOptions: github.ListOptions{PerPage: 30}})
+	f.updateClientWithDoneResponse(cl, res, err)
+	if err != nil {
+		if er, ok := err.(*github.ErrorResponse); ok {
+			if er.Response.StatusCode == http.StatusNotFound {
+				return false, domain.ErrNotFound
+			}
+		}
+		return false, domain.ErrUnavailable
+	}
+
+	for _, gist := range gists {
+		if strings.Contains(gist.GetDescription(), challenge) {
+			return true, nil
+		}
+		for name := range gist.Files {
+			if strings.Contains(string(name), challenge) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (f *Fetcher) challengeInProfileReadme(ctx context.Context, username string, challenge string) (bool, error) {
+	cl := f.acquireClient(ctx)
+	if cl == nil {
+		f.logger.Warn("can't find available client for github api request")
+		return false, domain.ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.RequestTimeout)
+	defer cancel()
+
+	readme, res, err := cl.Client.Repositories.GetReadme(ctx, username, username, nil)
+	f.updateClientWithDoneResponse(cl, res, err)
+	if err != nil {
+		if er, ok := err.(*github.ErrorResponse); ok {
+			// user doesn't have profile README
+			if er.Response.StatusCode == http.StatusNotFound {
+				return false, nil
+			}
+		}
+		return false, domain.ErrUnavailable
+	}
+
+	content, err := readme.GetContent()
+	if err != nil {
+		return false, domain.ErrUnavailable
+	}
+	return strings.Contains(content, challenge), nil
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// FetchRepoData fetches single repository with its latest release and languages
+// previous is a stored data of the repository ( can be nil ), its languages are kept, if repository wasn't pushed since then
+// languages are always fetched in bytes, repository banner shows breakdown of its code
+func (f *Fetcher) FetchRepoData(ctx context.Context, fullName string, previous *domain.GithubRepoData) (*domain.GithubRepoData, error) {
+	owner, name, ok := domain.SplitRepoFullName(fullName)
+	if !ok || owner != url.PathEscape(owner) || name != url.PathEscape(name) {
+		return nil, domain.ErrNotFound
+	}
+
+	ghRepo := &github.Repository{}
+	if _, err := f.getConditional(ctx, fmt.Sprintf("repos/%s/%s", owner, name), "", ghRepo); err != nil {
+		return nil, err
+	}
+	repo, ok := repoToDomain(ghRepo)
+	if !ok {
+		return nil, domain.ErrUnavailable
+	}
+
+	release, err := f.fetchLatestRelease(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != nil && previous.Languages != nil && previous.ID == repo.ID && samePushedAt(previous.PushedAt, repo.PushedAt) {
+		repo.Languages = previous.Languages
+	} else {
+		languages := map[string]int{}
+		_, err := f.getConditional(ctx, fmt.Sprintf("repos/%s/%s/languages", url.PathEscape(repo.OwnerUsername), url.PathEscape(repo.Name)), "", &languages)
+		if err != nil {
+			return nil, err
+		}
+		repo.Languages = languages
+	}
+
+	return &domain.GithubRepoData{
+		GithubRepository: repo,
+		Description:      ghRepo.Description,
+		OpenIssues:       ghRepo.GetOpenIssuesCount(),
+		License:          licenseID(ghRepo.GetLicense()),
+		LatestRelease:    release,
+		// sets the FetchedAt field to time when it was fetched
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// fetchLatestRelease returns tag of the latest release or nil, if repository has no releases
+func (f *Fetcher) fetchLatestRelease(ctx context.Context, repo domain.GithubRepository) (*string, error) {
+	release := &github.RepositoryRelease{}
+	_, err := f.getConditional(ctx, fmt.Sprintf("repos/%s/%s/releases/latest", url.PathEscape(repo.OwnerUsername), url.PathEscape(repo.Name)), "", release)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return release.TagName, nil
+}
+
+// licenseID returns SPDX id of the license
+// licenses, that github can't recognize, have NOASSERTION id, their name ( "Other" ) is returned instead
+func licenseID(license *github.License) *string {
+	if license == nil {
+		return nil
+	}
+	id := license.GetSPDXID()
+	if id == "" || id == "NOASSERTION" {
+		id = license.GetName()
+	}
+	if id == "" {
+		return nil
+	}
+	return &id
+}
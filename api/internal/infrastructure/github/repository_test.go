@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+const ghRepoResponse = `{"id":7,"name":"banners","owner":{"login":"hurtki"},"description":"Banners for READMEs",
+  "stargazers_count":120,"forks_count":8,"open_issues_count":3,"language":"Go",
+  "license":{"key":"mit","name":"MIT License","spdx_id":"MIT"},"pushed_at":"2025-01-01T00:00:00Z"}`
+
+func TestFetcherFetchRepoData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/hurtki/banners", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, ghRepoResponse)
+	})
+	mux.HandleFunc("GET /repos/hurtki/banners/releases/latest", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"tag_name":"v1.2.0","name":"Second release"}`)
+	})
+	mux.HandleFunc("GET /repos/hurtki/banners/languages", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"Go":9000,"Dockerfile":100}`)
+	})
+	f := newTestFetcher(t, mux)
+
+	data, err := f.FetchRepoData(context.Background(), "hurtki/banners", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), data.ID)
+	require.Equal(t, "hurtki", data.OwnerUsername)
+	require.Equal(t, "banners", data.Name)
+	require.Equal(t, "Banners for READMEs", *data.Description)
+	require.Equal(t, 120, data.StarsCount)
+	require.Equal(t, 8, data.ForksCount)
+	require.Equal(t, 3, data.OpenIssues)
+	require.Equal(t, "MIT", *data.License)
+	require.Equal(t, "v1.2.0", *data.LatestRelease)
+	require.Equal(t, map[string]int{"Go": 9000, "Dockerfile": 100}, data.Languages)
+	require.False(t, data.FetchedAt.IsZero())
+}
+
+func TestFetcherFetchRepoDataWithoutReleases(t *testing.T) {
+	pushed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/hurtki/banners", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"id":7,"name":"banners","owner":{"login":"hurtki"},"license":{"spdx_id":"NOASSERTION","name":"Other"},"pushed_at":"2025-01-01T00:00:00Z"}`)
+	})
+	mux.HandleFunc("GET /repos/hurtki/banners/releases/latest", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `{"message":"Not Found"}`)
+	})
+	f := newTestFetcher(t, mux)
+	// repository wasn't pushed since previous fetch, so languages aren't requested
+	previous := &domain.GithubRepoData{GithubRepository: domain.GithubRepository{ID: 7, PushedAt: &pushed, Languages: map[string]int{"Go": 1000}}}
+
+	data, err := f.FetchRepoData(context.Background(), "hurtki/banners", previous)
+	require.NoError(t, err)
+	require.Nil(t, data.LatestRelease)
+	require.Equal(t, "Other", *data.License)
+	require.Equal(t, map[string]int{"Go": 1000}, data.Languages)
+}
+
+func TestFetcherFetchRepoDataNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/hurtki/ghost", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `{"message":"Not Found"}`)
+	})
+	f := newTestFetcher(t, mux)
+
+	_, err := f.FetchRepoData(context.Background(), "hurtki/ghost", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// full name without owner can't be fetched at all
+	_, err = f.FetchRepoData(context.Background(), "banners", nil)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+// Tokens returns states of all the clients of all the hosts, default host goes first
+func (f *HostsFetcher) Tokens() []domain.GithubTokenState {
+	f.mu.RLock()
+	hosts := make([]string, 0, len(f.hosts))
+	for host := range f.hosts {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+	fetchers := make([]*Fetcher, 0, len(hosts))
+	for _, host := range hosts {
+		fetchers = append(fetchers, f.hosts[host].rest)
+	}
+	f.mu.RUnlock()
+
+	states := []domain.GithubTokenState{}
+	for _, rest := range fetchers {
+		for _, cl := range rest.snapshot() {
+			states = append(states, cl.state(rest.host))
+		}
+	}
+	return states
+}
+
+// AddToken adds client with personal access token to the pool of host, blank host is the default one
+// token of host, that has no fetcher yet, creates fetcher of GitHub Enterprise Server at https://host/
+// disabled token is enabled again, token, that is already used, returns domain.ConflictError
+// token, that github rejects, returns domain.ErrTokenRejected
+func (f *HostsFetcher) AddToken(ctx context.Context, host string, token string) (domain.GithubTokenState, error) {
+	// admin operations are serialized, so two tokens of new host don't create two fetchers
+	f.tokensMu.Lock()
+	defer f.tokensMu.Unlock()
+
+	host = strings.ToLower(host)
+	id := domain.GithubTokenID(host, token)
+
+	f.mu.RLock()
+	fetchers, known := f.hosts[host]
+	f.mu.RUnlock()
+
+	rest := fetchers.rest
+	if !known {
+		newClient, err := enterpriseClientFactory("https://"+host+"/", "")
+		if err != nil {
+			return domain.GithubTokenState{}, err
+		}
+		rest = newFetcher(nil, host, newClient, f.config, f.logger)
+	}
+
+	if cl := rest.client(id); cl != nil {
+		cl.mu.Lock()
+		defer cl.mu.Unlock()
+		if !cl.Disabled {
+			return domain.GithubTokenState{}, domain.NewConflictError("token")
+		}
+		cl.Disabled = false
+		return cl.stateLocked(host), nil
+	}
+
+	cl, err := rest.addClient(ctx, namedTokenSource{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+		id:          id,
+		name:        domain.MaskGithubToken(token),
+		source:      domain.GithubTokenSourceAdmin,
+	})
+	if err != nil {
+		return domain.GithubTokenState{}, err
+	}
+	if !known {
+		f.add(host, rest)
+	}
+	return cl.state(host), nil
+}
+
+// DisableToken stops using client of token with id, client stays in the pool, so it's listed and can be enabled again
+// returns domain.ErrNotFound, if pool has no such token
+func (f *HostsFetcher) DisableToken(id string) error {
+	f.tokensMu.Lock()
+	defer f.tokensMu.Unlock()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, fetchers := range f.hosts {
+		if cl := fetchers.rest.client(id); cl != nil {
+			cl.mu.Lock()
+			cl.Disabled = true
+			cl.mu.Unlock()
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// client returns client of token with id or nil
+func (f *Fetcher) client(id string) *GithubClient {
+	for _, cl := range f.snapshot() {
+		if cl.ID == id {
+			return cl
+		}
+	}
+	return nil
+}
+
+func (cl *GithubClient) state(host string) domain.GithubTokenState {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.stateLocked(host)
+}
+
+// stateLocked should be called with client's mutex locked
+func (cl *GithubClient) stateLocked(host string) domain.GithubTokenState {
+	state := domain.GithubTokenState{
+		ID:               cl.ID,
+		Host:             host,
+		Masked:           cl.Name,
+		Source:           cl.Source,
+		Remaining:        cl.Remaining,
+		ResetsAt:         cl.ResetsAt,
+		GraphQLRemaining: cl.GraphQLRemaining,
+		GraphQLResetsAt:  cl.GraphQLResetsAt,
+		Quarantined:      cl.Quarantined,
+		Disabled:         cl.Disabled,
+	}
+	if cooldownUntil := cl.CooldownUntil; cooldownUntil.After(time.Now()) {
+		state.CooldownUntil = &cooldownUntil
+	}
+	return state
+}
@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostsFetcherManagesTokens(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rate_limit", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp_valid_token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(rw, `{"message":"Bad credentials"}`)
+			return
+		}
+		fmt.Fprintf(rw, `{"resources":{"core":{"limit":5000,"remaining":4000,"reset":%d}}}`, time.Now().Add(time.Hour).Unix())
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	newClient := func(httpClient *http.Client) *github.Client {
+		client := github.NewClient(httpClient)
+		client.BaseURL, _ = url.Parse(srv.URL + "/")
+		return client
+	}
+
+	f := newTestHostsFetcher()
+	rest := newFetcher(nil, "", newClient, f.config, f.logger)
+	f.add("", rest)
+	ctx := context.Background()
+	require.Nil(t, rest.acquireClient(ctx))
+
+	state, err := f.AddToken(ctx, "", "ghp_valid_token")
+	require.NoError(t, err)
+	require.Equal(t, domain.GithubTokenID("", "ghp_valid_token"), state.ID)
+	require.Equal(t, "ghp_...", state.Masked)
+	require.Equal(t, domain.GithubTokenSourceAdmin, state.Source)
+	require.Equal(t, 4000, state.Remaining)
+	require.NotNil(t, rest.acquireClient(ctx))
+
+	_, err = f.AddToken(ctx, "", "ghp_valid_token")
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	_, err = f.AddToken(ctx, "", "ghp_revoked_token")
+	require.ErrorIs(t, err, domain.ErrTokenRejected)
+
+	require.NoError(t, f.DisableToken(state.ID))
+	require.ErrorIs(t, f.DisableToken("unknown"), domain.ErrNotFound)
+	require.Nil(t, rest.acquireClient(ctx))
+	require.True(t, f.Tokens()[0].Disabled)
+
+	// disabled token is enabled again without new client
+	_, err = f.AddToken(ctx, "", "ghp_valid_token")
+	require.NoError(t, err)
+	require.Len(t, f.Tokens(), 1)
+	require.NotNil(t, rest.acquireClient(ctx))
+}
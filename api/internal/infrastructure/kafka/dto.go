@@ -9,7 +9,10 @@ import (
 func FromDomainBannerInfoToPayload(bf domain.LTBannerInfo) Payload {
 	return Payload{
 		Username:    bf.Username,
-		BannerType:  domain.BannerTypesBackward[bf.BannerType],
+		Kind:        string(bf.Kind),
+		BannerType:  string(bf.BannerType),
+		Layout:      string(bf.Layout),
+		Motion:      string(bf.Motion),
 		StoragePath: bf.UrlPath,
 		Stats:       FromDomainUserStats(bf.Stats),
 		FetchedAt:   bf.Stats.FetchedAt,
@@ -24,6 +27,38 @@ func FromDomainUserStats(us domain.GithubUserStats) Stats {
 		TotalStars:    us.TotalStars,
 		TotalForks:    us.TotalForks,
 		Languages:     us.Languages,
+		Contributions: fromDomainContributions(us.Contributions),
+		Members:       us.Members,
+		Repository:    fromDomainRepository(us.Repository),
+	}
+}
+
+// fromDomainRepository returns nil for nil repository's stats, so they are omitted
+func fromDomainRepository(r *domain.GithubRepoStats) *Repository {
+	if r == nil {
+		return nil
+	}
+	return &Repository{
+		OpenIssues:    r.OpenIssues,
+		License:       r.License,
+		LatestRelease: r.LatestRelease,
+		PushedAt:      r.PushedAt,
+	}
+}
+
+// fromDomainContributions returns nil for nil contributions, so they are omitted
+func fromDomainContributions(c *domain.GithubContributions) *Contributions {
+	if c == nil {
+		return nil
+	}
+	return &Contributions{
+		Total:         c.Total,
+		Commits:       c.Commits,
+		PullRequests:  c.PullRequests,
+		Issues:        c.Issues,
+		Reviews:       c.Reviews,
+		CurrentStreak: c.CurrentStreak,
+		LongestStreak: c.LongestStreak,
 	}
 }
 
@@ -34,11 +69,36 @@ type Stats struct {
 	TotalStars    int            `json:"total_stars"`
 	TotalForks    int            `json:"total_forks"`
 	Languages     map[string]int `json:"languages"`
+	Contributions *Contributions `json:"contributions,omitempty"`
+	// public members of organization
+	Members int `json:"members,omitempty"`
+	// details of single repository
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+type Repository struct {
+	OpenIssues    int        `json:"open_issues"`
+	License       *string    `json:"license,omitempty"`
+	LatestRelease *string    `json:"latest_release,omitempty"`
+	PushedAt      *time.Time `json:"pushed_at,omitempty"`
+}
+
+type Contributions struct {
+	Total         int `json:"total"`
+	Commits       int `json:"commits"`
+	PullRequests  int `json:"pull_requests"`
+	Issues        int `json:"issues"`
+	Reviews       int `json:"reviews"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
 }
 
 type Payload struct {
 	Username    string    `json:"username"`
+	Kind        string    `json:"kind,omitempty"`
 	BannerType  string    `json:"banner_type"`
+	Layout      string    `json:"layout"`
+	Motion      string    `json:"motion,omitempty"`
 	StoragePath string    `json:"storage_path"`
 	Stats       Stats     `json:"stats"`
 	FetchedAt   time.Time `json:"fetched_at"`
@@ -0,0 +1,140 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// Elector elects one leader of replicas with session advisory lock of postgres:
+// replica, that holds the lock, is the leader, lock is released, when its connection is closed
+// so, if leader dies, other replica becomes leader on its next try
+type Elector struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+	logger   logger.Logger
+
+	// conn holds the lock, it's used only by run goroutine
+	conn   *sql.Conn
+	leader atomic.Bool
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+func NewElector(db *sql.DB, key int64, interval time.Duration, logger logger.Logger) *Elector {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Elector{
+		db:       db,
+		key:      key,
+		interval: interval,
+		logger:   logger.With("service", "leader-elector"),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// IsLeader reports, if replica held the lock on the last check
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+func (e *Elector) Start() {
+	e.wg.Go(e.run)
+}
+
+func (e *Elector) run() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.logger.Info("started", "interval", e.interval.String(), "key", e.key)
+	for {
+		e.elect()
+		select {
+		case <-e.ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect checks, that connection of the leader is alive, or tries to take the lock
+func (e *Elector) elect() {
+	fn := "internal.infrastructure.pglock.Elector.elect"
+	ctx, cancel := context.WithTimeout(e.ctx, e.interval)
+	defer cancel()
+
+	if e.conn != nil {
+		var one int
+		if err := e.conn.QueryRowContext(ctx, "select 1;").Scan(&one); err != nil {
+			e.logger.Warn("lost connection, that holds leader lock", "source", fn, "err", err)
+			e.resign()
+		}
+		return
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		e.logger.Warn("can't get connection to take leader lock", "source", fn, "err", err)
+		return
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "select pg_try_advisory_lock($1);", e.key).Scan(&locked); err != nil {
+		e.logger.Warn("can't take leader lock", "source", fn, "err", err)
+		discard(conn)
+		return
+	}
+	if !locked {
+		conn.Close()
+		return
+	}
+	e.conn = conn
+	e.leader.Store(true)
+	e.logger.Info("became leader", "source", fn)
+}
+
+// resign stops being leader, connection is closed instead of returning to the pool, so the lock is released with it
+func (e *Elector) resign() {
+	if e.conn == nil {
+		return
+	}
+	e.leader.Store(false)
+	discard(e.conn)
+	e.conn = nil
+	e.logger.Info("resigned leadership")
+}
+
+// Close resigns leadership, so other replica can take it at once
+func (e *Elector) Close(ctx context.Context) error {
+	e.cancel()
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		e.logger.Warn("couldn't shutdown in time, exiting", "ctxErr", ctx.Err())
+		return ctx.Err()
+	case <-done:
+		e.logger.Info("successfully shutted down")
+		return nil
+	}
+}
+
+// discard closes connection, it's not returned to the pool
+func discard(conn *sql.Conn) {
+	conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	conn.Close()
+}
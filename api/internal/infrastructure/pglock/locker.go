@@ -0,0 +1,49 @@
+package pglock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// previewsLockSpace is the first key of two keys advisory locks of Locker, so they don't collide with leader lock
+const previewsLockSpace int32 = 1
+
+// Locker takes advisory lock of postgres per key, so replicas do the same work one by one
+// every held lock takes connection of the pool
+type Locker struct {
+	db *sql.DB
+}
+
+func NewLocker(db *sql.DB) *Locker {
+	return &Locker{db: db}
+}
+
+// TryLock takes lock of key, ok is false, if other replica holds it
+// connection is kept only for taken lock, so replicas, that retry, don't hold connections of the pool
+// unlock releases the lock, it should be called once
+func (l *Locker) TryLock(ctx context.Context, key string) (unlock func(), ok bool, err error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("can't get connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "select pg_try_advisory_lock($1, hashtext($2));", previewsLockSpace, key).Scan(&locked); err != nil {
+		discard(conn)
+		return nil, false, fmt.Errorf("can't take lock: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		// lock is released even after cancel of ctx
+		if _, err := conn.ExecContext(context.Background(), "select pg_advisory_unlock($1, hashtext($2));", previewsLockSpace, key); err != nil {
+			discard(conn)
+			return
+		}
+		conn.Close()
+	}, true, nil
+}
@@ -0,0 +1,78 @@
+package pglock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func getMockAndElector(t *testing.T) (sqlmock.Sqlmock, *Elector) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	return mock, NewElector(db, 42, time.Second, logger.NewLogger("error", "json"))
+}
+
+func TestElectorTakesAndLosesLeadership(t *testing.T) {
+	mock, e := getMockAndElector(t)
+
+	// other replica is the leader
+	mock.ExpectQuery(`select pg_try_advisory_lock($1);`).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	e.elect()
+	require.False(t, e.IsLeader())
+
+	mock.ExpectQuery(`select pg_try_advisory_lock($1);`).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	e.elect()
+	require.True(t, e.IsLeader())
+
+	// leader checks its connection instead of taking lock again
+	mock.ExpectQuery(`select 1;`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	e.elect()
+	require.True(t, e.IsLeader())
+
+	mock.ExpectQuery(`select 1;`).WillReturnError(errors.New("connection reset"))
+	e.elect()
+	require.False(t, e.IsLeader())
+}
+
+func TestLockerTryLock(t *testing.T) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	l := NewLocker(db)
+
+	mock.ExpectQuery(`select pg_try_advisory_lock($1, hashtext($2));`).WithArgs(previewsLockSpace, "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec(`select pg_advisory_unlock($1, hashtext($2));`).WithArgs(previewsLockSpace, "hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	unlock, ok, err := l.TryLock(context.Background(), "hash")
+	require.NoError(t, err)
+	require.True(t, ok)
+	unlock()
+
+	// lock held by other replica isn't waited
+	mock.ExpectQuery(`select pg_try_advisory_lock($1, hashtext($2));`).WithArgs(previewsLockSpace, "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	unlock, ok, err = l.TryLock(context.Background(), "hash")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, unlock)
+
+	mock.ExpectQuery(`select pg_try_advisory_lock($1, hashtext($2));`).WithArgs(previewsLockSpace, "hash").
+		WillReturnError(context.DeadlineExceeded)
+	_, _, err = l.TryLock(context.Background(), "hash")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -9,14 +9,20 @@ import (
 // GithubUserBannerInfo is a struct, that describes data that is used to render banner
 type GithubUserBannerInfo struct {
 	Username   string
+	Kind       string
 	BannerType string
+	Layout     string
+	Motion     string
 	Stats      domain.GithubUserStats
 }
 
 func FromDomainBannerInfo(bi domain.BannerInfo) GithubUserBannerInfo {
 	return GithubUserBannerInfo{
 		Username:   bi.Username,
-		BannerType: domain.BannerTypesBackward[bi.BannerType],
+		Kind:       string(bi.Kind),
+		BannerType: string(bi.BannerType),
+		Layout:     string(bi.Layout),
+		Motion:     string(bi.Motion),
 		Stats:      bi.Stats,
 	}
 }
@@ -24,7 +30,10 @@ func FromDomainBannerInfo(bi domain.BannerInfo) GithubUserBannerInfo {
 func (i GithubUserBannerInfo) ToBannerPreviewRequest() bannerPreviewRequest {
 	return bannerPreviewRequest{
 		Username:   i.Username,
+		Kind:       i.Kind,
 		BannerType: i.BannerType,
+		Layout:     i.Layout,
+		Motion:     i.Motion,
 		Stats: bannerPreviewStats{
 			TotalRepos:    i.Stats.TotalRepos,
 			OriginalRepos: i.Stats.OriginalRepos,
@@ -32,14 +41,49 @@ func (i GithubUserBannerInfo) ToBannerPreviewRequest() bannerPreviewRequest {
 			TotalStars:    i.Stats.TotalStars,
 			TotalForks:    i.Stats.TotalForks,
 			Languages:     i.Stats.Languages,
+			Contributions: toPreviewContributions(i.Stats.Contributions),
+			Members:       i.Stats.Members,
+			Repository:    toPreviewRepository(i.Stats.Repository),
 		},
 		FetchedAt: i.Stats.FetchedAt,
 	}
 }
 
+// toPreviewContributions returns nil for nil contributions, so they are omitted
+func toPreviewContributions(c *domain.GithubContributions) *bannerPreviewContributions {
+	if c == nil {
+		return nil
+	}
+	return &bannerPreviewContributions{
+		Total:         c.Total,
+		Commits:       c.Commits,
+		PullRequests:  c.PullRequests,
+		Issues:        c.Issues,
+		Reviews:       c.Reviews,
+		CurrentStreak: c.CurrentStreak,
+		LongestStreak: c.LongestStreak,
+	}
+}
+
+// toPreviewRepository returns nil for nil repository's stats, so they are omitted
+func toPreviewRepository(r *domain.GithubRepoStats) *bannerPreviewRepository {
+	if r == nil {
+		return nil
+	}
+	return &bannerPreviewRepository{
+		OpenIssues:    r.OpenIssues,
+		License:       r.License,
+		LatestRelease: r.LatestRelease,
+		PushedAt:      r.PushedAt,
+	}
+}
+
 type bannerPreviewRequest struct {
 	Username   string             `json:"username"`
+	Kind       string             `json:"kind,omitempty"`
 	BannerType string             `json:"banner_type"`
+	Layout     string             `json:"layout,omitempty"`
+	Motion     string             `json:"motion,omitempty"`
 	Stats      bannerPreviewStats `json:"stats"`
 	FetchedAt  time.Time          `json:"fetched_at"`
 }
@@ -51,4 +95,33 @@ type bannerPreviewStats struct {
 	TotalStars    int            `json:"total_stars"`
 	TotalForks    int            `json:"total_forks"`
 	Languages     map[string]int `json:"languages"`
+	// omitted, if contributions weren't fetched yet
+	Contributions *bannerPreviewContributions `json:"contributions,omitempty"`
+	// public members of organization
+	Members int `json:"members,omitempty"`
+	// details of single repository, omitted for users and organizations
+	Repository *bannerPreviewRepository `json:"repository,omitempty"`
+}
+
+type bannerPreviewRepository struct {
+	OpenIssues    int        `json:"open_issues"`
+	License       *string    `json:"license,omitempty"`
+	LatestRelease *string    `json:"latest_release,omitempty"`
+	PushedAt      *time.Time `json:"pushed_at,omitempty"`
+}
+
+type bannerPreviewContributions struct {
+	Total         int `json:"total"`
+	Commits       int `json:"commits"`
+	PullRequests  int `json:"pull_requests"`
+	Issues        int `json:"issues"`
+	Reviews       int `json:"reviews"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+type themesResponse struct {
+	Themes []struct {
+		Name string `json:"name"`
+	} `json:"themes"`
 }
@@ -37,8 +37,13 @@ const (
 )
 
 // RenderPreview requests renderer service for preview for given bannerInfo
+// format of the image is requested with Accept header, blank format is svg
 func (c *Renderer) RenderPreview(ctx context.Context, bannerInfo domain.BannerInfo) (*domain.Banner, error) {
 	fn := "internal.infrastructure.renderer.Renderer.RenderPreview"
+	format := bannerInfo.Format
+	if format == "" {
+		format = domain.FormatSVG
+	}
 	reqBody, err := json.Marshal(FromDomainBannerInfo(bannerInfo).ToBannerPreviewRequest())
 	if err != nil {
 		c.logger.Error("unexpected error, when marshaling banner preview request", "source", fn, "err", err)
@@ -54,6 +59,7 @@ func (c *Renderer) RenderPreview(ctx context.Context, bannerInfo domain.BannerIn
 		return nil, domain.ErrUnavailable
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", format.ContentType())
 
 	res, err := c.client.Do(req)
 
@@ -88,7 +94,7 @@ func (c *Renderer) RenderPreview(ctx context.Context, bannerInfo domain.BannerIn
 		}
 	}
 	ct := res.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "image/svg+xml") {
+	if !strings.HasPrefix(ct, format.ContentType()) {
 		c.logger.Error("unexpected content type from renderer service", "source", fn, "content-type", ct)
 		return nil, domain.ErrUnavailable
 	}
@@ -101,6 +107,51 @@ func (c *Renderer) RenderPreview(ctx context.Context, bannerInfo domain.BannerIn
 	return &domain.Banner{
 		Username:   bannerInfo.Username,
 		BannerType: bannerInfo.BannerType,
+		Layout:     bannerInfo.Layout,
+		Format:     format,
 		Banner:     resBody,
 	}, nil
 }
+
+// ListThemes requests renderer service for names of the themes, that it can render banners with
+func (c *Renderer) ListThemes(ctx context.Context) ([]domain.BannerType, error) {
+	fn := "internal.infrastructure.renderer.Renderer.ListThemes"
+
+	timeoutContext, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutContext, "GET", c.baseURL+"/themes", nil)
+	if err != nil {
+		c.logger.Error("unexpected error when preparing request", "source", fn, "err", err)
+		return nil, domain.ErrUnavailable
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, ctx.Err()
+		}
+		c.logger.Warn("can't request themes from renderer service", "source", fn, "err", err)
+		return nil, domain.ErrUnavailable
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		c.logger.Error("unexpected status code from renderer service", "source", fn, "code", res.StatusCode)
+		return nil, domain.ErrUnavailable
+	}
+
+	var themesRes themesResponse
+	if err := json.NewDecoder(res.Body).Decode(&themesRes); err != nil {
+		c.logger.Error("can't decode themes response", "source", fn, "err", err)
+		return nil, domain.ErrUnavailable
+	}
+
+	themes := make([]domain.BannerType, 0, len(themesRes.Themes))
+	for _, theme := range themesRes.Themes {
+		if theme.Name != "" {
+			themes = append(themes, domain.BannerType(theme.Name))
+		}
+	}
+	return themes, nil
+}
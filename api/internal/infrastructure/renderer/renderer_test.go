@@ -59,9 +59,39 @@ func TestRenderer_RenderPreview(t *testing.T) {
 				Body: &StringBody{strings.NewReader("<svg></svg>")},
 			},
 			bannerInfo: domain.BannerInfo{BannerType: domain.TypeDark},
-			want:       &domain.Banner{Username: "", BannerType: domain.TypeDark, Banner: baseBodyBytes},
+			want:       &domain.Banner{Username: "", BannerType: domain.TypeDark, Format: domain.FormatSVG, Banner: baseBodyBytes},
 			wantedErr:  nil,
 		},
+		{
+			name:       "png",
+			httpClient: http.DefaultClient,
+			logger:     logger.NewLogger("info", "json"),
+			httpResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header: newHeader(map[string]string{
+					"Content-Type": "image/png",
+				}),
+				Body: &StringBody{strings.NewReader("png stuff")},
+			},
+			bannerInfo: domain.BannerInfo{BannerType: domain.TypeDark, Format: domain.FormatPNG},
+			want:       &domain.Banner{Username: "", BannerType: domain.TypeDark, Format: domain.FormatPNG, Banner: []byte("png stuff")},
+			wantedErr:  nil,
+		},
+		{
+			name:       "svg-instead-of-png",
+			httpClient: http.DefaultClient,
+			logger:     logger.NewLogger("info", "json"),
+			httpResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header: newHeader(map[string]string{
+					"Content-Type": "image/svg+xml",
+				}),
+				Body: &StringBody{strings.NewReader("<svg></svg>")},
+			},
+			bannerInfo: domain.BannerInfo{Format: domain.FormatPNG},
+			want:       nil,
+			wantedErr:  domain.ErrUnavailable,
+		},
 		{
 			name:       "wrong-format",
 			httpClient: http.DefaultClient,
@@ -103,3 +133,20 @@ func TestRenderer_RenderPreview(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderer_ListThemes(t *testing.T) {
+	httpmock.Activate(t)
+	rendererBaseUrl := "https://renderer"
+
+	httpmock.RegisterResponder("GET", rendererBaseUrl+"/themes",
+		httpmock.NewStringResponder(http.StatusOK, `{"themes":[{"name":"dark"},{"name":"default"},{"name":""}]}`))
+
+	c := renderer.NewRenderer(http.DefaultClient, logger.NewLogger("info", "json"), rendererBaseUrl)
+	got, err := c.ListThemes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []domain.BannerType{domain.TypeDark, domain.TypeDefault}, got)
+
+	httpmock.RegisterResponder("GET", rendererBaseUrl+"/themes", httpmock.NewStringResponder(http.StatusUnauthorized, `{"error":"unauthorized"}`))
+	_, err = c.ListThemes(context.Background())
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+}
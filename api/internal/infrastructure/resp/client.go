@@ -0,0 +1,223 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Addr     string
+	Password string
+	DB       int
+	// PoolSize is the max count of idle connections
+	PoolSize int
+	// Timeout limits every command, that's context has no deadline
+	Timeout time.Duration
+}
+
+// ParseURL parses redis://[:password@]host:port[/db]
+func ParseURL(raw string) (Config, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "redis" || u.Host == "" {
+		return Config{}, ErrInvalidURL
+	}
+	cfg := Config{Addr: u.Host}
+	if _, port, _ := net.SplitHostPort(u.Host); port == "" {
+		cfg.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		cfg.Password = password
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if cfg.DB, err = strconv.Atoi(db); err != nil {
+			return Config{}, ErrInvalidURL
+		}
+	}
+	return cfg, nil
+}
+
+// Client talks RESP protocol to redis compatible server
+// connections are dialed, when they are needed, so client can be created, while server is down
+type Client struct {
+	cfg    Config
+	dialer net.Dialer
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 10
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	return &Client{cfg: cfg}
+}
+
+// Get returns value of key, ErrNil is returned, if key doesn't exist
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNil
+	}
+	value, ok := res.([]byte)
+	if !ok {
+		return nil, ErrUnexpectedReply
+	}
+	return value, nil
+}
+
+// Set sets value of key, that expires after ttl, ttl less than a millisecond doesn't expire
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := [][]byte{[]byte("SET"), []byte(key), value}
+	if ttl >= time.Millisecond {
+		args = append(args, []byte("PX"), []byte(strconv.FormatInt(ttl.Milliseconds(), 10)))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	_, err := c.Do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Do sends command and returns its reply, error reply is returned as ReplyError
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	return c.do(ctx, bulkStrings(args)...)
+}
+
+func (c *Client) do(ctx context.Context, args ...[]byte) (any, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := cn.roundTrip(ctx, args...)
+	if err != nil {
+		// state of connection is unknown after network error
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+
+	if replyErr, ok := res.(ReplyError); ok {
+		return nil, replyErr
+	}
+	return res, nil
+}
+
+func (cn *conn) roundTrip(ctx context.Context, args ...[]byte) (any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeCommand(cn.w, args...); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+// get returns idle connection or dials the new one
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	netConn, err := c.dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if c.cfg.Password != "" {
+		if err := cn.handshake(ctx, "AUTH", c.cfg.Password); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("can't authenticate: %w", err)
+		}
+	}
+	if c.cfg.DB != 0 {
+		if err := cn.handshake(ctx, "SELECT", strconv.Itoa(c.cfg.DB)); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("can't select database: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+func (cn *conn) handshake(ctx context.Context, args ...string) error {
+	res, err := cn.roundTrip(ctx, bulkStrings(args)...)
+	if err != nil {
+		return err
+	}
+	if replyErr, ok := res.(ReplyError); ok {
+		return replyErr
+	}
+	return nil
+}
+
+// put returns connection to the pool, connection is closed, if pool is full
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= c.cfg.PoolSize {
+		cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// Close closes idle connections, commands after Close return ErrClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	var errs []error
+	for _, cn := range c.idle {
+		errs = append(errs, cn.Close())
+	}
+	c.idle = nil
+	return errors.Join(errs...)
+}
+
+func bulkStrings(args []string) [][]byte {
+	res := make([][]byte, len(args))
+	for i, arg := range args {
+		res[i] = []byte(arg)
+	}
+	return res
+}
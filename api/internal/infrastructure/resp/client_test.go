@@ -0,0 +1,79 @@
+package resp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp/resptest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	cfg, err := ParseURL("redis://:secret@cache:6380/2")
+	require.NoError(t, err)
+	require.Equal(t, Config{Addr: "cache:6380", Password: "secret", DB: 2}, cfg)
+
+	cfg, err = ParseURL("redis://cache")
+	require.NoError(t, err)
+	require.Equal(t, Config{Addr: "cache:6379"}, cfg)
+
+	_, err = ParseURL("http://cache:6379")
+	require.ErrorIs(t, err, ErrInvalidURL)
+	_, err = ParseURL("redis://cache:6379/db")
+	require.ErrorIs(t, err, ErrInvalidURL)
+}
+
+func TestClientGetSetDel(t *testing.T) {
+	srv := resptest.NewServerWithPassword(t, "secret")
+	c := NewClient(Config{Addr: srv.Addr, Password: "secret", DB: 1})
+	t.Cleanup(func() { c.Close() })
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, "missing")
+	require.ErrorIs(t, err, ErrNil)
+
+	// binary values are sent as they are
+	value := []byte("line\r\nwith\x00bytes")
+	require.NoError(t, c.Set(ctx, "key", value, 0))
+	got, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+
+	require.NoError(t, c.Set(ctx, "short", []byte("v"), 20*time.Millisecond))
+	require.Eventually(t, func() bool {
+		_, err := c.Get(ctx, "short")
+		return err == ErrNil
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, c.Del(ctx, "key"))
+	_, err = c.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrNil)
+
+	_, err = c.Do(ctx, "UNKNOWN")
+	var replyErr ReplyError
+	require.ErrorAs(t, err, &replyErr)
+}
+
+func TestClientWrongPassword(t *testing.T) {
+	srv := resptest.NewServerWithPassword(t, "secret")
+	c := NewClient(Config{Addr: srv.Addr, Password: "wrong"})
+
+	_, err := c.Get(context.Background(), "key")
+	var replyErr ReplyError
+	require.ErrorAs(t, err, &replyErr)
+}
+
+func TestClientServerDown(t *testing.T) {
+	srv := resptest.NewServer(t)
+	c := NewClient(Config{Addr: srv.Addr, Timeout: 100 * time.Millisecond})
+	require.NoError(t, c.Set(context.Background(), "key", []byte("v"), 0))
+	srv.Close()
+
+	_, err := c.Get(context.Background(), "key")
+	require.Error(t, err)
+
+	require.NoError(t, c.Close())
+	_, err = c.Get(context.Background(), "key")
+	require.ErrorIs(t, err, ErrClosed)
+}
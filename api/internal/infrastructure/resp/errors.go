@@ -0,0 +1,18 @@
+package resp
+
+import "errors"
+
+var (
+	// ErrNil is returned, when key doesn't exist
+	ErrNil             = errors.New("nil reply")
+	ErrInvalidURL      = errors.New("invalid url, expected redis://[:password@]host:port[/db]")
+	ErrUnexpectedReply = errors.New("unexpected reply")
+	ErrClosed          = errors.New("client is closed")
+)
+
+// ReplyError is an error reply of the server, like "ERR unknown command"
+type ReplyError string
+
+func (e ReplyError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,243 @@
+// Package resptest provides in-process stand-in of redis compatible server for tests
+// it supports PING, AUTH, SELECT, GET, SET ( with PX and EX ), DEL and EXISTS
+package resptest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Server struct {
+	Addr string
+
+	// password, that AUTH expects, blank server doesn't require AUTH
+	password string
+	listener net.Listener
+	mu       sync.Mutex
+	values   map[string]entry
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+type testingT interface {
+	Fatalf(format string, args ...any)
+	Cleanup(func())
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewServer starts server on random local port, it's stopped on test cleanup
+func NewServer(t testingT) *Server {
+	return NewServerWithPassword(t, "")
+}
+
+// NewServerWithPassword starts server, that requires AUTH with password before other commands
+func NewServerWithPassword(t testingT, password string) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("resptest: can't listen: %v", err)
+	}
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		password: password,
+		listener: listener,
+		values:   map[string]entry{},
+		conns:    map[net.Conn]struct{}{},
+	}
+	s.wg.Go(s.serve)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close stops server and closes its connections, like server going down
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Keys returns count of keys, that aren't expired
+func (s *Server) Keys() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for key := range s.values {
+		if _, ok := s.getLocked(key); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Go(func() { s.handle(conn) })
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	authorized := s.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(args[0])
+		switch {
+		case cmd == "AUTH":
+			if len(args) == 2 && args[1] == s.password {
+				authorized = true
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid password\r\n")
+			}
+		case !authorized:
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		default:
+			s.exec(w, cmd, args[1:])
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(w *bufio.Writer, cmd string, args []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch cmd {
+	case "PING", "SELECT":
+		w.WriteString("+OK\r\n")
+	case "GET":
+		if len(args) != 1 {
+			w.WriteString("-ERR wrong number of arguments\r\n")
+			return
+		}
+		value, ok := s.getLocked(args[0])
+		if !ok {
+			w.WriteString("$-1\r\n")
+			return
+		}
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		if len(args) != 2 && len(args) != 4 {
+			w.WriteString("-ERR syntax error\r\n")
+			return
+		}
+		e := entry{value: []byte(args[1])}
+		if len(args) == 4 {
+			n, err := strconv.Atoi(args[3])
+			if err != nil || n <= 0 {
+				w.WriteString("-ERR invalid expire time\r\n")
+				return
+			}
+			switch strings.ToUpper(args[2]) {
+			case "PX":
+				e.expiresAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+			case "EX":
+				e.expiresAt = time.Now().Add(time.Duration(n) * time.Second)
+			default:
+				w.WriteString("-ERR syntax error\r\n")
+				return
+			}
+		}
+		s.values[args[0]] = e
+		w.WriteString("+OK\r\n")
+	case "DEL", "EXISTS":
+		n := 0
+		for _, key := range args {
+			if _, ok := s.getLocked(key); ok {
+				n++
+				if cmd == "DEL" {
+					delete(s.values, key)
+				}
+			}
+		}
+		fmt.Fprintf(w, ":%d\r\n", n)
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", cmd)
+	}
+}
+
+func (s *Server) getLocked(key string) ([]byte, bool) {
+	e, ok := s.values[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		delete(s.values, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// readCommand reads array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("resptest: expected array, got %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("resptest: invalid array length %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("resptest: invalid bulk string length %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
+	"github.com/hurtki/github-banners/api/internal/domain"
 	"github.com/hurtki/github-banners/api/internal/logger"
 )
 
@@ -44,20 +46,23 @@ func NewClient(baseURL string, httpClient *http.Client, logger logger.Logger) *C
 	}
 }
 
-func (c *Client) SaveBanner(ctx context.Context, bannerID string, svg string) (string, error) {
+// SaveBanner saves banner's image of given format
+// returns relative url of the saved image
+func (c *Client) SaveBanner(ctx context.Context, bannerID string, data []byte, format domain.BannerFormat) (string, error) {
 	fn := "internal.infrastructure.storage.client.SaveBanner"
 	start := time.Now()
 
 	c.logger.Debug("saving banner to storage",
 		"source", fn,
 		"banner_id", bannerID,
+		"format", format,
 	)
 
-	encoded := base64.StdEncoding.EncodeToString([]byte(svg))
+	encoded := base64.StdEncoding.EncodeToString(data)
 	reqBody := SaveRequest{
 		URLPath:      bannerID,
 		BannerData:   encoded,
-		BannerFormat: "svg",
+		BannerFormat: string(format),
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -131,3 +136,58 @@ func (c *Client) SaveBanner(ctx context.Context, bannerID string, svg string) (s
 	)
 	return SaveResp.URL, nil
 }
+
+// DeleteBanner removes banner's image from storage
+// deleting of not existing banner is not an error
+func (c *Client) DeleteBanner(ctx context.Context, bannerID string) error {
+	fn := "internal.infrastructure.storage.client.DeleteBanner"
+	start := time.Now()
+
+	c.logger.Debug("deleting banner from storage",
+		"source", fn,
+		"banner_id", bannerID,
+	)
+
+	url := c.baseURL + "/banners/" + neturl.PathEscape(bannerID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		c.logger.Error("failed to create storage request",
+			"source", fn,
+			"banner_id", bannerID,
+			"err", err,
+		)
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("storage request failed",
+			"source", fn,
+			"banner_id", bannerID,
+			"err", err,
+		)
+		return fmt.Errorf("storage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	duration := time.Since(start)
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		c.logger.Error("storage returned non-success status",
+			"source", fn,
+			"banner_id", bannerID,
+			"status", resp.StatusCode,
+			"duration", duration,
+			"body", string(respBody),
+		)
+		return fmt.Errorf("storage returned status %d", resp.StatusCode)
+	}
+
+	c.logger.Debug("banner deleted successfully",
+		"source", fn,
+		"banner_id", bannerID,
+		"duration", duration.String(),
+	)
+	return nil
+}
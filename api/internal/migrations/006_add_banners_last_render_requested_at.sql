@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS last_render_requested_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE banners DROP COLUMN IF EXISTS last_render_requested_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS banner_owners (
+    github_username_normalized TEXT PRIMARY KEY,
+    challenge TEXT,
+    challenge_expires_at TIMESTAMP,
+    token_hash TEXT,
+    verified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS banner_owners;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS layout TEXT NOT NULL DEFAULT 'default';
+
+-- +goose Down
+ALTER TABLE banners DROP COLUMN IF EXISTS layout;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS motion TEXT NOT NULL DEFAULT 'on';
+
+-- +goose Down
+ALTER TABLE banners DROP COLUMN IF EXISTS motion;
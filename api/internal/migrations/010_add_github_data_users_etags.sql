@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE github_data.users ADD COLUMN IF NOT EXISTS etags JSONB;
+
+-- +goose Down
+ALTER TABLE github_data.users DROP COLUMN IF EXISTS etags;
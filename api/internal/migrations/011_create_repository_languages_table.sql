@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE github_data.repositories ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS github_data.repository_languages (
+    repo_github_id BIGINT NOT NULL REFERENCES github_data.repositories(github_id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    bytes BIGINT NOT NULL,
+    PRIMARY KEY (repo_github_id, language)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_data.repository_languages;
+ALTER TABLE github_data.repositories DROP COLUMN IF EXISTS name;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS github_data.user_contributions (
+    username_normalized TEXT PRIMARY KEY REFERENCES github_data.users(username_normalized) ON DELETE CASCADE,
+    total INT NOT NULL,
+    commits INT NOT NULL,
+    pull_requests INT NOT NULL,
+    issues INT NOT NULL,
+    reviews INT NOT NULL,
+    current_streak INT NOT NULL,
+    longest_streak INT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_data.user_contributions;
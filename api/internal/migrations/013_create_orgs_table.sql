@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS github_data.orgs (
+    login_normalized TEXT PRIMARY KEY,
+    login TEXT NOT NULL,
+    name TEXT,
+    description TEXT,
+    location TEXT,
+    public_repos_count INT NOT NULL,
+    members_count INT NOT NULL,
+    repositories JSONB NOT NULL,
+    fetched_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE banners ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'user';
+
+-- +goose Down
+ALTER TABLE banners DROP COLUMN IF EXISTS kind;
+DROP TABLE IF EXISTS github_data.orgs;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- repository banners store repositories of any owner: users without stored profile and organizations
+ALTER TABLE github_data.repositories DROP CONSTRAINT IF EXISTS fk_repository_owner;
+
+-- project's details are filled only for repositories of repository banners, fetched_at is NULL for other ones
+ALTER TABLE github_data.repositories
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS open_issues_count INT,
+    ADD COLUMN IF NOT EXISTS license TEXT,
+    ADD COLUMN IF NOT EXISTS latest_release TEXT,
+    ADD COLUMN IF NOT EXISTS fetched_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_repositories_full_name ON github_data.repositories(owner_username_normalized, lower(name));
+
+-- +goose Down
+DROP INDEX IF EXISTS github_data.idx_repositories_full_name;
+
+ALTER TABLE github_data.repositories
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS open_issues_count,
+    DROP COLUMN IF EXISTS license,
+    DROP COLUMN IF EXISTS latest_release,
+    DROP COLUMN IF EXISTS fetched_at;
+
+DELETE FROM github_data.repositories r
+WHERE NOT EXISTS (
+    SELECT 1 FROM github_data.users u WHERE u.username_normalized = r.owner_username_normalized
+);
+
+ALTER TABLE github_data.repositories
+ADD CONSTRAINT fk_repository_owner
+    FOREIGN KEY (owner_username_normalized)
+    REFERENCES github_data.users(username_normalized) ON DELETE CASCADE;
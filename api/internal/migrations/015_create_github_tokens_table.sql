@@ -0,0 +1,13 @@
+-- +goose Up
+-- github tokens, added at runtime, and marks of disabled ones
+CREATE TABLE IF NOT EXISTS github_tokens (
+    id TEXT PRIMARY KEY,
+    host TEXT NOT NULL DEFAULT '',
+    -- AES-GCM encrypted token, null for disabled mark of token, that isn't stored ( from GITHUB_TOKENS )
+    token_encrypted BYTEA,
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    disabled_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_tokens;
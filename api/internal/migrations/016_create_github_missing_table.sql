@@ -0,0 +1,11 @@
+-- +goose Up
+-- users, organizations and repositories, that github didn't have, so they aren't fetched again until expires_at
+CREATE TABLE IF NOT EXISTS github_missing (
+    kind TEXT NOT NULL,
+    username_normalized TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, username_normalized)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_missing;
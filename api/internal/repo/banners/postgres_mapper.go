@@ -7,24 +7,68 @@ import (
 
 func (r *PostgresRepo) bannerTypeToDB(bt domain.BannerType) (string, error) {
 	fn := "internal.repo.banners.PostgresRepo.bannerTypeToDB"
-	v, ok := domain.BannerTypesBackward[bt]
-	if !ok {
+	if bt == "" {
 		if r.logger != nil {
-			r.logger.Error("unknown banner type", "source", fn, "banner_type", bt)
+			r.logger.Error("blank banner type", "source", fn)
 		}
-		return "", repoerr.ErrRepoInternal{Note: "unknown banner type"}
+		return "", repoerr.ErrRepoInternal{Note: "blank banner type"}
 	}
-	return v, nil
+	return string(bt), nil
 }
 
 func (r *PostgresRepo) bannerTypeFromDB(v string) (domain.BannerType, error) {
 	fn := "internal.repo.banners.PostgresRepo.bannerTypeFromDB"
-	bt, ok := domain.BannerTypes[v]
-	if !ok {
+	if v == "" {
 		if r.logger != nil {
-			r.logger.Error("unknown banner type", "source", fn, "banner_type", v)
+			r.logger.Error("blank banner type", "source", fn)
 		}
-		return 0, repoerr.ErrRepoInternal{Note: "unknown banner type"}
+		return "", repoerr.ErrRepoInternal{Note: "blank banner type"}
 	}
-	return bt, nil
+	return domain.BannerType(v), nil
+}
+
+// layoutToDB stores blank layout as default one
+func layoutToDB(l domain.BannerLayout) string {
+	if l == "" {
+		return string(domain.LayoutDefault)
+	}
+	return string(l)
+}
+
+// motionToDB stores blank motion as turned on
+func motionToDB(m domain.BannerMotion) string {
+	if m == "" {
+		return string(domain.MotionOn)
+	}
+	return string(m)
+}
+
+func motionFromDB(v string) domain.BannerMotion {
+	if v == "" {
+		return domain.MotionOn
+	}
+	return domain.BannerMotion(v)
+}
+
+// layoutFromDB keeps unknown layouts as is, so they are passed to renderer and rejected there
+func layoutFromDB(v string) domain.BannerLayout {
+	if v == "" {
+		return domain.LayoutDefault
+	}
+	return domain.BannerLayout(v)
+}
+
+// kindToDB stores blank kind as user
+func kindToDB(k domain.BannerKind) string {
+	if k == "" {
+		return string(domain.KindUser)
+	}
+	return string(k)
+}
+
+func kindFromDB(v string) domain.BannerKind {
+	if v == "" {
+		return domain.KindUser
+	}
+	return domain.BannerKind(v)
 }
@@ -12,20 +12,47 @@ import (
 
 func (r *PostgresRepo) GetActiveBanners(ctx context.Context) ([]domain.LTBannerMetadata, error) {
 	fn := "internal.repo.banners.PostgresRepo.GetActiveBanners"
-	const q = `select github_username_normalized, banner_type, storage_path from banners where is_active = true`
+	const q = `select github_username_normalized, kind, banner_type, layout, motion, storage_path from banners where is_active = true`
 	rows, err := r.db.QueryContext(ctx, q)
 	if err != nil {
 		r.logger.Error("unexpected error when querying banners", "source", fn, "err", err)
 		return nil, repoerr.ErrRepoInternal{Note: err.Error()}
 	}
 
+	return r.scanActiveBanners(fn, rows)
+}
+
+// GetOwnerActiveBanners returns active banners of github user or organization
+// and active banners of repositories, that are owned by it
+func (r *PostgresRepo) GetOwnerActiveBanners(ctx context.Context, owner string) ([]domain.LTBannerMetadata, error) {
+	fn := "internal.repo.banners.PostgresRepo.GetOwnerActiveBanners"
+	if owner == "" {
+		return nil, repoerr.ErrEmptyField{Field: "github_username"}
+	}
+	normalized := domain.NormalizeGithubUsername(owner)
+
+	const q = `
+	select github_username_normalized, kind, banner_type, layout, motion, storage_path from banners
+	where is_active = true and (github_username_normalized = $1 or starts_with(github_username_normalized, $2));`
+	rows, err := r.db.QueryContext(ctx, q, normalized, normalized+"/")
+	if err != nil {
+		r.logger.Error("unexpected error when querying banners", "source", fn, "err", err)
+		return nil, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	return r.scanActiveBanners(fn, rows)
+}
+
+// scanActiveBanners reads rows of github_username_normalized, kind, banner_type, layout, motion and storage_path
+// and closes them
+func (r *PostgresRepo) scanActiveBanners(fn string, rows *sql.Rows) ([]domain.LTBannerMetadata, error) {
 	defer rows.Close()
 
 	res := make([]domain.LTBannerMetadata, 0)
 
 	for rows.Next() {
-		var username, btStr, path string
-		if err := rows.Scan(&username, &btStr, &path); err != nil {
+		var username, kind, btStr, layout, motion, path string
+		if err := rows.Scan(&username, &kind, &btStr, &layout, &motion, &path); err != nil {
 			r.logger.Error("unexpected error when scanning banners", "source", fn, "err", err)
 			return nil, repoerr.ErrRepoInternal{Note: err.Error()}
 		}
@@ -37,7 +64,10 @@ func (r *PostgresRepo) GetActiveBanners(ctx context.Context) ([]domain.LTBannerM
 
 		res = append(res, domain.LTBannerMetadata{
 			Username:   username,
+			Kind:       kindFromDB(kind),
 			BannerType: bt,
+			Layout:     layoutFromDB(layout),
+			Motion:     motionFromDB(motion),
 			UrlPath:    path,
 			Active:     true,
 		})
@@ -66,15 +96,20 @@ func (r *PostgresRepo) SaveBanner(ctx context.Context, b domain.LTBannerMetadata
 		return err
 	}
 
+	// banner is saved only after it was rendered, so last_render_requested_at is updated too
 	const q = `
-	insert into banners (github_username_normalized, banner_type, storage_path, is_active)
-	values ($1, $2, $3, $4)
+	insert into banners (github_username_normalized, banner_type, storage_path, is_active, layout, motion, kind, last_render_requested_at)
+	values ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
 	on conflict (github_username_normalized, banner_type) do update set
 		is_active = EXCLUDED.is_active,
-		storage_path = EXCLUDED.storage_path;
+		storage_path = EXCLUDED.storage_path,
+		layout = EXCLUDED.layout,
+		motion = EXCLUDED.motion,
+		updated_at = CURRENT_TIMESTAMP,
+		last_render_requested_at = EXCLUDED.last_render_requested_at;
 	`
 
-	_, err = r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(b.Username), btStr, b.UrlPath, b.Active)
+	_, err = r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(b.Username), btStr, b.UrlPath, b.Active, layoutToDB(b.Layout), motionToDB(b.Motion), kindToDB(b.Kind))
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") ||
 			strings.Contains(err.Error(), "unique constraint") {
@@ -94,10 +129,10 @@ func (r *PostgresRepo) DeactivateBanner(ctx context.Context, githubUsername stri
 
 	const q = `
 	update banners
-	set is_active = false
+	set is_active = false, updated_at = CURRENT_TIMESTAMP
 	where github_username_normalized = $1 and banner_type = $2 and is_active = true`
 
-	res, err := r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), domain.BannerTypesBackward[bannerType])
+	res, err := r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), string(bannerType))
 	if err != nil {
 		r.logger.Error("unexpected error when deactivating banner", "source", fn, "err", err)
 		return repoerr.ErrRepoInternal{Note: err.Error()}
@@ -118,11 +153,14 @@ func (r *PostgresRepo) DeactivateBanner(ctx context.Context, githubUsername stri
 func (r *PostgresRepo) GetBanner(ctx context.Context, githubUsername string, bannerType domain.BannerType) (domain.LTBannerMetadata, error) {
 	fn := "internal.repo.banners.PostgresRepo.GetBanner"
 	const q = `
-	select storage_path, is_active from banners
+	select storage_path, kind, layout, motion, is_active, created_at, updated_at, last_render_requested_at from banners
 	where github_username_normalized = $1 and banner_type = $2;`
 	meta := domain.LTBannerMetadata{Username: githubUsername, BannerType: bannerType}
 
-	err := r.db.QueryRowContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), domain.BannerTypesBackward[bannerType]).Scan(&meta.UrlPath, &meta.Active)
+	var kind, layout, motion string
+	var lastRenderRequestedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), string(bannerType)).
+		Scan(&meta.UrlPath, &kind, &layout, &motion, &meta.Active, &meta.CreatedAt, &meta.UpdatedAt, &lastRenderRequestedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.LTBannerMetadata{}, repoerr.ErrNothingFound
@@ -130,5 +168,99 @@ func (r *PostgresRepo) GetBanner(ctx context.Context, githubUsername string, ban
 		r.logger.Error("unexpected error when getting banner", "source", fn, "err", err)
 		return domain.LTBannerMetadata{}, repoerr.ErrRepoInternal{Note: err.Error()}
 	}
+	meta.Kind = kindFromDB(kind)
+	meta.Layout = layoutFromDB(layout)
+	meta.Motion = motionFromDB(motion)
+	if lastRenderRequestedAt.Valid {
+		meta.LastRenderRequestedAt = &lastRenderRequestedAt.Time
+	}
 	return meta, nil
 }
+
+// ListBanners returns page of user's banners ( both active and not ), ordered by creation time
+// and total count of user's banners
+func (r *PostgresRepo) ListBanners(ctx context.Context, githubUsername string, limit, offset int) ([]domain.LTBannerMetadata, int, error) {
+	fn := "internal.repo.banners.PostgresRepo.ListBanners"
+	if githubUsername == "" {
+		return nil, 0, repoerr.ErrEmptyField{Field: "github_username"}
+	}
+	normalized := domain.NormalizeGithubUsername(githubUsername)
+
+	const countQ = `select count(*) from banners where github_username_normalized = $1;`
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQ, normalized).Scan(&total); err != nil {
+		r.logger.Error("unexpected error when counting banners", "source", fn, "err", err)
+		return nil, 0, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	const q = `
+	select banner_type, kind, layout, motion, storage_path, is_active, created_at, updated_at, last_render_requested_at from banners
+	where github_username_normalized = $1
+	order by created_at, banner_type
+	limit $2 offset $3;`
+	rows, err := r.db.QueryContext(ctx, q, normalized, limit, offset)
+	if err != nil {
+		r.logger.Error("unexpected error when querying banners", "source", fn, "err", err)
+		return nil, 0, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	defer rows.Close()
+
+	res := make([]domain.LTBannerMetadata, 0, limit)
+
+	for rows.Next() {
+		meta := domain.LTBannerMetadata{Username: normalized}
+		var btStr, kind, layout, motion string
+		var lastRenderRequestedAt sql.NullTime
+		if err := rows.Scan(&btStr, &kind, &layout, &motion, &meta.UrlPath, &meta.Active, &meta.CreatedAt, &meta.UpdatedAt, &lastRenderRequestedAt); err != nil {
+			r.logger.Error("unexpected error when scanning banners", "source", fn, "err", err)
+			return nil, 0, repoerr.ErrRepoInternal{Note: err.Error()}
+		}
+
+		meta.BannerType, err = r.bannerTypeFromDB(btStr)
+		if err != nil {
+			return nil, 0, err
+		}
+		meta.Kind = kindFromDB(kind)
+		meta.Layout = layoutFromDB(layout)
+		meta.Motion = motionFromDB(motion)
+		if lastRenderRequestedAt.Valid {
+			meta.LastRenderRequestedAt = &lastRenderRequestedAt.Time
+		}
+
+		res = append(res, meta)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("unexpected error after iterating banners", "source", fn, "err", err)
+		return nil, 0, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	return res, total, nil
+}
+
+// MarkRenderRequested sets time of the last requested render of the banner to current time
+func (r *PostgresRepo) MarkRenderRequested(ctx context.Context, githubUsername string, bannerType domain.BannerType) error {
+	fn := "internal.repo.banners.PostgresRepo.MarkRenderRequested"
+	const q = `
+	update banners
+	set last_render_requested_at = CURRENT_TIMESTAMP
+	where github_username_normalized = $1 and banner_type = $2`
+
+	res, err := r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), string(bannerType))
+	if err != nil {
+		r.logger.Error("unexpected error when updating banner's render request time", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.logger.Error("unexpected error when reading affected rows", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	if affected == 0 {
+		return repoerr.ErrNothingFound
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package banners_repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+type LoggerMock struct{}
+
+func (m LoggerMock) Debug(a string, b ...any)    {}
+func (m LoggerMock) Info(a string, b ...any)     {}
+func (m LoggerMock) Warn(a string, b ...any)     {}
+func (m LoggerMock) Error(a string, b ...any)    {}
+func (m LoggerMock) With(a ...any) logger.Logger { return m }
+
+func getMockAndRepo(t *testing.T) (sqlmock.Sqlmock, *PostgresRepo) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return mock, NewPostgresRepo(db, LoggerMock{})
+}
+
+func TestGetBannerSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rendered := created.Add(time.Hour)
+
+	mock.ExpectQuery(`
+	select storage_path, kind, layout, motion, is_active, created_at, updated_at, last_render_requested_at from banners
+	where github_username_normalized = $1 and banner_type = $2;`).
+		WithArgs("hurtki", "dark").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_path", "kind", "layout", "motion", "is_active", "created_at", "updated_at", "last_render_requested_at"}).
+			AddRow("hurtki-dark", "user", "wide", "off", true, created, created, rendered))
+
+	meta, err := repo.GetBanner(context.TODO(), "HurtKi", domain.TypeDark)
+	require.NoError(t, err)
+	require.Equal(t, domain.LTBannerMetadata{
+		Username:              "HurtKi",
+		Kind:                  domain.KindUser,
+		BannerType:            domain.TypeDark,
+		Layout:                domain.LayoutWide,
+		Motion:                domain.MotionOff,
+		UrlPath:               "hurtki-dark",
+		Active:                true,
+		CreatedAt:             created,
+		UpdatedAt:             created,
+		LastRenderRequestedAt: &rendered,
+	}, meta)
+}
+
+func TestGetBannerNotFound(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectQuery(`
+	select storage_path, kind, layout, motion, is_active, created_at, updated_at, last_render_requested_at from banners
+	where github_username_normalized = $1 and banner_type = $2;`).
+		WithArgs("hurtki", "dark").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_path", "kind", "layout", "motion", "is_active", "created_at", "updated_at", "last_render_requested_at"}))
+
+	_, err := repo.GetBanner(context.TODO(), "hurtki", domain.TypeDark)
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
+
+func TestListBannersSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`select count(*) from banners where github_username_normalized = $1;`).
+		WithArgs("hurtki").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	mock.ExpectQuery(`
+	select banner_type, kind, layout, motion, storage_path, is_active, created_at, updated_at, last_render_requested_at from banners
+	where github_username_normalized = $1
+	order by created_at, banner_type
+	limit $2 offset $3;`).
+		WithArgs("hurtki", 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"banner_type", "kind", "layout", "motion", "storage_path", "is_active", "created_at", "updated_at", "last_render_requested_at"}).
+			AddRow("default", "user", "default", "on", "hurtki-default", false, created, created, nil).
+			AddRow("dark", "org", "compact", "off", "org_hurtki-dark", true, created, created, created))
+
+	banners, total, err := repo.ListBanners(context.TODO(), "HURTKI", 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, banners, 2)
+
+	require.Equal(t, domain.TypeDefault, banners[0].BannerType)
+	require.False(t, banners[0].Active)
+	require.Nil(t, banners[0].LastRenderRequestedAt)
+
+	require.Equal(t, domain.TypeDark, banners[1].BannerType)
+	require.Equal(t, domain.KindOrg, banners[1].Kind)
+	require.Equal(t, domain.LayoutCompact, banners[1].Layout)
+	require.Equal(t, domain.MotionOff, banners[1].Motion)
+	require.Equal(t, "hurtki", banners[1].Username)
+	require.Equal(t, created, *banners[1].LastRenderRequestedAt)
+}
+
+func TestListBannersEmptyUsername(t *testing.T) {
+	_, repo := getMockAndRepo(t)
+
+	_, _, err := repo.ListBanners(context.TODO(), "", 20, 0)
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "github_username"})
+}
+
+func TestGetOwnerActiveBanners(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectQuery(`
+	select github_username_normalized, kind, banner_type, layout, motion, storage_path from banners
+	where is_active = true and (github_username_normalized = $1 or starts_with(github_username_normalized, $2));`).
+		WithArgs("hurtki", "hurtki/").
+		WillReturnRows(sqlmock.NewRows([]string{"github_username_normalized", "kind", "banner_type", "layout", "motion", "storage_path"}).
+			AddRow("hurtki", "user", "dark", "wide", "on", "hurtki-dark").
+			AddRow("hurtki/banners", "repository", "default", "repository", "on", "repo_hurtki_banners-default"))
+
+	banners, err := repo.GetOwnerActiveBanners(context.TODO(), "HurtKi")
+	require.NoError(t, err)
+	require.Len(t, banners, 2)
+	require.Equal(t, domain.KindUser, banners[0].Kind)
+	require.Equal(t, "hurtki/banners", banners[1].Username)
+	require.Equal(t, domain.KindRepository, banners[1].Kind)
+	require.True(t, banners[1].Active)
+}
+
+func TestMarkRenderRequestedNotFound(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(`
+	update banners
+	set last_render_requested_at = CURRENT_TIMESTAMP
+	where github_username_normalized = $1 and banner_type = $2`).
+		WithArgs("hurtki", "dark").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.MarkRenderRequested(context.TODO(), "hurtki", domain.TypeDark)
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
+
+func TestSaveBannerStoresDefaultLayout(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(`
+	insert into banners (github_username_normalized, banner_type, storage_path, is_active, layout, motion, kind, last_render_requested_at)
+	values ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
+	on conflict (github_username_normalized, banner_type) do update set
+		is_active = EXCLUDED.is_active,
+		storage_path = EXCLUDED.storage_path,
+		layout = EXCLUDED.layout,
+		motion = EXCLUDED.motion,
+		updated_at = CURRENT_TIMESTAMP,
+		last_render_requested_at = EXCLUDED.last_render_requested_at;
+	`).
+		WithArgs("hurtki", "dark", "hurtki-dark", true, "default", "on", "user").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveBanner(context.TODO(), domain.LTBannerMetadata{
+		Username:   "HurtKi",
+		BannerType: domain.TypeDark,
+		UrlPath:    "hurtki-dark",
+		Active:     true,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,20 @@
+package github_missing_repo
+
+import (
+	"database/sql"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+// PostgresRepo persists negative cache: users, organizations and repositories, that github didn't have
+type PostgresRepo struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewPostgresRepo(db *sql.DB, logger logger.Logger) *PostgresRepo {
+	return &PostgresRepo{
+		db:     db,
+		logger: logger.With("repo", "github-missing-repo"),
+	}
+}
@@ -0,0 +1,76 @@
+package github_missing_repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+)
+
+// SaveMissing stores entity or prolongs it
+func (r *PostgresRepo) SaveMissing(ctx context.Context, missing domain.MissingGithubEntity) error {
+	fn := "internal.repo.github_missing.PostgresRepo.SaveMissing"
+	if missing.Username == "" {
+		return repoerr.ErrEmptyField{Field: "github_username"}
+	}
+
+	const q = `
+	insert into github_missing (kind, username_normalized, expires_at)
+	values ($1, $2, $3)
+	on conflict (kind, username_normalized) do update set
+		expires_at = EXCLUDED.expires_at;
+	`
+	if _, err := r.db.ExecContext(ctx, q, string(missing.Kind), domain.NormalizeGithubUsername(missing.Username), missing.ExpiresAt.UTC()); err != nil {
+		r.logger.Error("unexpected error when saving missing entity", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return nil
+}
+
+// DeleteMissing deletes username of every kind and repositories, that it owns
+func (r *PostgresRepo) DeleteMissing(ctx context.Context, username string) error {
+	fn := "internal.repo.github_missing.PostgresRepo.DeleteMissing"
+	if username == "" {
+		return repoerr.ErrEmptyField{Field: "github_username"}
+	}
+	normalized := domain.NormalizeGithubUsername(username)
+
+	const q = `
+	delete from github_missing
+	where username_normalized = $1 or starts_with(username_normalized, $2);`
+	if _, err := r.db.ExecContext(ctx, q, normalized, normalized+"/"); err != nil {
+		r.logger.Error("unexpected error when deleting missing entity", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return nil
+}
+
+// IsMissing reports, whether entity is stored and isn't expired yet
+func (r *PostgresRepo) IsMissing(ctx context.Context, kind domain.BannerKind, username string) (bool, error) {
+	fn := "internal.repo.github_missing.PostgresRepo.IsMissing"
+	const q = `
+	select exists(
+		select 1 from github_missing
+		where kind = $1 and username_normalized = $2 and expires_at > $3
+	);`
+
+	var missing bool
+	err := r.db.QueryRowContext(ctx, q, string(kind), domain.NormalizeGithubUsername(username), time.Now().UTC()).Scan(&missing)
+	if err != nil {
+		r.logger.Error("unexpected error when checking missing entity", "source", fn, "err", err)
+		return false, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return missing, nil
+}
+
+// DeleteExpired deletes entities, that aren't used anymore
+func (r *PostgresRepo) DeleteExpired(ctx context.Context) error {
+	fn := "internal.repo.github_missing.PostgresRepo.DeleteExpired"
+	const q = `delete from github_missing where expires_at <= $1;`
+	if _, err := r.db.ExecContext(ctx, q, time.Now().UTC()); err != nil {
+		r.logger.Error("unexpected error when deleting expired entities", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package github_missing_repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+type LoggerMock struct{}
+
+func (m LoggerMock) Debug(a string, b ...any)    {}
+func (m LoggerMock) Info(a string, b ...any)     {}
+func (m LoggerMock) Warn(a string, b ...any)     {}
+func (m LoggerMock) Error(a string, b ...any)    {}
+func (m LoggerMock) With(a ...any) logger.Logger { return m }
+
+func getMockAndRepo(t *testing.T) (sqlmock.Sqlmock, *PostgresRepo) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return mock, NewPostgresRepo(db, LoggerMock{})
+}
+
+func TestSaveMissing(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`
+	insert into github_missing (kind, username_normalized, expires_at)
+	values ($1, $2, $3)
+	on conflict (kind, username_normalized) do update set
+		expires_at = EXCLUDED.expires_at;
+	`).WithArgs("user", "hurtki", expiresAt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.SaveMissing(context.TODO(), domain.MissingGithubEntity{Kind: domain.KindUser, Username: "HurtKi", ExpiresAt: expiresAt})
+	require.NoError(t, err)
+
+	err = repo.SaveMissing(context.TODO(), domain.MissingGithubEntity{Kind: domain.KindUser})
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "github_username"})
+}
+
+func TestDeleteMissing(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(`
+	delete from github_missing
+	where username_normalized = $1 or starts_with(username_normalized, $2);`).
+		WithArgs("hurtki", "hurtki/").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, repo.DeleteMissing(context.TODO(), "HURTKI"))
+}
+
+func TestIsMissing(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	const q = `
+	select exists(
+		select 1 from github_missing
+		where kind = $1 and username_normalized = $2 and expires_at > $3
+	);`
+
+	mock.ExpectQuery(q).
+		WithArgs("repository", "hurtki/typo", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	missing, err := repo.IsMissing(context.TODO(), domain.KindRepository, "HurtKi/Typo")
+	require.NoError(t, err)
+	require.True(t, missing)
+
+	mock.ExpectQuery(q).
+		WithArgs("user", "hurtki", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	missing, err = repo.IsMissing(context.TODO(), domain.KindUser, "hurtki")
+	require.NoError(t, err)
+	require.False(t, missing)
+}
+
+func TestDeleteExpired(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(`delete from github_missing where expires_at <= $1;`).
+		WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	require.NoError(t, repo.DeleteExpired(context.TODO()))
+}
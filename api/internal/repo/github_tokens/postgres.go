@@ -0,0 +1,43 @@
+package github_tokens_repo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+var ErrInvalidEncryptionKey = errors.New("invalid tokens encryption key, expected base64 encoded 32 bytes")
+
+// PostgresRepo stores github tokens encrypted with AES-256-GCM
+// id of the token is authenticated together with it, so encrypted token can't be moved to other row
+type PostgresRepo struct {
+	db     *sql.DB
+	aead   cipher.AEAD
+	logger logger.Logger
+}
+
+// NewPostgresRepo accepts base64 encoded 32 bytes key
+func NewPostgresRepo(db *sql.DB, encryptionKey string, logger logger.Logger) (*PostgresRepo, error) {
+	key, err := base64.StdEncoding.DecodeString(encryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidEncryptionKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	return &PostgresRepo{
+		db:     db,
+		aead:   aead,
+		logger: logger.With("repo", "github-tokens-repo"),
+	}, nil
+}
@@ -0,0 +1,125 @@
+package github_tokens_repo
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+)
+
+// SaveToken stores token encrypted, disabled mark of the token is dropped
+func (r *PostgresRepo) SaveToken(ctx context.Context, token domain.GithubToken) error {
+	fn := "internal.repo.github_tokens.PostgresRepo.SaveToken"
+	if token.ID == "" {
+		return repoerr.ErrEmptyField{Field: "id"}
+	}
+	if token.Token == "" {
+		return repoerr.ErrEmptyField{Field: "token"}
+	}
+
+	encrypted, err := r.encrypt(token.ID, token.Token)
+	if err != nil {
+		r.logger.Error("can't encrypt token", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	const q = `
+	insert into github_tokens (id, host, token_encrypted, added_at, disabled_at)
+	values ($1, $2, $3, $4, null)
+	on conflict (id) do update set
+		token_encrypted = EXCLUDED.token_encrypted,
+		added_at = EXCLUDED.added_at,
+		disabled_at = null;
+	`
+	if _, err := r.db.ExecContext(ctx, q, token.ID, token.Host, encrypted, token.AddedAt.UTC()); err != nil {
+		r.logger.Error("unexpected error when saving token", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return nil
+}
+
+// DisableToken marks token as disabled, mark of the token, that isn't stored, is stored without token itself
+func (r *PostgresRepo) DisableToken(ctx context.Context, id string, host string) error {
+	fn := "internal.repo.github_tokens.PostgresRepo.DisableToken"
+	if id == "" {
+		return repoerr.ErrEmptyField{Field: "id"}
+	}
+
+	const q = `
+	insert into github_tokens (id, host, disabled_at)
+	values ($1, $2, CURRENT_TIMESTAMP)
+	on conflict (id) do update set
+		disabled_at = CURRENT_TIMESTAMP;
+	`
+	if _, err := r.db.ExecContext(ctx, q, id, host); err != nil {
+		r.logger.Error("unexpected error when disabling token", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return nil
+}
+
+// GetTokens returns all the stored tokens decrypted and disabled marks
+func (r *PostgresRepo) GetTokens(ctx context.Context) ([]domain.GithubToken, error) {
+	fn := "internal.repo.github_tokens.PostgresRepo.GetTokens"
+	const q = `
+	select id, host, token_encrypted, added_at, disabled_at from github_tokens
+	order by added_at;`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		r.logger.Error("unexpected error when selecting tokens", "source", fn, "err", err)
+		return nil, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	defer rows.Close()
+
+	tokens := []domain.GithubToken{}
+	for rows.Next() {
+		var (
+			token      domain.GithubToken
+			encrypted  []byte
+			disabledAt sql.NullTime
+		)
+		if err := rows.Scan(&token.ID, &token.Host, &encrypted, &token.AddedAt, &disabledAt); err != nil {
+			r.logger.Error("unexpected error when scanning token", "source", fn, "err", err)
+			return nil, repoerr.ErrRepoInternal{Note: err.Error()}
+		}
+		token.Disabled = disabledAt.Valid
+		if encrypted != nil {
+			token.Token, err = r.decrypt(token.ID, encrypted)
+			if err != nil {
+				// token, encrypted with other key, can't be used, but the rest of tokens can
+				r.logger.Error("can't decrypt token, skipping it", "source", fn, "id", token.ID, "err", err)
+				continue
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("unexpected error after iterating tokens", "source", fn, "err", err)
+		return nil, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+	return tokens, nil
+}
+
+// encrypt returns nonce followed by sealed token
+func (r *PostgresRepo) encrypt(id string, token string) ([]byte, error) {
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return r.aead.Seal(nonce, nonce, []byte(token), []byte(id)), nil
+}
+
+func (r *PostgresRepo) decrypt(id string, encrypted []byte) (string, error) {
+	nonceSize := r.aead.NonceSize()
+	if len(encrypted) < nonceSize {
+		return "", ErrInvalidEncryptionKey
+	}
+	token, err := r.aead.Open(nil, encrypted[:nonceSize], encrypted[nonceSize:], []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
@@ -0,0 +1,102 @@
+package github_tokens_repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type LoggerMock struct{}
+
+func (m LoggerMock) Debug(a string, b ...any)    {}
+func (m LoggerMock) Info(a string, b ...any)     {}
+func (m LoggerMock) Warn(a string, b ...any)     {}
+func (m LoggerMock) Error(a string, b ...any)    {}
+func (m LoggerMock) With(a ...any) logger.Logger { return m }
+
+var testKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+
+func getMockAndRepo(t *testing.T) (sqlmock.Sqlmock, *PostgresRepo) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	repo, err := NewPostgresRepo(db, testKey, LoggerMock{})
+	require.NoError(t, err)
+	return mock, repo
+}
+
+// capturedBytes matches any []byte argument and remembers it
+type capturedBytes struct{ value []byte }
+
+func (c *capturedBytes) Match(v driver.Value) bool {
+	b, ok := v.([]byte)
+	c.value = b
+	return ok
+}
+
+func TestNewPostgresRepoInvalidKey(t *testing.T) {
+	_, err := NewPostgresRepo(nil, base64.StdEncoding.EncodeToString([]byte("short")), LoggerMock{})
+	require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+	_, err = NewPostgresRepo(nil, "not base64!", LoggerMock{})
+	require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+}
+
+func TestSaveAndGetTokens(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	addedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	encrypted := &capturedBytes{}
+
+	mock.ExpectExec(`
+	insert into github_tokens (id, host, token_encrypted, added_at, disabled_at)
+	values ($1, $2, $3, $4, null)
+	on conflict (id) do update set
+		token_encrypted = EXCLUDED.token_encrypted,
+		added_at = EXCLUDED.added_at,
+		disabled_at = null;
+	`).WithArgs("abc", "", encrypted, addedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.SaveToken(context.Background(), domain.GithubToken{ID: "abc", Token: "ghp_secret_token", AddedAt: addedAt})
+	require.NoError(t, err)
+	// token isn't stored in plain text
+	require.NotContains(t, string(encrypted.value), "ghp_secret_token")
+
+	mock.ExpectQuery(`
+	select id, host, token_encrypted, added_at, disabled_at from github_tokens
+	order by added_at;`).WillReturnRows(sqlmock.NewRows([]string{"id", "host", "token_encrypted", "added_at", "disabled_at"}).
+		AddRow("abc", "", encrypted.value, addedAt, nil).
+		// token, that was encrypted for other row, isn't returned
+		AddRow("moved", "", encrypted.value, addedAt, nil).
+		AddRow("env", "", nil, addedAt, addedAt))
+
+	tokens, err := repo.GetTokens(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []domain.GithubToken{
+		{ID: "abc", Token: "ghp_secret_token", AddedAt: addedAt},
+		{ID: "env", Disabled: true, AddedAt: addedAt},
+	}, tokens)
+}
+
+func TestDisableToken(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(`
+	insert into github_tokens (id, host, disabled_at)
+	values ($1, $2, CURRENT_TIMESTAMP)
+	on conflict (id) do update set
+		disabled_at = CURRENT_TIMESTAMP;
+	`).WithArgs("abc", "github.example.com").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.DisableToken(context.Background(), "abc", "github.example.com"))
+}
@@ -0,0 +1,47 @@
+package github_data_repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// upsertContributions stores contributions of user, nil contributions leave stored ones untouched
+func (r *GithubDataPsgrRepo) upsertContributions(ctx context.Context, tx *sql.Tx, username string, c *domain.GithubContributions) error {
+	if c == nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+	insert into github_data.user_contributions (username_normalized, total, commits, pull_requests, issues, reviews, current_streak, longest_streak)
+	values ($1, $2, $3, $4, $5, $6, $7, $8)
+	on conflict (username_normalized) do update set
+		total = EXCLUDED.total,
+		commits = EXCLUDED.commits,
+		pull_requests = EXCLUDED.pull_requests,
+		issues = EXCLUDED.issues,
+		reviews = EXCLUDED.reviews,
+		current_streak = EXCLUDED.current_streak,
+		longest_streak = EXCLUDED.longest_streak;
+	`, domain.NormalizeGithubUsername(username), c.Total, c.Commits, c.PullRequests, c.Issues, c.Reviews, c.CurrentStreak, c.LongestStreak)
+	return err
+}
+
+// selectContributions returns nil contributions, if they weren't stored for user
+func (r *GithubDataPsgrRepo) selectContributions(ctx context.Context, tx *sql.Tx, username string) (*domain.GithubContributions, error) {
+	row := tx.QueryRowContext(ctx, `
+	select total, commits, pull_requests, issues, reviews, current_streak, longest_streak from github_data.user_contributions
+	where username_normalized = $1;
+	`, domain.NormalizeGithubUsername(username))
+
+	var c domain.GithubContributions
+	err := row.Scan(&c.Total, &c.Commits, &c.PullRequests, &c.Issues, &c.Reviews, &c.CurrentStreak, &c.LongestStreak)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,52 @@
+package github_data_repo
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// etagsRow is a shape of github_data.users.etags jsonb column
+type etagsRow struct {
+	User      string             `json:"user,omitempty"`
+	RepoPages []reposPageETagRow `json:"repo_pages,omitempty"`
+}
+
+type reposPageETagRow struct {
+	ETag    string  `json:"etag"`
+	RepoIDs []int64 `json:"repo_ids"`
+	Size    int     `json:"size,omitempty"`
+}
+
+// etagsToDB stores nil ETags as NULL
+func etagsToDB(etags *domain.GithubDataETags) (sql.NullString, error) {
+	if etags == nil {
+		return sql.NullString{}, nil
+	}
+	row := etagsRow{User: etags.User, RepoPages: make([]reposPageETagRow, len(etags.RepoPages))}
+	for i, page := range etags.RepoPages {
+		row.RepoPages[i] = reposPageETagRow{ETag: page.ETag, RepoIDs: page.RepoIDs, Size: page.Size}
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// etagsFromDB returns nil ETags for NULL or broken value, so data will be fetched without them
+func etagsFromDB(v []byte) *domain.GithubDataETags {
+	if len(v) == 0 {
+		return nil
+	}
+	var row etagsRow
+	if err := json.Unmarshal(v, &row); err != nil {
+		return nil
+	}
+	etags := &domain.GithubDataETags{User: row.User, RepoPages: make([]domain.GithubReposPageETag, len(row.RepoPages))}
+	for i, page := range row.RepoPages {
+		etags.RepoPages[i] = domain.GithubReposPageETag{ETag: page.ETag, RepoIDs: page.RepoIDs, Size: page.Size}
+	}
+	return etags
+}
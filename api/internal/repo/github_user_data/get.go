@@ -33,20 +33,27 @@ func (r *GithubDataPsgrRepo) GetUserData(ctx context.Context, username string) (
 	}()
 
 	row := tx.QueryRowContext(ctx, `
-	select username, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at from github_data.users
+	select username, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags from github_data.users
 	where username_normalized = $1;
 	`, domain.NormalizeGithubUsername(username))
 
 	data := domain.GithubUserData{}
+	var etags []byte
 
-	err = row.Scan(&data.Username, &data.Name, &data.Company, &data.Location, &data.Bio, &data.PublicRepos, &data.Followers, &data.Following, &data.FetchedAt)
+	err = row.Scan(&data.Username, &data.Name, &data.Company, &data.Location, &data.Bio, &data.PublicRepos, &data.Followers, &data.Following, &data.FetchedAt, &etags)
 
 	if err != nil {
 		return domain.GithubUserData{}, r.handleError(err, fn+".scanIntoGithubUserData")
 	}
+	data.ETags = etagsFromDB(etags)
+
+	data.Contributions, err = r.selectContributions(ctx, tx, username)
+	if err != nil {
+		return domain.GithubUserData{}, r.handleError(err, fn+".selectContributions")
+	}
 
 	rows, err := tx.QueryContext(ctx, `
-	select github_id, pushed_at, updated_at, language, stars_count, is_fork, forks_count from github_data.repositories
+	select github_id, name, pushed_at, updated_at, language, stars_count, is_fork, forks_count from github_data.repositories
 	where owner_username_normalized = $1;
 	`, domain.NormalizeGithubUsername(username))
 
@@ -58,7 +65,7 @@ func (r *GithubDataPsgrRepo) GetUserData(ctx context.Context, username string) (
 
 	for rows.Next() {
 		githubRepo := domain.GithubRepository{}
-		err = rows.Scan(&githubRepo.ID, &githubRepo.PushedAt, &githubRepo.UpdatedAt, &githubRepo.Language, &githubRepo.StarsCount, &githubRepo.Fork, &githubRepo.ForksCount)
+		err = rows.Scan(&githubRepo.ID, &githubRepo.Name, &githubRepo.PushedAt, &githubRepo.UpdatedAt, &githubRepo.Language, &githubRepo.StarsCount, &githubRepo.Fork, &githubRepo.ForksCount)
 		if err != nil {
 			return domain.GithubUserData{}, r.handleError(err, fn+".scanRepositoryRow")
 		}
@@ -74,6 +81,10 @@ func (r *GithubDataPsgrRepo) GetUserData(ctx context.Context, username string) (
 		return domain.GithubUserData{}, r.handleError(err, fn+".afterIteratingRowsError")
 	}
 
+	if err = r.selectRepoLanguages(ctx, tx, username, githubRepos); err != nil {
+		return domain.GithubUserData{}, err
+	}
+
 	data.Repositories = githubRepos
 
 	if err = tx.Commit(); err != nil {
@@ -82,3 +93,47 @@ func (r *GithubDataPsgrRepo) GetUserData(ctx context.Context, username string) (
 	committed = true
 	return data, nil
 }
+
+// selectRepoLanguages fills Languages of repositories, that have stored languages
+func (r *GithubDataPsgrRepo) selectRepoLanguages(ctx context.Context, tx *sql.Tx, username string, repos []domain.GithubRepository) error {
+	fn := "internal.repo.github_user_data.GithubDataPsgrRepo.selectRepoLanguages"
+
+	rows, err := tx.QueryContext(ctx, `
+	select l.repo_github_id, l.language, l.bytes from github_data.repository_languages l
+	join github_data.repositories r on r.github_id = l.repo_github_id
+	where r.owner_username_normalized = $1;
+	`, domain.NormalizeGithubUsername(username))
+	if err != nil {
+		return r.handleError(err, fn+".selectLanguagesQuery")
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*domain.GithubRepository, len(repos))
+	for i := range repos {
+		byID[repos[i].ID] = &repos[i]
+	}
+
+	for rows.Next() {
+		var (
+			repoID int64
+			lang   string
+			bytes  int
+		)
+		if err := rows.Scan(&repoID, &lang, &bytes); err != nil {
+			return r.handleError(err, fn+".scanLanguageRow")
+		}
+		repo, ok := byID[repoID]
+		if !ok {
+			continue
+		}
+		if repo.Languages == nil {
+			repo.Languages = make(map[string]int)
+		}
+		repo.Languages[lang] = bytes
+	}
+
+	if err := rows.Err(); err != nil {
+		return r.handleError(err, fn+".afterIteratingRowsError")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package github_data_repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// languagesBatchSize is a count of language rows in one insert ( 3 positional parameters per row )
+const languagesBatchSize = 1000
+
+// replaceRepoLanguages replaces stored languages of repositories, that have them fetched
+// repositories with nil Languages are skipped, their stored languages stay untouched
+func (r *GithubDataPsgrRepo) replaceRepoLanguages(ctx context.Context, tx *sql.Tx, repos []domain.GithubRepository) error {
+	var (
+		deletePosParams []string
+		deleteArgs      []any
+		rows            [][3]any
+	)
+	for _, repo := range repos {
+		if repo.Languages == nil {
+			continue
+		}
+		deleteArgs = append(deleteArgs, repo.ID)
+		deletePosParams = append(deletePosParams, fmt.Sprintf("$%d", len(deleteArgs)))
+		// sorted, so the same languages produce the same query
+		langs := make([]string, 0, len(repo.Languages))
+		for lang := range repo.Languages {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		for _, lang := range langs {
+			rows = append(rows, [3]any{repo.ID, lang, repo.Languages[lang]})
+		}
+	}
+
+	if len(deleteArgs) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+	delete from github_data.repository_languages
+	where repo_github_id in (%s);
+	`, strings.Join(deletePosParams, ", ")), deleteArgs...)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rows); i += languagesBatchSize {
+		end := min(i+languagesBatchSize, len(rows))
+
+		posParams := make([]string, 0, end-i)
+		args := make([]any, 0, (end-i)*3)
+		for j, row := range rows[i:end] {
+			posParams = append(posParams, fmt.Sprintf("($%d, $%d, $%d)", j*3+1, j*3+2, j*3+3))
+			args = append(args, row[0], row[1], row[2])
+		}
+
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+	insert into github_data.repository_languages (repo_github_id, language, bytes)
+	values %s;
+	`, strings.Join(posParams, ", ")), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package github_data_repo
+
+import (
+	"context"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+)
+
+// SaveOrgData upserts organization's data together with its repositories
+func (r *GithubDataPsgrRepo) SaveOrgData(ctx context.Context, orgData domain.GithubOrgData) error {
+	fn := "internal.repo.github_user_data.GithubDataPsgrRepo.SaveOrgData"
+
+	repos, err := orgReposToDB(orgData.Repositories)
+	if err != nil {
+		r.logger.Error("can't marshal repositories of organization", "source", fn, "err", err)
+		return repo.ErrRepoInternal{Note: err.Error()}
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+	insert into github_data.orgs (login, login_normalized, name, description, location, public_repos_count, members_count, repositories, fetched_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	on conflict (login_normalized) do update set
+		login = EXCLUDED.login,
+		name = EXCLUDED.name,
+		description = EXCLUDED.description,
+		location = EXCLUDED.location,
+		public_repos_count = EXCLUDED.public_repos_count,
+		members_count = EXCLUDED.members_count,
+		repositories = EXCLUDED.repositories,
+		fetched_at = EXCLUDED.fetched_at;
+	`, orgData.Login, domain.NormalizeGithubUsername(orgData.Login), orgData.Name, orgData.Description, orgData.Location, orgData.PublicRepos, orgData.Members, repos, orgData.FetchedAt)
+	if err != nil {
+		return r.handleError(err, fn+".upsertOrg")
+	}
+	return nil
+}
+
+// GetOrgData returns repo.ErrNothingFound, if organization's data wasn't stored
+func (r *GithubDataPsgrRepo) GetOrgData(ctx context.Context, login string) (domain.GithubOrgData, error) {
+	fn := "internal.repo.github_user_data.GithubDataPsgrRepo.GetOrgData"
+
+	row := r.db.QueryRowContext(ctx, `
+	select login, name, description, location, public_repos_count, members_count, repositories, fetched_at from github_data.orgs
+	where login_normalized = $1;
+	`, domain.NormalizeGithubUsername(login))
+
+	data := domain.GithubOrgData{}
+	var repos []byte
+	err := row.Scan(&data.Login, &data.Name, &data.Description, &data.Location, &data.PublicRepos, &data.Members, &repos, &data.FetchedAt)
+	if err != nil {
+		return domain.GithubOrgData{}, r.handleError(err, fn+".scanIntoGithubOrgData")
+	}
+
+	data.Repositories, err = orgReposFromDB(repos)
+	if err != nil {
+		r.logger.Error("can't unmarshal repositories of organization", "source", fn, "err", err)
+		return domain.GithubOrgData{}, repo.ErrRepoInternal{Note: err.Error()}
+	}
+	return data, nil
+}
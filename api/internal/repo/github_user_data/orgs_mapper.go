@@ -0,0 +1,65 @@
+package github_data_repo
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+)
+
+// orgRepoRow is an element of github_data.orgs.repositories jsonb column
+// repositories of organization are always read and written together, so they aren't stored in separate table
+type orgRepoRow struct {
+	ID         int64          `json:"id"`
+	Owner      string         `json:"owner"`
+	Name       string         `json:"name"`
+	PushedAt   *time.Time     `json:"pushed_at,omitempty"`
+	UpdatedAt  *time.Time     `json:"updated_at,omitempty"`
+	Language   *string        `json:"language,omitempty"`
+	StarsCount int            `json:"stars_count"`
+	Fork       bool           `json:"is_fork"`
+	ForksCount int            `json:"forks_count"`
+	Languages  map[string]int `json:"languages,omitempty"`
+}
+
+func orgReposToDB(repos []domain.GithubRepository) ([]byte, error) {
+	rows := make([]orgRepoRow, len(repos))
+	for i, repo := range repos {
+		rows[i] = orgRepoRow{
+			ID:         repo.ID,
+			Owner:      repo.OwnerUsername,
+			Name:       repo.Name,
+			PushedAt:   repo.PushedAt,
+			UpdatedAt:  repo.UpdatedAt,
+			Language:   repo.Language,
+			StarsCount: repo.StarsCount,
+			Fork:       repo.Fork,
+			ForksCount: repo.ForksCount,
+			Languages:  repo.Languages,
+		}
+	}
+	return json.Marshal(rows)
+}
+
+func orgReposFromDB(v []byte) ([]domain.GithubRepository, error) {
+	var rows []orgRepoRow
+	if err := json.Unmarshal(v, &rows); err != nil {
+		return nil, err
+	}
+	repos := make([]domain.GithubRepository, len(rows))
+	for i, row := range rows {
+		repos[i] = domain.GithubRepository{
+			ID:            row.ID,
+			OwnerUsername: row.Owner,
+			Name:          row.Name,
+			PushedAt:      row.PushedAt,
+			UpdatedAt:     row.UpdatedAt,
+			Language:      row.Language,
+			StarsCount:    row.StarsCount,
+			Fork:          row.Fork,
+			ForksCount:    row.ForksCount,
+			Languages:     row.Languages,
+		}
+	}
+	return repos, nil
+}
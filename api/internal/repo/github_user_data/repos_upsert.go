@@ -25,7 +25,7 @@ func (r *GithubDataPsgrRepo) upsertRepoBatch(ctx context.Context, tx *sql.Tx, ba
 	i := 1
 	for _, repo := range batch {
 		tempPosArgs := []string{}
-		for j := i; j < i+8; j++ {
+		for j := i; j < i+9; j++ {
 			tempPosArgs = append(tempPosArgs, fmt.Sprintf("$%d", j))
 		}
 		posParams = append(posParams, fmt.Sprintf("(%s)", strings.Join(tempPosArgs, ", ")))
@@ -38,12 +38,13 @@ func (r *GithubDataPsgrRepo) upsertRepoBatch(ctx context.Context, tx *sql.Tx, ba
 			repo.StarsCount,
 			repo.Fork,
 			repo.ForksCount,
+			repo.Name,
 		)
-		i += 8
+		i += 9
 	}
 
 	query := fmt.Sprintf(`
-	insert into github_data.repositories (github_id, owner_username_normalized, pushed_at, updated_at, language, stars_count, is_fork, forks_count)
+	insert into github_data.repositories (github_id, owner_username_normalized, pushed_at, updated_at, language, stars_count, is_fork, forks_count, name)
 	values %s
 	on conflict (github_id) do update set
 		owner_username_normalized = excluded.owner_username_normalized,
@@ -52,7 +53,8 @@ func (r *GithubDataPsgrRepo) upsertRepoBatch(ctx context.Context, tx *sql.Tx, ba
 		language       = excluded.language,
 		stars_count    = excluded.stars_count,
 		is_fork        = excluded.is_fork,
-		forks_count    = excluded.forks_count;
+		forks_count    = excluded.forks_count,
+		name           = excluded.name;
 	`, strings.Join(posParams, ", "))
 
 	_, err := tx.ExecContext(ctx, query, args...)
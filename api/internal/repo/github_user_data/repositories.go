@@ -0,0 +1,117 @@
+package github_data_repo
+
+import (
+	"context"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/repo"
+)
+
+// SaveRepoData upserts single repository with its project's details and replaces its languages
+// repository shares the row with owner's repositories list, so it's updated by both
+func (r *GithubDataPsgrRepo) SaveRepoData(ctx context.Context, repoData domain.GithubRepoData) error {
+	fn := "internal.repo.github_user_data.GithubDataPsgrRepo.SaveRepoData"
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("can't start transaction", "source", fn, "err", err)
+		return repo.ErrRepoInternal{
+			Note: err.Error(),
+		}
+	}
+	committed := false
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if !committed {
+			rbErr := tx.Rollback()
+			if rbErr != nil {
+				r.logger.Error("error occurred, when rolling back transaction", "err", rbErr, "source", fn)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+	insert into github_data.repositories (github_id, owner_username_normalized, name, description, pushed_at, updated_at, language, stars_count, is_fork, forks_count, open_issues_count, license, latest_release, fetched_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	on conflict (github_id) do update set
+		owner_username_normalized = EXCLUDED.owner_username_normalized,
+		name = EXCLUDED.name,
+		description = EXCLUDED.description,
+		pushed_at = EXCLUDED.pushed_at,
+		updated_at = EXCLUDED.updated_at,
+		language = EXCLUDED.language,
+		stars_count = EXCLUDED.stars_count,
+		is_fork = EXCLUDED.is_fork,
+		forks_count = EXCLUDED.forks_count,
+		open_issues_count = EXCLUDED.open_issues_count,
+		license = EXCLUDED.license,
+		latest_release = EXCLUDED.latest_release,
+		fetched_at = EXCLUDED.fetched_at;
+	`, repoData.ID, domain.NormalizeGithubUsername(repoData.OwnerUsername), repoData.Name, repoData.Description, repoData.PushedAt, repoData.UpdatedAt,
+		repoData.Language, repoData.StarsCount, repoData.Fork, repoData.ForksCount, repoData.OpenIssues, repoData.License, repoData.LatestRelease, repoData.FetchedAt)
+	if err != nil {
+		return r.handleError(err, fn+".upsertRepository")
+	}
+
+	if err := r.replaceRepoLanguages(ctx, tx, []domain.GithubRepository{repoData.GithubRepository}); err != nil {
+		return r.handleError(err, fn+".replaceRepoLanguages")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return r.handleError(err, fn+".commit")
+	}
+	committed = true
+	return nil
+}
+
+// GetRepoData returns repo.ErrNothingFound, if repository wasn't stored by SaveRepoData
+// repository, that is stored only as a part of owner's list, has no project's details and isn't returned
+func (r *GithubDataPsgrRepo) GetRepoData(ctx context.Context, fullName string) (domain.GithubRepoData, error) {
+	fn := "internal.repo.github_user_data.GithubDataPsgrRepo.GetRepoData"
+	owner, name, ok := domain.SplitRepoFullName(fullName)
+	if !ok {
+		return domain.GithubRepoData{}, repo.ErrNothingFound
+	}
+
+	// renamed repository can leave its old row with the same name, so the freshest one is taken
+	row := r.db.QueryRowContext(ctx, `
+	select github_id, owner_username_normalized, name, description, pushed_at, updated_at, language, stars_count, is_fork, forks_count, open_issues_count, license, latest_release, fetched_at from github_data.repositories
+	where owner_username_normalized = $1 and lower(name) = lower($2) and fetched_at is not null
+	order by fetched_at desc
+	limit 1;
+	`, domain.NormalizeGithubUsername(owner), name)
+
+	data := domain.GithubRepoData{}
+	err := row.Scan(&data.ID, &data.OwnerUsername, &data.Name, &data.Description, &data.PushedAt, &data.UpdatedAt, &data.Language,
+		&data.StarsCount, &data.Fork, &data.ForksCount, &data.OpenIssues, &data.License, &data.LatestRelease, &data.FetchedAt)
+	if err != nil {
+		return domain.GithubRepoData{}, r.handleError(err, fn+".scanIntoGithubRepoData")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+	select language, bytes from github_data.repository_languages
+	where repo_github_id = $1;
+	`, data.ID)
+	if err != nil {
+		return domain.GithubRepoData{}, r.handleError(err, fn+".selectLanguagesQuery")
+	}
+	defer rows.Close()
+
+	data.Languages = map[string]int{}
+	for rows.Next() {
+		var (
+			lang  string
+			bytes int
+		)
+		if err := rows.Scan(&lang, &bytes); err != nil {
+			return domain.GithubRepoData{}, r.handleError(err, fn+".scanLanguageRow")
+		}
+		data.Languages[lang] = bytes
+	}
+	if err := rows.Err(); err != nil {
+		return domain.GithubRepoData{}, r.handleError(err, fn+".afterIteratingRowsError")
+	}
+	return data, nil
+}
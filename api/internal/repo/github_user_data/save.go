@@ -34,9 +34,15 @@ func (r *GithubDataPsgrRepo) SaveUserData(ctx context.Context, userData domain.G
 		}
 	}()
 
+	etags, err := etagsToDB(userData.ETags)
+	if err != nil {
+		r.logger.Error("can't marshal etags", "source", fn, "err", err)
+		return repo.ErrRepoInternal{Note: err.Error()}
+	}
+
 	_, err = tx.ExecContext(ctx, `
-	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at)
-	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	on conflict (username_normalized) do update set
 		username = EXCLUDED.username,
 		name = EXCLUDED.name,
@@ -46,12 +52,17 @@ func (r *GithubDataPsgrRepo) SaveUserData(ctx context.Context, userData domain.G
 		public_repos_count = EXCLUDED.public_repos_count,
 		followers_count = EXCLUDED.followers_count,
 		following_count = EXCLUDED.following_count,
-		fetched_at = EXCLUDED.fetched_at;
-	`, userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt)
+		fetched_at = EXCLUDED.fetched_at,
+		etags = EXCLUDED.etags;
+	`, userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt, etags)
 	if err != nil {
 		return r.handleError(err, fn+".insertUser")
 	}
 
+	if err := r.upsertContributions(ctx, tx, userData.Username, userData.Contributions); err != nil {
+		return r.handleError(err, fn+".upsertContributions")
+	}
+
 	// if a new data says that there is no repositories, then delete all existing ones
 	if len(userData.Repositories) == 0 {
 		_, err := tx.ExecContext(ctx, `
@@ -97,6 +108,10 @@ func (r *GithubDataPsgrRepo) SaveUserData(ctx context.Context, userData domain.G
 		}
 	}
 
+	if err := r.replaceRepoLanguages(ctx, tx, userData.Repositories); err != nil {
+		return r.handleError(err, fn+".replaceRepoLanguages")
+	}
+
 	deleteArgs := make([]any, len(userData.Repositories)+1)
 	deleteArgs[0] = domain.NormalizeGithubUsername(userData.Username)
 	reposCount := len(userData.Repositories)
@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hurtki/github-banners/api/internal/domain"
 	"github.com/hurtki/github-banners/api/internal/logger"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,8 +38,8 @@ func getMockAndRepo(t *testing.T) (sqlmock.Sqlmock, *GithubDataPsgrRepo) {
 
 func TestSaveUserDataSucess(t *testing.T) {
 	mock, repo := getMockAndRepo(t)
-	githubRepo1 := domain.GithubRepository{ID: 123, OwnerUsername: "alex"}
-	githubRepo2 := domain.GithubRepository{ID: 45, OwnerUsername: "alex"}
+	githubRepo1 := domain.GithubRepository{ID: 123, OwnerUsername: "alex", Name: "banners"}
+	githubRepo2 := domain.GithubRepository{ID: 45, OwnerUsername: "alex", Name: "dotfiles"}
 	userData := domain.GithubUserData{
 		Username:     "alex",
 		FetchedAt:    time.Now(),
@@ -48,8 +49,8 @@ func TestSaveUserDataSucess(t *testing.T) {
 	mock.ExpectBegin()
 
 	mock.ExpectExec(`
-	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at)
-	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	on conflict (username_normalized) do update set
 		username = EXCLUDED.username,
 		name = EXCLUDED.name,
@@ -59,12 +60,13 @@ func TestSaveUserDataSucess(t *testing.T) {
 		public_repos_count = EXCLUDED.public_repos_count,
 		followers_count = EXCLUDED.followers_count,
 		following_count = EXCLUDED.following_count,
-		fetched_at = EXCLUDED.fetched_at;
-	`).WithArgs(userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+		fetched_at = EXCLUDED.fetched_at,
+		etags = EXCLUDED.etags;
+	`).WithArgs(userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt, nil).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(`
-	insert into github_data.repositories (github_id, owner_username_normalized, pushed_at, updated_at, language, stars_count, is_fork, forks_count)
-	values ($1, $2, $3, $4, $5, $6, $7, $8), ($9, $10, $11, $12, $13, $14, $15, $16)
+	insert into github_data.repositories (github_id, owner_username_normalized, pushed_at, updated_at, language, stars_count, is_fork, forks_count, name)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9), ($10, $11, $12, $13, $14, $15, $16, $17, $18)
 	on conflict (github_id) do update set
 		owner_username_normalized = excluded.owner_username_normalized,
 		pushed_at      = excluded.pushed_at,
@@ -72,8 +74,9 @@ func TestSaveUserDataSucess(t *testing.T) {
 		language       = excluded.language,
 		stars_count    = excluded.stars_count,
 		is_fork        = excluded.is_fork,
-		forks_count    = excluded.forks_count;
-	`).WithArgs(githubRepo1.ID, domain.NormalizeGithubUsername(githubRepo1.OwnerUsername), githubRepo1.PushedAt, githubRepo1.UpdatedAt, githubRepo1.Language, githubRepo1.StarsCount, githubRepo1.Fork, githubRepo1.ForksCount, githubRepo2.ID, domain.NormalizeGithubUsername(githubRepo2.OwnerUsername), githubRepo2.PushedAt, githubRepo2.UpdatedAt, githubRepo2.Language, githubRepo2.StarsCount, githubRepo2.Fork, githubRepo2.ForksCount).WillReturnResult(sqlmock.NewResult(1, 1))
+		forks_count    = excluded.forks_count,
+		name           = excluded.name;
+	`).WithArgs(githubRepo1.ID, domain.NormalizeGithubUsername(githubRepo1.OwnerUsername), githubRepo1.PushedAt, githubRepo1.UpdatedAt, githubRepo1.Language, githubRepo1.StarsCount, githubRepo1.Fork, githubRepo1.ForksCount, githubRepo1.Name, githubRepo2.ID, domain.NormalizeGithubUsername(githubRepo2.OwnerUsername), githubRepo2.PushedAt, githubRepo2.UpdatedAt, githubRepo2.Language, githubRepo2.StarsCount, githubRepo2.Fork, githubRepo2.ForksCount, githubRepo2.Name).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(`
 		delete from github_data.repositories r
@@ -91,19 +94,85 @@ func TestSaveUserDataSucess(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestSaveUserDataSucessNoRepos(t *testing.T) {
+func TestSaveUserDataReplacesLanguages(t *testing.T) {
 	mock, repo := getMockAndRepo(t)
+	githubRepo := domain.GithubRepository{ID: 123, OwnerUsername: "alex", Name: "banners", Languages: map[string]int{"Go": 1000, "Makefile": 20}}
 	userData := domain.GithubUserData{
 		Username:     "alex",
 		FetchedAt:    time.Now(),
-		Repositories: []domain.GithubRepository{},
+		Repositories: []domain.GithubRepository{githubRepo},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`
+	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	on conflict (username_normalized) do update set
+		username = EXCLUDED.username,
+		name = EXCLUDED.name,
+		company = EXCLUDED.company,
+		location = EXCLUDED.location,
+		bio = EXCLUDED.bio,
+		public_repos_count = EXCLUDED.public_repos_count,
+		followers_count = EXCLUDED.followers_count,
+		following_count = EXCLUDED.following_count,
+		fetched_at = EXCLUDED.fetched_at,
+		etags = EXCLUDED.etags;
+	`).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(`
+	insert into github_data.repositories (github_id, owner_username_normalized, pushed_at, updated_at, language, stars_count, is_fork, forks_count, name)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	on conflict (github_id) do update set
+		owner_username_normalized = excluded.owner_username_normalized,
+		pushed_at      = excluded.pushed_at,
+		updated_at     = excluded.updated_at,
+		language       = excluded.language,
+		stars_count    = excluded.stars_count,
+		is_fork        = excluded.is_fork,
+		forks_count    = excluded.forks_count,
+		name           = excluded.name;
+	`).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(`
+	delete from github_data.repository_languages
+	where repo_github_id in ($1);
+	`).WithArgs(githubRepo.ID).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	mock.ExpectExec(`
+	insert into github_data.repository_languages (repo_github_id, language, bytes)
+	values ($1, $2, $3), ($4, $5, $6);
+	`).WithArgs(githubRepo.ID, "Go", 1000, githubRepo.ID, "Makefile", 20).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(`
+		delete from github_data.repositories r
+		where r.owner_username_normalized = $1
+		and not exists (
+			select 1
+			from (values ($2::bigint)) as v(github_id)
+			where v.github_id = r.github_id
+		);
+	`).WithArgs("alex", githubRepo.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveUserData(context.TODO(), userData))
+}
+
+func TestSaveUserDataSucessNoRepos(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	userData := domain.GithubUserData{
+		Username:      "alex",
+		FetchedAt:     time.Now(),
+		Repositories:  []domain.GithubRepository{},
+		Contributions: &domain.GithubContributions{Total: 10, Commits: 7, PullRequests: 2, Issues: 1, CurrentStreak: 1, LongestStreak: 3},
 	}
 
 	mock.ExpectBegin()
 
 	mock.ExpectExec(`
-	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at)
-	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	insert into github_data.users (username, username_normalized, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	on conflict (username_normalized) do update set
 		username = EXCLUDED.username,
 		name = EXCLUDED.name,
@@ -113,8 +182,22 @@ func TestSaveUserDataSucessNoRepos(t *testing.T) {
 		public_repos_count = EXCLUDED.public_repos_count,
 		followers_count = EXCLUDED.followers_count,
 		following_count = EXCLUDED.following_count,
-		fetched_at = EXCLUDED.fetched_at;
-	`).WithArgs(userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+		fetched_at = EXCLUDED.fetched_at,
+		etags = EXCLUDED.etags;
+	`).WithArgs(userData.Username, domain.NormalizeGithubUsername(userData.Username), userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(`
+	insert into github_data.user_contributions (username_normalized, total, commits, pull_requests, issues, reviews, current_streak, longest_streak)
+	values ($1, $2, $3, $4, $5, $6, $7, $8)
+	on conflict (username_normalized) do update set
+		total = EXCLUDED.total,
+		commits = EXCLUDED.commits,
+		pull_requests = EXCLUDED.pull_requests,
+		issues = EXCLUDED.issues,
+		reviews = EXCLUDED.reviews,
+		current_streak = EXCLUDED.current_streak,
+		longest_streak = EXCLUDED.longest_streak;
+	`).WithArgs("alex", 10, 7, 2, 1, 0, 1, 3).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(`
 		delete from github_data.repositories
@@ -162,36 +245,239 @@ func TestGetUserDataSuccess(t *testing.T) {
 	mock, repo := getMockAndRepo(t)
 
 	userData := domain.GithubUserData{Username: "OliVia"}
-	repo1 := domain.GithubRepository{ID: 123, OwnerUsername: userData.Username}
-	repo2 := domain.GithubRepository{ID: 3454, OwnerUsername: userData.Username}
+	repo1 := domain.GithubRepository{ID: 123, OwnerUsername: userData.Username, Name: "banners", Languages: map[string]int{"Go": 1000, "Makefile": 20}}
+	repo2 := domain.GithubRepository{ID: 3454, OwnerUsername: userData.Username, Name: "notes"}
 	userData.Repositories = []domain.GithubRepository{repo1, repo2}
+	userData.Contributions = &domain.GithubContributions{Total: 420, Commits: 300, PullRequests: 40, Issues: 12, Reviews: 30, CurrentStreak: 5, LongestStreak: 21}
 
-	userColumns := []string{"username", "name", "company", "location", "bio", "public_repos_count", "followers_count", "following_count", "fetched_at"}
+	userColumns := []string{"username", "name", "company", "location", "bio", "public_repos_count", "followers_count", "following_count", "fetched_at", "etags"}
 
-	githubRepoColumns := []string{"github_id", "pushed_at", "updated_at", "language", "stars_count", "is_fork", "forks_count"}
+	githubRepoColumns := []string{"github_id", "name", "pushed_at", "updated_at", "language", "stars_count", "is_fork", "forks_count"}
 
 	githubReposRows := sqlmock.NewRows(githubRepoColumns)
 
 	for _, githubRepo := range userData.Repositories {
-		githubReposRows.AddRow(githubRepo.ID, githubRepo.PushedAt, githubRepo.UpdatedAt, githubRepo.Language, githubRepo.StarsCount, githubRepo.Fork, githubRepo.ForksCount)
+		githubReposRows.AddRow(githubRepo.ID, githubRepo.Name, githubRepo.PushedAt, githubRepo.UpdatedAt, githubRepo.Language, githubRepo.StarsCount, githubRepo.Fork, githubRepo.ForksCount)
 	}
 
 	userRows := sqlmock.NewRows(userColumns)
-	userRows.AddRow(userData.Username, userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt)
+	userRows.AddRow(userData.Username, userData.Name, userData.Company, userData.Location, userData.Bio, userData.PublicRepos, userData.Followers, userData.Following, userData.FetchedAt, nil)
 	mock.ExpectBegin()
 
 	mock.ExpectQuery(`
-	select username, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at from github_data.users
+	select username, name, company, location, bio, public_repos_count, followers_count, following_count, fetched_at, etags from github_data.users
 	where username_normalized = $1;
 	`).WithArgs(domain.NormalizeGithubUsername(userData.Username)).WillReturnRows(userRows)
 
 	mock.ExpectQuery(`
-	select github_id, pushed_at, updated_at, language, stars_count, is_fork, forks_count from github_data.repositories
+	select total, commits, pull_requests, issues, reviews, current_streak, longest_streak from github_data.user_contributions
+	where username_normalized = $1;
+	`).WithArgs(domain.NormalizeGithubUsername(userData.Username)).WillReturnRows(
+		sqlmock.NewRows([]string{"total", "commits", "pull_requests", "issues", "reviews", "current_streak", "longest_streak"}).
+			AddRow(420, 300, 40, 12, 30, 5, 21),
+	)
+
+	mock.ExpectQuery(`
+	select github_id, name, pushed_at, updated_at, language, stars_count, is_fork, forks_count from github_data.repositories
 	where owner_username_normalized = $1;
 	`).WithArgs(domain.NormalizeGithubUsername(userData.Username)).WillReturnRows(githubReposRows)
+
+	mock.ExpectQuery(`
+	select l.repo_github_id, l.language, l.bytes from github_data.repository_languages l
+	join github_data.repositories r on r.github_id = l.repo_github_id
+	where r.owner_username_normalized = $1;
+	`).WithArgs(domain.NormalizeGithubUsername(userData.Username)).WillReturnRows(
+		sqlmock.NewRows([]string{"repo_github_id", "language", "bytes"}).
+			AddRow(123, "Go", 1000).
+			AddRow(123, "Makefile", 20),
+	)
 	mock.ExpectCommit()
 
 	resUserData, err := repo.GetUserData(context.TODO(), userData.Username)
 	require.NoError(t, err)
 	require.Equal(t, userData, resUserData)
 }
+
+func TestETagsMapper(t *testing.T) {
+	etags := &domain.GithubDataETags{
+		User: `W/"user"`,
+		RepoPages: []domain.GithubReposPageETag{
+			{ETag: `W/"page1"`, RepoIDs: []int64{1, 2}, Size: 3},
+			{ETag: `W/"page2"`, RepoIDs: []int64{3}, Size: 1},
+		},
+	}
+
+	dbValue, err := etagsToDB(etags)
+	require.NoError(t, err)
+	require.True(t, dbValue.Valid)
+	require.Equal(t, etags, etagsFromDB([]byte(dbValue.String)))
+
+	dbValue, err = etagsToDB(nil)
+	require.NoError(t, err)
+	require.False(t, dbValue.Valid)
+	require.Nil(t, etagsFromDB(nil))
+	require.Nil(t, etagsFromDB([]byte("not json")))
+}
+
+func TestSaveOrgDataSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	orgData := domain.GithubOrgData{
+		Login:        "Gophers",
+		PublicRepos:  1,
+		Members:      42,
+		Repositories: []domain.GithubRepository{{ID: 1, OwnerUsername: "Gophers", Name: "gopher", StarsCount: 30}},
+		FetchedAt:    time.Now(),
+	}
+
+	mock.ExpectExec(`
+	insert into github_data.orgs (login, login_normalized, name, description, location, public_repos_count, members_count, repositories, fetched_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	on conflict (login_normalized) do update set
+		login = EXCLUDED.login,
+		name = EXCLUDED.name,
+		description = EXCLUDED.description,
+		location = EXCLUDED.location,
+		public_repos_count = EXCLUDED.public_repos_count,
+		members_count = EXCLUDED.members_count,
+		repositories = EXCLUDED.repositories,
+		fetched_at = EXCLUDED.fetched_at;
+	`).WithArgs("Gophers", "gophers", orgData.Name, orgData.Description, orgData.Location, 1, 42, sqlmock.AnyArg(), orgData.FetchedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.SaveOrgData(context.TODO(), orgData))
+}
+
+func TestGetOrgDataSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	goLang := "Go"
+	orgData := domain.GithubOrgData{
+		Login:       "Gophers",
+		PublicRepos: 2,
+		Members:     42,
+		Repositories: []domain.GithubRepository{
+			{ID: 1, OwnerUsername: "Gophers", Name: "gopher", Language: &goLang, StarsCount: 30, Languages: map[string]int{"Go": 1000}},
+			{ID: 2, OwnerUsername: "Gophers", Name: "fork", Fork: true},
+		},
+		FetchedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	repos, err := orgReposToDB(orgData.Repositories)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`
+	select login, name, description, location, public_repos_count, members_count, repositories, fetched_at from github_data.orgs
+	where login_normalized = $1;
+	`).WithArgs("gophers").WillReturnRows(
+		sqlmock.NewRows([]string{"login", "name", "description", "location", "public_repos_count", "members_count", "repositories", "fetched_at"}).
+			AddRow(orgData.Login, nil, nil, nil, orgData.PublicRepos, orgData.Members, repos, orgData.FetchedAt),
+	)
+
+	res, err := repo.GetOrgData(context.TODO(), "GOPHERS")
+	require.NoError(t, err)
+	require.Equal(t, orgData, res)
+}
+
+func TestGetOrgDataNotFound(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectQuery(`
+	select login, name, description, location, public_repos_count, members_count, repositories, fetched_at from github_data.orgs
+	where login_normalized = $1;
+	`).WithArgs("ghost").WillReturnRows(
+		sqlmock.NewRows([]string{"login", "name", "description", "location", "public_repos_count", "members_count", "repositories", "fetched_at"}),
+	)
+
+	_, err := repo.GetOrgData(context.TODO(), "ghost")
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
+
+func TestSaveRepoDataSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	mit := "MIT"
+	repoData := domain.GithubRepoData{
+		GithubRepository: domain.GithubRepository{ID: 7, OwnerUsername: "Hurtki", Name: "banners", StarsCount: 120, ForksCount: 8, Languages: map[string]int{"Go": 9000}},
+		OpenIssues:       3,
+		License:          &mit,
+		FetchedAt:        time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`
+	insert into github_data.repositories (github_id, owner_username_normalized, name, description, pushed_at, updated_at, language, stars_count, is_fork, forks_count, open_issues_count, license, latest_release, fetched_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	on conflict (github_id) do update set
+		owner_username_normalized = EXCLUDED.owner_username_normalized,
+		name = EXCLUDED.name,
+		description = EXCLUDED.description,
+		pushed_at = EXCLUDED.pushed_at,
+		updated_at = EXCLUDED.updated_at,
+		language = EXCLUDED.language,
+		stars_count = EXCLUDED.stars_count,
+		is_fork = EXCLUDED.is_fork,
+		forks_count = EXCLUDED.forks_count,
+		open_issues_count = EXCLUDED.open_issues_count,
+		license = EXCLUDED.license,
+		latest_release = EXCLUDED.latest_release,
+		fetched_at = EXCLUDED.fetched_at;
+	`).WithArgs(int64(7), "hurtki", "banners", repoData.Description, repoData.PushedAt, repoData.UpdatedAt, repoData.Language, 120, false, 8, 3, repoData.License, repoData.LatestRelease, repoData.FetchedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`
+	delete from github_data.repository_languages
+	where repo_github_id in ($1);
+	`).WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`
+	insert into github_data.repository_languages (repo_github_id, language, bytes)
+	values ($1, $2, $3);
+	`).WithArgs(int64(7), "Go", 9000).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveRepoData(context.TODO(), repoData))
+}
+
+func TestGetRepoDataSuccess(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	mit, release := "MIT", "v1.2.0"
+	repoData := domain.GithubRepoData{
+		GithubRepository: domain.GithubRepository{ID: 7, OwnerUsername: "hurtki", Name: "banners", StarsCount: 120, ForksCount: 8, Languages: map[string]int{"Go": 9000}},
+		OpenIssues:       3,
+		License:          &mit,
+		LatestRelease:    &release,
+		FetchedAt:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectQuery(`
+	select github_id, owner_username_normalized, name, description, pushed_at, updated_at, language, stars_count, is_fork, forks_count, open_issues_count, license, latest_release, fetched_at from github_data.repositories
+	where owner_username_normalized = $1 and lower(name) = lower($2) and fetched_at is not null
+	order by fetched_at desc
+	limit 1;
+	`).WithArgs("hurtki", "Banners").WillReturnRows(
+		sqlmock.NewRows([]string{"github_id", "owner_username_normalized", "name", "description", "pushed_at", "updated_at", "language", "stars_count", "is_fork", "forks_count", "open_issues_count", "license", "latest_release", "fetched_at"}).
+			AddRow(7, "hurtki", "banners", nil, nil, nil, nil, 120, false, 8, 3, mit, release, repoData.FetchedAt),
+	)
+	mock.ExpectQuery(`
+	select language, bytes from github_data.repository_languages
+	where repo_github_id = $1;
+	`).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows([]string{"language", "bytes"}).AddRow("Go", 9000))
+
+	res, err := repo.GetRepoData(context.TODO(), "Hurtki/Banners")
+	require.NoError(t, err)
+	require.Equal(t, repoData, res)
+}
+
+func TestGetRepoDataNotFound(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectQuery(`
+	select github_id, owner_username_normalized, name, description, pushed_at, updated_at, language, stars_count, is_fork, forks_count, open_issues_count, license, latest_release, fetched_at from github_data.repositories
+	where owner_username_normalized = $1 and lower(name) = lower($2) and fetched_at is not null
+	order by fetched_at desc
+	limit 1;
+	`).WithArgs("hurtki", "ghost").WillReturnRows(
+		sqlmock.NewRows([]string{"github_id", "owner_username_normalized", "name", "description", "pushed_at", "updated_at", "language", "stars_count", "is_fork", "forks_count", "open_issues_count", "license", "latest_release", "fetched_at"}),
+	)
+
+	_, err := repo.GetRepoData(context.TODO(), "hurtki/ghost")
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+
+	// full name without owner is never stored
+	_, err = repo.GetRepoData(context.TODO(), "ghost")
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
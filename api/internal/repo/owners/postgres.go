@@ -0,0 +1,19 @@
+package owners_repo
+
+import (
+	"database/sql"
+
+	"github.com/hurtki/github-banners/api/internal/logger"
+)
+
+type PostgresRepo struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewPostgresRepo(db *sql.DB, logger logger.Logger) *PostgresRepo {
+	return &PostgresRepo{
+		db:     db,
+		logger: logger.With("repo", "owners-repo"),
+	}
+}
@@ -0,0 +1,114 @@
+package owners_repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hurtki/github-banners/api/internal/domain"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+)
+
+// SaveChallenge saves new challenge for the username, token of already verified owner is kept
+// challenge, that hasn't expired at now, isn't replaced, then ErrNothingChanged is returned
+func (r *PostgresRepo) SaveChallenge(ctx context.Context, githubUsername string, challenge string, expiresAt time.Time, now time.Time) error {
+	fn := "internal.repo.owners.PostgresRepo.SaveChallenge"
+	if githubUsername == "" {
+		return repoerr.ErrEmptyField{Field: "github_username"}
+	}
+	if challenge == "" {
+		return repoerr.ErrEmptyField{Field: "challenge"}
+	}
+
+	const q = `
+	insert into banner_owners (github_username_normalized, challenge, challenge_expires_at)
+	values ($1, $2, $3)
+	on conflict (github_username_normalized) do update set
+		challenge = EXCLUDED.challenge,
+		challenge_expires_at = EXCLUDED.challenge_expires_at
+	where banner_owners.challenge is null or banner_owners.challenge_expires_at <= $4;
+	`
+
+	res, err := r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), challenge, expiresAt.UTC(), now.UTC())
+	if err != nil {
+		r.logger.Error("unexpected error when saving challenge", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.logger.Error("unexpected error when reading affected rows", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	if affected == 0 {
+		return repoerr.ErrNothingChanged
+	}
+	return nil
+}
+
+func (r *PostgresRepo) GetOwner(ctx context.Context, githubUsername string) (domain.BannerOwner, error) {
+	fn := "internal.repo.owners.PostgresRepo.GetOwner"
+	const q = `
+	select challenge, challenge_expires_at, token_hash, verified_at from banner_owners
+	where github_username_normalized = $1;`
+
+	owner := domain.BannerOwner{Username: githubUsername}
+	var challenge, tokenHash sql.NullString
+	var challengeExpiresAt, verifiedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, q, domain.NormalizeGithubUsername(githubUsername)).
+		Scan(&challenge, &challengeExpiresAt, &tokenHash, &verifiedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.BannerOwner{}, repoerr.ErrNothingFound
+		}
+		r.logger.Error("unexpected error when getting owner", "source", fn, "err", err)
+		return domain.BannerOwner{}, repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	owner.Challenge = challenge.String
+	owner.TokenHash = tokenHash.String
+	if challengeExpiresAt.Valid {
+		owner.ChallengeExpiresAt = challengeExpiresAt.Time
+	}
+	if verifiedAt.Valid {
+		owner.VerifiedAt = &verifiedAt.Time
+	}
+	return owner, nil
+}
+
+// SaveToken saves token only if owner's challenge is still the verified one
+// returns ErrNothingFound, if challenge was already used or replaced
+func (r *PostgresRepo) SaveToken(ctx context.Context, githubUsername string, challenge string, tokenHash string) error {
+	fn := "internal.repo.owners.PostgresRepo.SaveToken"
+	if challenge == "" {
+		return repoerr.ErrEmptyField{Field: "challenge"}
+	}
+	if tokenHash == "" {
+		return repoerr.ErrEmptyField{Field: "token_hash"}
+	}
+
+	const q = `
+	update banner_owners
+	set token_hash = $2, verified_at = CURRENT_TIMESTAMP, challenge = null, challenge_expires_at = null
+	where github_username_normalized = $1 and challenge = $3`
+
+	res, err := r.db.ExecContext(ctx, q, domain.NormalizeGithubUsername(githubUsername), tokenHash, challenge)
+	if err != nil {
+		r.logger.Error("unexpected error when saving token", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.logger.Error("unexpected error when reading affected rows", "source", fn, "err", err)
+		return repoerr.ErrRepoInternal{Note: err.Error()}
+	}
+
+	if affected == 0 {
+		return repoerr.ErrNothingFound
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package owners_repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hurtki/github-banners/api/internal/domain"
+	"github.com/hurtki/github-banners/api/internal/logger"
+	repoerr "github.com/hurtki/github-banners/api/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+type LoggerMock struct{}
+
+func (m LoggerMock) Debug(a string, b ...any)    {}
+func (m LoggerMock) Info(a string, b ...any)     {}
+func (m LoggerMock) Warn(a string, b ...any)     {}
+func (m LoggerMock) Error(a string, b ...any)    {}
+func (m LoggerMock) With(a ...any) logger.Logger { return m }
+
+func getMockAndRepo(t *testing.T) (sqlmock.Sqlmock, *PostgresRepo) {
+	db, mock, _ := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return mock, NewPostgresRepo(db, LoggerMock{})
+}
+
+const saveChallengeQuery = `
+	insert into banner_owners (github_username_normalized, challenge, challenge_expires_at)
+	values ($1, $2, $3)
+	on conflict (github_username_normalized) do update set
+		challenge = EXCLUDED.challenge,
+		challenge_expires_at = EXCLUDED.challenge_expires_at
+	where banner_owners.challenge is null or banner_owners.challenge_expires_at <= $4;
+	`
+
+func TestSaveChallengeKeepsToken(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	expiresAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	now := expiresAt.Add(-time.Hour)
+
+	// conflict updates only challenge columns, so token_hash and verified_at of verified owner stay the same
+	mock.ExpectExec(saveChallengeQuery).
+		WithArgs("hurtki", "challenge", expiresAt.UTC(), now.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.SaveChallenge(context.TODO(), "HurtKi", "challenge", expiresAt, now))
+}
+
+func TestSaveChallengeKeepsUnexpiredChallenge(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	expiresAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := expiresAt.Add(-time.Hour)
+
+	// conflict row has unexpired challenge, so where clause skips the update
+	mock.ExpectExec(saveChallengeQuery).
+		WithArgs("hurtki", "challenge", expiresAt, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.SaveChallenge(context.TODO(), "hurtki", "challenge", expiresAt, now)
+	require.ErrorIs(t, err, repoerr.ErrNothingChanged)
+}
+
+func TestSaveChallengeEmptyFields(t *testing.T) {
+	_, repo := getMockAndRepo(t)
+
+	err := repo.SaveChallenge(context.TODO(), "", "challenge", time.Now(), time.Now())
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "github_username"})
+
+	err = repo.SaveChallenge(context.TODO(), "hurtki", "", time.Now(), time.Now())
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "challenge"})
+}
+
+func TestSaveChallengeInternalError(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	expiresAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(saveChallengeQuery).
+		WithArgs("hurtki", "challenge", expiresAt, expiresAt).
+		WillReturnError(errors.New("connection reset"))
+
+	err := repo.SaveChallenge(context.TODO(), "hurtki", "challenge", expiresAt, expiresAt)
+	require.ErrorAs(t, err, &repoerr.ErrRepoInternal{})
+}
+
+const getOwnerQuery = `
+	select challenge, challenge_expires_at, token_hash, verified_at from banner_owners
+	where github_username_normalized = $1;`
+
+func TestGetOwnerVerified(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	verifiedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(getOwnerQuery).
+		WithArgs("hurtki").
+		WillReturnRows(sqlmock.NewRows([]string{"challenge", "challenge_expires_at", "token_hash", "verified_at"}).
+			AddRow(nil, nil, "hash", verifiedAt))
+
+	owner, err := repo.GetOwner(context.TODO(), "HurtKi")
+	require.NoError(t, err)
+	require.Equal(t, domain.BannerOwner{Username: "HurtKi", TokenHash: "hash", VerifiedAt: &verifiedAt}, owner)
+}
+
+func TestGetOwnerWithChallenge(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+	expiresAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(getOwnerQuery).
+		WithArgs("hurtki").
+		WillReturnRows(sqlmock.NewRows([]string{"challenge", "challenge_expires_at", "token_hash", "verified_at"}).
+			AddRow("challenge", expiresAt, nil, nil))
+
+	owner, err := repo.GetOwner(context.TODO(), "hurtki")
+	require.NoError(t, err)
+	require.Equal(t, domain.BannerOwner{Username: "hurtki", Challenge: "challenge", ChallengeExpiresAt: expiresAt}, owner)
+}
+
+func TestGetOwnerNotFound(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectQuery(getOwnerQuery).
+		WithArgs("hurtki").
+		WillReturnRows(sqlmock.NewRows([]string{"challenge", "challenge_expires_at", "token_hash", "verified_at"}))
+
+	_, err := repo.GetOwner(context.TODO(), "hurtki")
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
+
+const saveTokenQuery = `
+	update banner_owners
+	set token_hash = $2, verified_at = CURRENT_TIMESTAMP, challenge = null, challenge_expires_at = null
+	where github_username_normalized = $1 and challenge = $3`
+
+func TestSaveToken(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	mock.ExpectExec(saveTokenQuery).
+		WithArgs("hurtki", "hash", "challenge").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.SaveToken(context.TODO(), "HurtKi", "challenge", "hash"))
+}
+
+func TestSaveTokenChallengeReplaced(t *testing.T) {
+	mock, repo := getMockAndRepo(t)
+
+	// challenge was used or replaced by concurrent request, so no row matches it
+	mock.ExpectExec(saveTokenQuery).
+		WithArgs("hurtki", "hash", "challenge").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.SaveToken(context.TODO(), "hurtki", "challenge", "hash")
+	require.ErrorIs(t, err, repoerr.ErrNothingFound)
+}
+
+func TestSaveTokenEmptyFields(t *testing.T) {
+	_, repo := getMockAndRepo(t)
+
+	err := repo.SaveToken(context.TODO(), "hurtki", "challenge", "")
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "token_hash"})
+
+	err = repo.SaveToken(context.TODO(), "hurtki", "", "hash")
+	require.ErrorIs(t, err, repoerr.ErrEmptyField{Field: "challenge"})
+}
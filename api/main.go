@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,25 +12,34 @@ import (
 	"github.com/go-chi/chi/v5"
 	banners_worker "github.com/hurtki/github-banners/api/internal/app/banners"
 	user_stats_worker "github.com/hurtki/github-banners/api/internal/app/user_stats"
+	webhooks_worker "github.com/hurtki/github-banners/api/internal/app/webhooks"
 	"github.com/hurtki/github-banners/api/internal/cache"
 	"github.com/hurtki/github-banners/api/internal/config"
 	"github.com/hurtki/github-banners/api/internal/domain"
 	longterm "github.com/hurtki/github-banners/api/internal/domain/long-term"
+	"github.com/hurtki/github-banners/api/internal/domain/ownership"
 	"github.com/hurtki/github-banners/api/internal/domain/preview"
+	"github.com/hurtki/github-banners/api/internal/domain/themes"
+	"github.com/hurtki/github-banners/api/internal/domain/tokens"
 	userstats "github.com/hurtki/github-banners/api/internal/domain/user_stats"
 	"github.com/hurtki/github-banners/api/internal/handlers"
 	infraDB "github.com/hurtki/github-banners/api/internal/infrastructure/db"
 	infraGithub "github.com/hurtki/github-banners/api/internal/infrastructure/github"
-	http_auth "github.com/hurtki/github-banners/api/internal/infrastructure/httpauth"
 	"github.com/hurtki/github-banners/api/internal/infrastructure/kafka"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/pglock"
 	"github.com/hurtki/github-banners/api/internal/infrastructure/renderer"
 	renderer_http "github.com/hurtki/github-banners/api/internal/infrastructure/renderer/http"
+	"github.com/hurtki/github-banners/api/internal/infrastructure/resp"
 	"github.com/hurtki/github-banners/api/internal/infrastructure/server"
 	"github.com/hurtki/github-banners/api/internal/infrastructure/storage"
 	"github.com/hurtki/github-banners/api/internal/logger"
 	"github.com/hurtki/github-banners/api/internal/migrations"
 	banners_repo "github.com/hurtki/github-banners/api/internal/repo/banners"
+	github_missing_repo "github.com/hurtki/github-banners/api/internal/repo/github_missing"
+	github_tokens_repo "github.com/hurtki/github-banners/api/internal/repo/github_tokens"
 	github_data_repo "github.com/hurtki/github-banners/api/internal/repo/github_user_data"
+	owners_repo "github.com/hurtki/github-banners/api/internal/repo/owners"
+	http_auth "github.com/hurtki/github-banners/httpauth"
 )
 
 func main() {
@@ -47,16 +57,81 @@ func main() {
 
 	}
 
-	// cache
-	statsCache := cache.NewStatsMemoryCache(cfg.CacheTTL)
+	// caches, remote backends are shared by replicas and survive restarts
+	cacheBackend, ok := cache.ParseBackend(cfg.CacheBackend)
+	if !ok {
+		logger.Error("unknown cache backend, expected memory, remote or layered", "cache_backend", cfg.CacheBackend)
+		os.Exit(1)
+	}
+	var cacheClient *resp.Client
+	if cacheBackend != cache.BackendMemory {
+		respConf, err := resp.ParseURL(cfg.CacheRemoteURL)
+		if err != nil {
+			logger.Error("can't parse remote cache url", "err", err.Error())
+			os.Exit(1)
+		}
+		cacheClient = resp.NewClient(respConf)
+		defer cacheClient.Close()
+	}
+	if cacheBackend == cache.BackendLayered && cfg.CacheMemoryTTL <= 0 {
+		logger.Error("memory ttl of layered cache should be positive", "cache_memory_ttl", cfg.CacheMemoryTTL)
+		os.Exit(1)
+	}
+	// without shared cache replicas can't use previews of each other, so locks only serialize renders
+	if cfg.PreviewLocks && cacheBackend == cache.BackendMemory {
+		logger.Error("preview locks need remote or layered cache backend", "cache_backend", cfg.CacheBackend)
+		os.Exit(1)
+	}
+	caches := cache.NewFactory(cacheBackend, cacheClient, cfg.CacheTTL, cfg.CacheMemoryTTL, cfg.CacheRemoteTimeout, logger)
+	statsCache := caches.Stats("stats:user:")
+
+	languagesMode, ok := domain.ParseLanguagesMode(cfg.LanguagesMode)
+	if !ok {
+		logger.Error("unknown languages mode, expected primary or bytes", "languages_mode", cfg.LanguagesMode)
+		os.Exit(1)
+	}
 
 	// Create service configuration
 	serviceConfig := &domain.ServiceConfig{
 		CacheTTL:       cfg.CacheTTL,
 		RequestTimeout: cfg.RequestTimeout,
+		LanguagesMode:  languagesMode,
+
+		ClientWaitTimeout: cfg.GithubClientWaitTimeout,
+	}
+	// users data can be fetched with GraphQL api, it shares the clients, but spends their GraphQL points budget
+	var useGraphQL bool
+	switch cfg.GithubAPI {
+	case "rest":
+	case "graphql":
+		useGraphQL = true
+	default:
+		logger.Error("unknown github api, expected rest or graphql", "github_api", cfg.GithubAPI)
+		os.Exit(1)
+	}
+
+	// installations of GitHub App are used in the same rotation as tokens, each with its own rate limit
+	var githubApp *infraGithub.AppConfig
+	if cfg.GithubAppID != 0 {
+		githubApp = &infraGithub.AppConfig{
+			ID:              cfg.GithubAppID,
+			PrivateKeyPath:  cfg.GithubAppPrivateKeyPath,
+			InstallationIDs: cfg.GithubAppInstallationIDs,
+		}
+	}
+
+	// Create GitHub fetcher (infrastructure layer), it routes requests to github hosts by usernames' namespace
+	githubFetcher, err := infraGithub.NewHostsFetcher(infraGithub.HostsConfig{
+		Tokens:    cfg.GithubTokens,
+		BaseURL:   cfg.GithubBaseURL,
+		UploadURL: cfg.GithubUploadURL,
+		GraphQL:   useGraphQL,
+		App:       githubApp,
+	}, serviceConfig, logger)
+	if err != nil {
+		logger.Error("can't create github fetcher", "err", err.Error())
+		os.Exit(1)
 	}
-	// Create GitHub fetcher (infrastructure layer)
-	githubFetcher := infraGithub.NewFetcher(cfg.GithubTokens, serviceConfig, logger)
 
 	db, err := infraDB.NewDB(psgrConf, logger)
 	if err != nil {
@@ -69,21 +144,71 @@ func main() {
 	}
 	githubDataRepo := github_data_repo.NewGithubDataPsgrRepo(db, logger)
 
+	// tokens, added at runtime, are stored only if encryption key is set
+	var tokensRepo tokens.TokensRepo
+	if cfg.GithubTokensEncryptionKey != "" {
+		tokensRepo, err = github_tokens_repo.NewPostgresRepo(db, cfg.GithubTokensEncryptionKey, logger)
+		if err != nil {
+			logger.Error("can't create github tokens repo", "err", err.Error())
+			os.Exit(1)
+		}
+	}
+	tokensUsecase := tokens.NewTokensUsecase(githubFetcher, tokensRepo)
+	restoreCtx, cancelRestore := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := tokensUsecase.Restore(restoreCtx); err != nil {
+		logger.Warn("some of stored github tokens weren't restored", "err", err.Error())
+	}
+	cancelRestore()
+
+	statsConfig := userstats.Config{
+		LanguagesMode:     languagesMode,
+		ExcludedLanguages: cfg.ExcludedLanguages,
+		SoftTTL:           cfg.StatsSoftTTL,
+		HardTTL:           cfg.StatsHardTTL,
+		RefreshWorkers:    cfg.StatsRefreshWorkers,
+		RefreshQueueSize:  cfg.StatsRefreshQueueSize,
+	}
+
+	// usernames, that github doesn't have, aren't fetched again until negative cache ttl expires
+	var missingRepo cache.MissingRepo
+	if cfg.NegativeCachePersist {
+		missingRepo = github_missing_repo.NewPostgresRepo(db, logger)
+	}
+	negativeCache := cache.NewNegativeCache(cfg.NegativeCacheTTL, missingRepo, time.Second, logger)
+	purgeMissingCtx, cancelPurgeMissing := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := negativeCache.Purge(purgeMissingCtx); err != nil {
+		logger.Warn("can't purge expired entries of negative cache", "err", err.Error())
+	}
+	cancelPurgeMissing()
+
 	// Create stats service (domain service with cache)
-	statsService := userstats.NewUserStatsService(githubDataRepo, githubFetcher, statsCache)
+	statsService := userstats.NewUserStatsService(githubDataRepo, githubFetcher, statsCache, negativeCache, statsConfig)
+
+	// organizations are fetched with REST api only, their stats are cached separately from users' ones
+	orgStatsService := userstats.NewOrgStatsService(githubDataRepo, githubFetcher, caches.Stats("stats:org:"), negativeCache, statsConfig)
+
+	// single repositories are fetched with REST api too, full names are cached separately from logins
+	repoStatsService := userstats.NewRepoStatsService(githubDataRepo, githubFetcher, caches.Stats("stats:repo:"), negativeCache, statsConfig)
 
 	router := chi.NewRouter()
 
 	// renderer infra intialization
-	rendererAuthRT := http_auth.NewAuthHTTPRoundTripper("api", http_auth.NewHMACSigner([]byte(cfg.ServicesSecret)), time.Now)
+	servicesKeyring, err := http_auth.LoadKeyring(cfg.ServicesSecretKeys, cfg.ServicesSecret)
+	if err != nil {
+		logger.Error("can't load services secret keys", "err", err)
+		os.Exit(1)
+	}
+
+	rendererAuthRT := http_auth.NewAuthHTTPRoundTripper("api", servicesKeyring, time.Now, http_auth.SignatureVersion(cfg.SignatureVersion))
 	rendererHTTPClient := renderer_http.NewRendererHTTPClient(rendererAuthRT)
 	rendererCl := renderer.NewRenderer(rendererHTTPClient, logger, cfg.RendererBaseURL)
 
 	// storage infra initialization
 	storageAuthRT := http_auth.NewAuthHTTPRoundTripper(
 		"api",
-		http_auth.NewHMACSigner([]byte(cfg.ServicesSecret)),
+		servicesKeyring,
 		time.Now,
+		http_auth.SignatureVersion(cfg.SignatureVersion),
 	)
 
 	storageHTTPClient := &http.Client{
@@ -96,9 +221,15 @@ func main() {
 		logger,
 	)
 
-	previewService := preview.NewPreviewService(rendererCl, cache.NewPreviewMemoryCache(cfg.CacheTTL))
+	var previewLocker preview.Locker
+	if cfg.PreviewLocks {
+		previewLocker = pglock.NewLocker(db)
+	}
+	previewService := preview.NewPreviewService(rendererCl, caches.Preview(), previewLocker)
+
+	themesCatalog := themes.NewCatalog(rendererCl, cfg.ThemesRefreshInterval)
 
-	previewUsecase := preview.NewPreviewUsecase(statsService, previewService)
+	previewUsecase := preview.NewPreviewUsecase(statsService, orgStatsService, repoStatsService, previewService, themesCatalog)
 
 	kafkaProducer, err := kafka.NewBannerProducer([]string{"kafka:9092"}, "banner-update", config.NewProducerConfig(), logger)
 	if err != nil {
@@ -108,23 +239,76 @@ func main() {
 
 	bannersRepo := banners_repo.NewPostgresRepo(db, logger)
 
+	ownersRepo := owners_repo.NewPostgresRepo(db, logger)
+	ownershipUsecase := ownership.NewOwnershipUsecase(ownersRepo, githubFetcher, ownership.Config{
+		ChallengeTTL: cfg.OwnershipChallengeTTL,
+		Required:     cfg.OwnershipRequired,
+	})
+
 	ltBannersUsecase := longterm.NewLTBannersUsecase(
 		bannersRepo,
 		kafkaProducer,
 		previewService,
 		storageCl,
 		statsService,
+		orgStatsService,
+		repoStatsService,
+		ownershipUsecase,
+		themesCatalog,
 	)
 
-	bannersHandler := handlers.NewBannersHandler(logger, previewUsecase, ltBannersUsecase)
+	bannersHandler := handlers.NewBannersHandler(logger, previewUsecase, ltBannersUsecase, ownershipUsecase)
 
 	// http handlers
 	router.Get("/banners/preview", bannersHandler.Preview)
 	router.Post("/banners", bannersHandler.Create)
+	router.Get("/banners", bannersHandler.List)
+	router.Get("/banners/{username}/{type}", bannersHandler.Get)
+	router.Delete("/banners/{username}/{type}", bannersHandler.Delete)
+	// banners of repositories are addressed by full name of repository
+	router.Get("/banners/{owner}/{repo}/{type}", bannersHandler.Get)
+	router.Delete("/banners/{owner}/{repo}/{type}", bannersHandler.Delete)
+	router.Post("/ownership/{username}/challenge", bannersHandler.CreateChallenge)
+	router.Post("/ownership/{username}/verify", bannersHandler.VerifyOwnership)
+
+	// counters are served by /admin/metrics together with runtime ones of expvar
+	expvar.Publish("user_stats", expvar.Func(func() any { return statsService.Metrics() }))
+	expvar.Publish("org_stats", expvar.Func(func() any { return orgStatsService.Metrics() }))
+	expvar.Publish("repo_stats", expvar.Func(func() any { return repoStatsService.Metrics() }))
+
+	// admin endpoints exist only with admin token
+	if cfg.AdminToken != "" {
+		adminHandler := handlers.NewAdminHandler(logger, tokensUsecase, negativeCache, cfg.AdminToken)
+		router.Route("/admin", func(r chi.Router) {
+			r.Use(adminHandler.Authorize)
+			r.Get("/github-tokens", adminHandler.ListTokens)
+			r.Post("/github-tokens", adminHandler.AddToken)
+			r.Delete("/github-tokens/{id}", adminHandler.DisableToken)
+			r.Get("/metrics", expvar.Handler().ServeHTTP)
+			r.Delete("/missing-users/{username}", adminHandler.ForgetMissing)
+		})
+	}
+
+	// github webhooks refresh banners of repository's owner without waiting for banners worker
+	var webhooksDebouncer *webhooks_worker.RefreshDebouncer
+	if cfg.GithubWebhookSecret != "" {
+		webhooksDebouncer = webhooks_worker.NewRefreshDebouncer(logger, ltBannersUsecase.RefreshOwner, cfg.GithubWebhookDebounce)
+		webhooksHandler := handlers.NewWebhooksHandler(logger, webhooksDebouncer, cfg.GithubWebhookSecret)
+		router.Post("/webhooks/github", webhooksHandler.Github)
+	}
+
+	// with leader election only one replica runs scheduled workers, single node runs them without it
+	var workersLeader interface{ IsLeader() bool }
+	var leaderElector *pglock.Elector
+	if cfg.LeaderElection {
+		leaderElector = pglock.NewElector(db, cfg.LeaderLockKey, cfg.LeaderElectionInterval, logger)
+		leaderElector.Start()
+		workersLeader = leaderElector
+	}
 
 	// workers startup
-	ltBannersUpdateWorker := banners_worker.NewBannersWorker(logger, ltBannersUsecase.UpdateAll, time.Hour, longterm.UpdateAllConfig{Concurrency: 20})
-	statsWorker := user_stats_worker.NewStatsWorker(statsService.RefreshAll, time.Hour, logger, userstats.WorkerConfig{BatchSize: 5, Concurrency: 10})
+	ltBannersUpdateWorker := banners_worker.NewBannersWorker(logger, ltBannersUsecase.UpdateAll, time.Hour, longterm.UpdateAllConfig{Concurrency: 20}, workersLeader)
+	statsWorker := user_stats_worker.NewStatsWorker(statsService.RefreshAll, time.Hour, logger, userstats.WorkerConfig{BatchSize: 5, Concurrency: 10}, workersLeader)
 
 	ltBannersUpdateWorker.Start()
 	statsWorker.Start()
@@ -141,6 +325,15 @@ func main() {
 	quitCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	ltBannersUpdateWorker.Close(quitCtx)
 	statsWorker.Close(quitCtx)
+	statsService.Close(quitCtx)
+	orgStatsService.Close(quitCtx)
+	repoStatsService.Close(quitCtx)
+	if leaderElector != nil {
+		leaderElector.Close(quitCtx)
+	}
+	if webhooksDebouncer != nil {
+		webhooksDebouncer.Close(quitCtx)
+	}
 	srv.Close(quitCtx)
 
 	cancel()
@@ -0,0 +1,16 @@
+package httpauth
+
+import "errors"
+
+var (
+	ErrMissingAuthHeaders = errors.New("missing auth headers")
+	ErrServiceNotAllowed  = errors.New("service is not allowed")
+	ErrInvalidTimestamp   = errors.New("invalid timestamp")
+	ErrStaleTimestamp     = errors.New("timestamp is out of allowed clock skew")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrUnsupportedVersion = errors.New("unsupported signature version")
+	ErrReplayedRequest    = errors.New("nonce was already used")
+	ErrNonceCacheFull     = errors.New("nonce cache is full of unexpired nonces")
+	ErrUnreadableBody     = errors.New("can't read request body")
+	ErrBodyTooLarge       = errors.New("request body is too large")
+)
@@ -0,0 +1,3 @@
+module github.com/hurtki/github-banners/httpauth
+
+go 1.25.5
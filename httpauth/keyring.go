@@ -0,0 +1,138 @@
+package httpauth
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// legacyKeyID is an id of the key, that is built from single SERVICES_SECRET_KEY
+const legacyKeyID = "default"
+
+var (
+	ErrNoActiveKey      = errors.New("keyring has no active key")
+	ErrInvalidKeyring   = errors.New("invalid keyring")
+	ErrDuplicatedKeyID  = errors.New("duplicated key id in keyring")
+	ErrInvalidKeyExpiry = errors.New("invalid key not-after date")
+)
+
+// Key is one named secret of the Keyring
+// zero NotAfter means that key never expires
+type Key struct {
+	ID       string
+	NotAfter time.Time
+	signer   *HMACSigner
+}
+
+// NewKey creates key with given id and secret
+// panics if secret is blank ( as NewHMACSigner )
+func NewKey(id string, secret []byte, notAfter time.Time) Key {
+	return Key{ID: id, NotAfter: notAfter, signer: NewHMACSigner(secret)}
+}
+
+// ActiveAt reports, whether key can be used at given time
+func (k Key) ActiveAt(t time.Time) bool {
+	return k.NotAfter.IsZero() || t.Before(k.NotAfter)
+}
+
+// Keyring is a list of secrets, that allows to rotate them without coordinated restart of all the services
+// The first active key is used for signing, any active key is accepted while verifying
+type Keyring struct {
+	keys []Key
+}
+
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrInvalidKeyring)
+	}
+	seen := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, fmt.Errorf("%w: blank key id", ErrInvalidKeyring)
+		}
+		if _, ok := seen[k.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicatedKeyID, k.ID)
+		}
+		seen[k.ID] = struct{}{}
+	}
+	return &Keyring{keys: keys}, nil
+}
+
+// ParseKeyring parses keyring from "kid1:secret1,kid2:secret2:2026-12-31" format
+// third optional part of every key is its not-after date ( YYYY-MM-DD or RFC3339 )
+// so secrets can't contain ',' and ':' symbols
+func ParseKeyring(raw string) (*Keyring, error) {
+	keys := []Key{}
+	for entry := range strings.SplitSeq(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: key should be in kid:secret[:not-after] format", ErrInvalidKeyring)
+		}
+
+		var notAfter time.Time
+		if len(parts) == 3 {
+			t, err := parseNotAfter(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("%w: key %s: %w", ErrInvalidKeyExpiry, parts[0], err)
+			}
+			notAfter = t
+		}
+		keys = append(keys, NewKey(parts[0], []byte(parts[1]), notAfter))
+	}
+	return NewKeyring(keys...)
+}
+
+// LoadKeyring parses keys list if it's not blank
+// otherwise falls back to keyring with only one legacy secret
+func LoadKeyring(keys string, legacySecret string) (*Keyring, error) {
+	if strings.TrimSpace(keys) != "" {
+		return ParseKeyring(keys)
+	}
+	if legacySecret == "" {
+		return nil, fmt.Errorf("%w: neither keys list nor legacy secret are set", ErrInvalidKeyring)
+	}
+	return NewKeyring(NewKey(legacyKeyID, []byte(legacySecret), time.Time{}))
+}
+
+func parseNotAfter(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// Sign signs data using the first key, that is active at now
+// returns id of the used key, so verifier knows which secret to use
+func (k *Keyring) Sign(data []byte, now time.Time) (string, string, error) {
+	for _, key := range k.keys {
+		if key.ActiveAt(now) {
+			return key.ID, key.signer.Sign(data), nil
+		}
+	}
+	return "", "", ErrNoActiveKey
+}
+
+// Verify checks signature of data with the key of given id
+// if keyID is blank ( sender doesn't know about keyring yet ) every active key is tried
+// key is accepted if it was active at given time
+func (k *Keyring) Verify(keyID string, data []byte, signature string, at time.Time) bool {
+	for _, key := range k.keys {
+		if keyID != "" && key.ID != keyID {
+			continue
+		}
+		if !key.ActiveAt(at) {
+			continue
+		}
+		// hmac.Equal compares in constant time, so signature can't be guessed byte by byte
+		if hmac.Equal([]byte(key.signer.Sign(data)), []byte(signature)) {
+			return true
+		}
+	}
+	return false
+}
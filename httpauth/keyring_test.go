@@ -0,0 +1,205 @@
+package httpauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		ids  []string
+		err  error
+	}{
+		{name: "one key", raw: "k1:s1", ids: []string{"k1"}},
+		{name: "several keys", raw: "k1:s1, k2:s2:2026-12-31,", ids: []string{"k1", "k2"}},
+		{name: "rfc3339 expiry", raw: "k1:s1:2026-12-31T10:00:00Z", ids: []string{"k1"}},
+		{name: "blank", raw: " , ", err: ErrInvalidKeyring},
+		{name: "without secret", raw: "k1", err: ErrInvalidKeyring},
+		{name: "blank secret", raw: "k1:", err: ErrInvalidKeyring},
+		{name: "blank id", raw: ":s1", err: ErrInvalidKeyring},
+		{name: "invalid expiry", raw: "k1:s1:tomorrow", err: ErrInvalidKeyExpiry},
+		{name: "duplicated id", raw: "k1:s1,k1:s2", err: ErrDuplicatedKeyID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := ParseKeyring(tt.raw)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(keyring.keys) != len(tt.ids) {
+				t.Fatalf("expected %d keys, got %d", len(tt.ids), len(keyring.keys))
+			}
+			for i, id := range tt.ids {
+				if keyring.keys[i].ID != id {
+					t.Fatalf("expected key %d to be %s, got %s", i, id, keyring.keys[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestParseKeyringExpiry(t *testing.T) {
+	keyring, err := ParseKeyring("k1:s1:2026-12-31,k2:s2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC); !keyring.keys[0].NotAfter.Equal(want) {
+		t.Fatalf("expected not-after %v, got %v", want, keyring.keys[0].NotAfter)
+	}
+	if !keyring.keys[1].NotAfter.IsZero() {
+		t.Fatalf("expected key without expiry, got %v", keyring.keys[1].NotAfter)
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	keyring, err := LoadKeyring("", "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := []byte("data")
+	signature := NewHMACSigner([]byte("legacy")).Sign(data)
+	if !keyring.Verify(legacyKeyID, data, signature, testNow) {
+		t.Fatal("expected legacy secret to be accepted with legacy key id")
+	}
+	if !keyring.Verify("", data, signature, testNow) {
+		t.Fatal("expected legacy secret to be accepted without key id")
+	}
+
+	// keys list has priority over legacy secret
+	keyring, err = LoadKeyring("k1:s1", "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keyring.Verify("", data, signature, testNow) {
+		t.Fatal("expected legacy secret to be ignored, when keys are set")
+	}
+
+	if _, err := LoadKeyring(" ", ""); !errors.Is(err, ErrInvalidKeyring) {
+		t.Fatalf("expected ErrInvalidKeyring, got %v", err)
+	}
+	if _, err := LoadKeyring("k1", "legacy"); !errors.Is(err, ErrInvalidKeyring) {
+		t.Fatalf("expected ErrInvalidKeyring for malformed keys, got %v", err)
+	}
+}
+
+func TestKeyringVerify(t *testing.T) {
+	notAfter := testNow.Add(time.Hour)
+	keyring, err := NewKeyring(NewKey("old", []byte("s-old"), notAfter), NewKey("new", []byte("s-new"), time.Time{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := []byte("data")
+	oldSignature := NewHMACSigner([]byte("s-old")).Sign(data)
+	newSignature := NewHMACSigner([]byte("s-new")).Sign(data)
+
+	tests := []struct {
+		name      string
+		keyID     string
+		signature string
+		at        time.Time
+		ok        bool
+	}{
+		{name: "new key", keyID: "new", signature: newSignature, at: testNow, ok: true},
+		{name: "old key before not-after", keyID: "old", signature: oldSignature, at: testNow, ok: true},
+		{name: "old key after not-after", keyID: "old", signature: oldSignature, at: notAfter, ok: false},
+		{name: "unknown key id", keyID: "other", signature: newSignature, at: testNow, ok: false},
+		{name: "signature of other key", keyID: "new", signature: oldSignature, at: testNow, ok: false},
+		{name: "without key id", keyID: "", signature: oldSignature, at: testNow, ok: true},
+		{name: "without key id after not-after", keyID: "", signature: oldSignature, at: notAfter, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyring.Verify(tt.keyID, data, tt.signature, tt.at); got != tt.ok {
+				t.Fatalf("expected %v, got %v", tt.ok, got)
+			}
+		})
+	}
+}
+
+func TestVerifierKeyRotation(t *testing.T) {
+	keys := []Key{
+		NewKey("old", []byte("s-old"), testNow.Add(-30*time.Second)),
+		NewKey("expired", []byte("s-expired"), testNow.Add(-2*time.Minute)),
+		NewKey("new", []byte("s-new"), time.Time{}),
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		keyID  string
+		status int
+	}{
+		{name: "new key", secret: "s-new", keyID: "new", status: http.StatusOK},
+		{name: "key expired within clock skew", secret: "s-old", keyID: "old", status: http.StatusOK},
+		{name: "key past not-after", secret: "s-expired", keyID: "expired", status: http.StatusUnauthorized},
+		{name: "unknown key id", secret: "s-new", keyID: "other", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(t, testConfig(), keys...)
+			r := httptest.NewRequest(http.MethodGet, "/themes", nil)
+			signV1(r, []byte(tt.secret), tt.keyID, "api", testNow)
+
+			if status, _ := serve(v, r); status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestKeyringSignUsesFirstActiveKey(t *testing.T) {
+	keyring, err := ParseKeyring("old:secret1:2026-01-01,new:secret2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := []byte("canonical")
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		keyID  string
+		secret string
+	}{
+		{name: "before not-after", now: time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC), keyID: "old", secret: "secret1"},
+		{name: "after not-after", now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), keyID: "new", secret: "secret2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID, signature, err := keyring.Sign(data, tt.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keyID != tt.keyID {
+				t.Fatalf("expected key %s, got %s", tt.keyID, keyID)
+			}
+			if want := NewHMACSigner([]byte(tt.secret)).Sign(data); signature != want {
+				t.Fatalf("expected signature %s, got %s", want, signature)
+			}
+		})
+	}
+}
+
+func TestKeyringSignNoActiveKey(t *testing.T) {
+	keyring, err := ParseKeyring("old:secret1:2026-01-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := keyring.Sign([]byte("canonical"), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrNoActiveKey) {
+		t.Fatalf("expected ErrNoActiveKey, got %v", err)
+	}
+}
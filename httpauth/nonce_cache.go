@@ -0,0 +1,77 @@
+package httpauth
+
+import (
+	"sync"
+	"time"
+)
+
+type nonceEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// nonceCache remembers nonces of already accepted requests for ttl
+// it holds at most capacity nonces, unexpired nonces are never evicted
+// otherwise replay of evicted nonce would be accepted again
+type nonceCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+
+	seen map[string]time.Time
+	// queue of nonces in order of insertion, used for expiration
+	queue []nonceEntry
+}
+
+func newNonceCache(ttl time.Duration, capacity int) *nonceCache {
+	if capacity <= 0 {
+		panic("nonce cache capacity should be positive")
+	}
+	return &nonceCache{
+		ttl:      ttl,
+		capacity: capacity,
+		seen:     make(map[string]time.Time, capacity),
+		queue:    make([]nonceEntry, 0, capacity),
+	}
+}
+
+// Add stores nonce
+// returns ErrReplayedRequest if nonce was already seen and is not expired yet
+// returns ErrNonceCacheFull if there is no place for the nonce, until the oldest one expires
+func (c *nonceCache) Add(nonce string, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired(now)
+
+	if expiresAt, ok := c.seen[nonce]; ok && now.Before(expiresAt) {
+		return ErrReplayedRequest
+	}
+
+	if len(c.queue) >= c.capacity {
+		return ErrNonceCacheFull
+	}
+
+	entry := nonceEntry{nonce: nonce, expiresAt: now.Add(c.ttl)}
+	c.seen[nonce] = entry.expiresAt
+	c.queue = append(c.queue, entry)
+	return nil
+}
+
+func (c *nonceCache) evictExpired(now time.Time) {
+	for len(c.queue) > 0 && !now.Before(c.queue[0].expiresAt) {
+		c.removeOldest()
+	}
+}
+
+func (c *nonceCache) removeOldest() {
+	oldest := c.queue[0]
+	// nonce could be re-added after expiration, then map contains newer entry
+	if c.seen[oldest.nonce].Equal(oldest.expiresAt) {
+		delete(c.seen, oldest.nonce)
+	}
+	// append reallocates queue, when the consumed head leaves no capacity
+	// so the backing array doesn't grow without limit
+	c.queue[0] = nonceEntry{}
+	c.queue = c.queue[1:]
+}
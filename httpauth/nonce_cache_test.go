@@ -0,0 +1,56 @@
+package httpauth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNonceCacheRejectsSeenNonce(t *testing.T) {
+	c := newNonceCache(time.Minute, 4)
+
+	if err := c.Add("n1", testNow); err != nil {
+		t.Fatalf("expected new nonce to be added, got %v", err)
+	}
+	if err := c.Add("n1", testNow.Add(59*time.Second)); !errors.Is(err, ErrReplayedRequest) {
+		t.Fatalf("expected seen nonce to be rejected, got %v", err)
+	}
+}
+
+func TestNonceCacheAcceptsExpiredNonce(t *testing.T) {
+	c := newNonceCache(time.Minute, 4)
+
+	c.Add("n1", testNow)
+	if err := c.Add("n1", testNow.Add(time.Minute)); err != nil {
+		t.Fatalf("expected expired nonce to be added again, got %v", err)
+	}
+	// re-added nonce lives for the whole ttl again
+	if err := c.Add("n1", testNow.Add(90*time.Second)); !errors.Is(err, ErrReplayedRequest) {
+		t.Fatalf("expected re-added nonce to be rejected, got %v", err)
+	}
+	if len(c.queue) != 1 || len(c.seen) != 1 {
+		t.Fatalf("expected only one entry, got queue %d, map %d", len(c.queue), len(c.seen))
+	}
+}
+
+func TestNonceCacheRejectsWhenFull(t *testing.T) {
+	c := newNonceCache(time.Minute, 2)
+
+	c.Add("n1", testNow)
+	c.Add("n2", testNow.Add(time.Second))
+	if err := c.Add("n3", testNow.Add(2*time.Second)); !errors.Is(err, ErrNonceCacheFull) {
+		t.Fatalf("expected full cache to reject new nonce, got %v", err)
+	}
+	if len(c.queue) != 2 || len(c.seen) != 2 {
+		t.Fatalf("expected cache to hold 2 entries, got queue %d, map %d", len(c.queue), len(c.seen))
+	}
+	// live nonces are not evicted, so they can't be replayed
+	if err := c.Add("n1", testNow.Add(3*time.Second)); !errors.Is(err, ErrReplayedRequest) {
+		t.Fatalf("expected live nonce to be kept, got %v", err)
+	}
+
+	// place is freed, when the oldest nonce expires
+	if err := c.Add("n3", testNow.Add(time.Minute)); err != nil {
+		t.Fatalf("expected nonce to be added after expiration, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package httpauth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureVersion is a version of canonical, that SigningRoundTripper signs
+type SignatureVersion int
+
+const (
+	// SignatureV1 canonical covers method, path, timestamp and service name
+	SignatureV1 SignatureVersion = 1
+	// SignatureV2 canonical also covers query, nonce and SHA-256 of the request body
+	// so signed request can't be replayed with other query or body, or replayed at all
+	SignatureV2 SignatureVersion = 2
+)
+
+// SigningRoundTripper is a custom implementation of http.RoundTripper
+// It builds canonical, signs it, and sets headers, so the other service can identify our service
+type SigningRoundTripper struct {
+	base        http.RoundTripper
+	serviceName string
+	signer      Signer
+	clock       func() time.Time
+	version     SignatureVersion
+}
+
+// Signer represents signing algrorithm ( usually Keyring of HMAC secrets )
+// returns id of the key, that signed the data, so the other service can pick the same key
+type Signer interface {
+	Sign(data []byte, now time.Time) (keyID string, signature string, err error)
+}
+
+// NewRendererAuthHTTPRoundTripper creates a new auth http round tripper implementation
+// It will use signer in order to sign all request to renderer service
+// and use clock as part of paylaod ( use time.Now() )
+// version sets canonical version, that will be signed ( SignatureV1 or SignatureV2 )
+func NewAuthHTTPRoundTripper(serviceName string, signer Signer, clock func() time.Time, version SignatureVersion) *SigningRoundTripper {
+	if signer == nil {
+		panic("signer interface can't be nil, in NewAuthHTTPRoundTripper")
+	}
+	if clock == nil {
+		panic("clock function can't be nil, in NewAuthHTTPRoundTripper")
+	}
+	if version != SignatureV1 && version != SignatureV2 {
+		panic("unknown signature version, in NewAuthHTTPRoundTripper")
+	}
+	return &SigningRoundTripper{
+		base:        http.DefaultTransport,
+		serviceName: serviceName,
+		signer:      signer,
+		clock:       clock,
+		version:     version,
+	}
+}
+
+func (rt *SigningRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := rt.clock()
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	// clone, because by convention, only the goroutine that created the request object can change it.
+	// after creation it is kind of immutable
+	r := req.Clone(req.Context())
+
+	var canonical string
+	switch rt.version {
+	case SignatureV2:
+		body, err := readBody(r)
+		if err != nil {
+			return nil, fmt.Errorf("can't read request body to sign it: %w", err)
+		}
+		nonce, err := newNonce()
+		if err != nil {
+			return nil, fmt.Errorf("can't generate nonce: %w", err)
+		}
+		canonical = buildCanonicalV2(r.Method, r.URL.Path, r.URL.RawQuery, ts, rt.serviceName, nonce, body)
+		r.Header.Set("X-Nonce", nonce)
+		r.Header.Set("X-Signature-Version", strconv.Itoa(int(SignatureV2)))
+	default:
+		canonical = buildCanonicalV1(r.Method, r.URL.Path, ts, rt.serviceName)
+	}
+
+	keyID, signature, err := rt.signer.Sign([]byte(canonical), now)
+	if err != nil {
+		return nil, fmt.Errorf("can't sign request: %w", err)
+	}
+
+	r.Header.Set("X-Signature", signature)
+	r.Header.Set("X-Key-Id", keyID)
+	r.Header.Set("X-Timestamp", ts)
+	r.Header.Set("X-Service", rt.serviceName)
+
+	return rt.base.RoundTrip(r)
+}
+
+// readBody reads whole body of the request and replaces it with a fresh reader, so it can be sent after
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// newNonce generates random 128 bit HEX-coded value
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// canonical v1:
+// method[\n]url_path[\n]timestamp[\n]service_name
+func buildCanonicalV1(method, path, timestamp, serviceName string) string {
+	return strings.Join([]string{
+		method,
+		path,
+		timestamp,
+		serviceName,
+	}, "\n")
+}
+
+// canonical v2:
+// v2[\n]method[\n]url_path[\n]raw_query[\n]timestamp[\n]service_name[\n]nonce[\n]hex(sha256(body))
+func buildCanonicalV2(method, path, rawQuery, timestamp, serviceName, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	return strings.Join([]string{
+		"v2",
+		method,
+		path,
+		rawQuery,
+		timestamp,
+		serviceName,
+		nonce,
+		hex.EncodeToString(digest[:]),
+	}, "\n")
+}
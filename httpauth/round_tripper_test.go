@@ -0,0 +1,110 @@
+package httpauth
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func newTestRoundTripper(t *testing.T, version SignatureVersion, base roundTripFunc) *SigningRoundTripper {
+	t.Helper()
+	clock := func() time.Time { return time.Unix(1700000000, 0) }
+	keyring, err := ParseKeyring("old:expired:2020-01-01,new:secret")
+	if err != nil {
+		t.Fatalf("can't create keyring: %v", err)
+	}
+	rt := NewAuthHTTPRoundTripper("api", keyring, clock, version)
+	rt.base = base
+	return rt
+}
+
+func TestRoundTripSignsV1(t *testing.T) {
+	signer := NewHMACSigner([]byte("secret"))
+	rt := newTestRoundTripper(t, SignatureV1, func(r *http.Request) (*http.Response, error) {
+		headers := map[string]string{
+			"X-Timestamp":         "1700000000",
+			"X-Service":           "api",
+			"X-Key-Id":            "new",
+			"X-Nonce":             "",
+			"X-Signature-Version": "",
+			"X-Signature":         signer.Sign([]byte("POST\n/preview\n1700000000\napi")),
+		}
+		for name, want := range headers {
+			if got := r.Header.Get(name); got != want {
+				t.Errorf("expected %s header %q, got %q", name, want, got)
+			}
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "http://renderer/preview", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRoundTripSignsV2WithBody(t *testing.T) {
+	signer := NewHMACSigner([]byte("secret"))
+	body := `{"url_path":"hurtki-dark"}`
+	rt := newTestRoundTripper(t, SignatureV2, func(r *http.Request) (*http.Response, error) {
+		nonce := r.Header.Get("X-Nonce")
+		if len(nonce) != 32 {
+			t.Errorf("expected 32 chars nonce, got %q", nonce)
+		}
+		if got := r.Header.Get("X-Signature-Version"); got != "2" {
+			t.Errorf("expected signature version 2, got %q", got)
+		}
+
+		sentBody, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("can't read sent body: %v", err)
+		}
+		if string(sentBody) != body {
+			t.Errorf("expected body %s to be sent, got %s", body, sentBody)
+		}
+
+		expected := signer.Sign([]byte(buildCanonicalV2(http.MethodPost, "/banners", "force=true", "1700000000", "api", nonce, []byte(body))))
+		if got := r.Header.Get("X-Signature"); got != expected {
+			t.Errorf("expected signature %s, got %s", expected, got)
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "http://storage/banners?force=true", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRoundTripV2UsesNewNonceEveryRequest(t *testing.T) {
+	nonces := map[string]struct{}{}
+	rt := newTestRoundTripper(t, SignatureV2, func(r *http.Request) (*http.Response, error) {
+		nonces[r.Header.Get("X-Nonce")] = struct{}{}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, "http://renderer/preview", nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(nonces) != 3 {
+		t.Fatalf("expected 3 different nonces, got %d", len(nonces))
+	}
+}
@@ -0,0 +1,180 @@
+package httpauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Logger is the part of the service's logger, that verifier uses
+// every service passes its own structured logger
+type Logger interface {
+	Warn(msg string, args ...any)
+}
+
+type VerifierConfig struct {
+	AllowedServices []string
+	MaxClockSkew    time.Duration
+	// AcceptV1 allows requests signed with v1 canonical, that doesn't cover body and nonce
+	// should be turned off, when all the services are signing with v2
+	AcceptV1 bool
+	// NonceCacheSize is max count of remembered nonces of v2 requests
+	NonceCacheSize int
+	// MaxBodySize is max size of v2 request's body, that will be read to check its digest
+	MaxBodySize int64
+}
+
+// Verifier is a server side pair of SigningRoundTripper
+// it rebuilds canonical and checks, that request was signed with the same secret
+type Verifier struct {
+	keyring         *Keyring
+	allowedServices map[string]struct{}
+	maxClockSkew    time.Duration
+	acceptV1        bool
+	maxBodySize     int64
+	nonces          *nonceCache
+	clock           func() time.Time
+	logger          Logger
+}
+
+func NewVerifier(keyring *Keyring, cfg VerifierConfig, clock func() time.Time, logger Logger) *Verifier {
+	if keyring == nil {
+		panic("keyring can't be nil, in NewVerifier")
+	}
+	if clock == nil {
+		panic("clock function can't be nil, in NewVerifier")
+	}
+	if logger == nil {
+		panic("logger can't be nil, in NewVerifier")
+	}
+
+	allowed := make(map[string]struct{}, len(cfg.AllowedServices))
+	for _, s := range cfg.AllowedServices {
+		allowed[s] = struct{}{}
+	}
+
+	return &Verifier{
+		keyring:         keyring,
+		allowedServices: allowed,
+		maxClockSkew:    cfg.MaxClockSkew,
+		acceptV1:        cfg.AcceptV1,
+		maxBodySize:     cfg.MaxBodySize,
+		// request with the same nonce is valid for maxClockSkew in both directions from its timestamp
+		nonces: newNonceCache(2*cfg.MaxClockSkew, cfg.NonceCacheSize),
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Middleware rejects with 401 every request, that wasn't signed by one of allowed services
+// signed request with body larger, than MaxBodySize, is rejected with 413
+// signed request, whose nonce can't be remembered because nonce cache is full, is rejected with 503
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := v.verify(r); err != nil {
+			v.logger.Warn("rejected unauthenticated request", "err", err, "path", r.URL.Path, "service", r.Header.Get("X-Service"))
+			switch {
+			case errors.Is(err, ErrBodyTooLarge):
+				v.error(rw, http.StatusRequestEntityTooLarge, "request body is too large")
+			case errors.Is(err, ErrNonceCacheFull):
+				v.error(rw, http.StatusServiceUnavailable, "too many signed requests, try again later")
+			default:
+				v.error(rw, http.StatusUnauthorized, "unauthorized")
+			}
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (v *Verifier) verify(r *http.Request) error {
+	signature := r.Header.Get("X-Signature")
+	tsHeader := r.Header.Get("X-Timestamp")
+	serviceName := r.Header.Get("X-Service")
+
+	if signature == "" || tsHeader == "" || serviceName == "" {
+		return ErrMissingAuthHeaders
+	}
+
+	if _, ok := v.allowedServices[serviceName]; !ok {
+		return ErrServiceNotAllowed
+	}
+
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	now := v.clock()
+	skew := now.Sub(time.Unix(ts, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > v.maxClockSkew {
+		return ErrStaleTimestamp
+	}
+
+	var canonical, nonce string
+	switch r.Header.Get("X-Signature-Version") {
+	case "", strconv.Itoa(int(SignatureV1)):
+		if !v.acceptV1 {
+			return ErrUnsupportedVersion
+		}
+		canonical = buildCanonicalV1(r.Method, r.URL.Path, tsHeader, serviceName)
+	case strconv.Itoa(int(SignatureV2)):
+		nonce = r.Header.Get("X-Nonce")
+		if nonce == "" {
+			return ErrMissingAuthHeaders
+		}
+		body, err := v.readBody(r)
+		if err != nil {
+			return err
+		}
+		canonical = buildCanonicalV2(r.Method, r.URL.Path, r.URL.RawQuery, tsHeader, serviceName, nonce, body)
+	default:
+		return ErrUnsupportedVersion
+	}
+
+	// key, that expired just now, is still accepted for maxClockSkew
+	// so requests signed right before the rotation are not rejected
+	if !v.keyring.Verify(r.Header.Get("X-Key-Id"), []byte(canonical), signature, now.Add(-v.maxClockSkew)) {
+		return ErrInvalidSignature
+	}
+
+	// nonce is remembered only after signature check
+	// so unsigned requests can't fill up the cache
+	if nonce != "" {
+		return v.nonces.Add(serviceName+":"+nonce, now)
+	}
+	return nil
+}
+
+// readBody reads request's body to count its digest and puts it back for the next handler
+func (v *Verifier) readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, v.maxBodySize+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, ErrUnreadableBody
+	}
+	if int64(len(body)) > v.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (v *Verifier) error(rw http.ResponseWriter, statusCode int, message string) {
+	fn := "httpauth.Verifier.error"
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	if err := json.NewEncoder(rw).Encode(map[string]string{"error": message}); err != nil {
+		v.logger.Warn("can't write error response", "err", err, "source", fn)
+	}
+}
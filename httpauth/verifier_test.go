@@ -0,0 +1,367 @@
+package httpauth
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	testNow    = time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	testSecret = []byte("secret")
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func testConfig() VerifierConfig {
+	return VerifierConfig{
+		AllowedServices: []string{"api"},
+		MaxClockSkew:    time.Minute,
+		AcceptV1:        true,
+		NonceCacheSize:  16,
+		MaxBodySize:     1024,
+	}
+}
+
+func newTestVerifier(t *testing.T, cfg VerifierConfig, keys ...Key) *Verifier {
+	t.Helper()
+	if len(keys) == 0 {
+		keys = []Key{NewKey("k1", testSecret, time.Time{})}
+	}
+	keyring, err := NewKeyring(keys...)
+	if err != nil {
+		t.Fatalf("can't create keyring: %v", err)
+	}
+	return NewVerifier(keyring, cfg, func() time.Time { return testNow }, nopLogger{})
+}
+
+// signV1 sets headers of the request, signed with v1 canonical
+func signV1(r *http.Request, secret []byte, keyID, service string, ts time.Time) {
+	tsHeader := strconv.FormatInt(ts.Unix(), 10)
+	r.Header.Set("X-Signature", NewHMACSigner(secret).Sign([]byte(buildCanonicalV1(r.Method, r.URL.Path, tsHeader, service))))
+	r.Header.Set("X-Timestamp", tsHeader)
+	r.Header.Set("X-Service", service)
+	r.Header.Set("X-Key-Id", keyID)
+	r.Header.Set("X-Signature-Version", "1")
+}
+
+// signV2 sets headers of the request, signed with v2 canonical over given body
+func signV2(r *http.Request, secret []byte, keyID, service, nonce string, ts time.Time, body []byte) {
+	tsHeader := strconv.FormatInt(ts.Unix(), 10)
+	canonical := buildCanonicalV2(r.Method, r.URL.Path, r.URL.RawQuery, tsHeader, service, nonce, body)
+	r.Header.Set("X-Signature", NewHMACSigner(secret).Sign([]byte(canonical)))
+	r.Header.Set("X-Timestamp", tsHeader)
+	r.Header.Set("X-Service", service)
+	r.Header.Set("X-Key-Id", keyID)
+	r.Header.Set("X-Nonce", nonce)
+	r.Header.Set("X-Signature-Version", "2")
+}
+
+// serve passes request through verifier's middleware
+// returns response status and whether the next handler was called
+func serve(v *Verifier, r *http.Request) (int, bool) {
+	called := false
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+		rw.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	v.Middleware(next).ServeHTTP(rec, r)
+	return rec.Code, called
+}
+
+func TestVerifierMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		sign   func(r *http.Request)
+		status int
+	}{
+		{
+			name:   "valid",
+			sign:   func(r *http.Request) { signV1(r, testSecret, "k1", "api", testNow) },
+			status: http.StatusOK,
+		},
+		{
+			name:   "valid within clock skew",
+			sign:   func(r *http.Request) { signV1(r, testSecret, "k1", "api", testNow.Add(-50*time.Second)) },
+			status: http.StatusOK,
+		},
+		{
+			name:   "missing signature",
+			sign:   func(r *http.Request) {},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "blank signature header",
+			sign: func(r *http.Request) {
+				signV1(r, testSecret, "k1", "api", testNow)
+				r.Header.Del("X-Signature")
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong secret",
+			sign:   func(r *http.Request) { signV1(r, []byte("other"), "k1", "api", testNow) },
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "other path",
+			sign: func(r *http.Request) {
+				signV1(r, testSecret, "k1", "api", testNow)
+				r.URL.Path = "/other"
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "stale timestamp",
+			sign:   func(r *http.Request) { signV1(r, testSecret, "k1", "api", testNow.Add(-2*time.Minute)) },
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "timestamp from future",
+			sign:   func(r *http.Request) { signV1(r, testSecret, "k1", "api", testNow.Add(2*time.Minute)) },
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "invalid timestamp",
+			sign: func(r *http.Request) {
+				signV1(r, testSecret, "k1", "api", testNow)
+				r.Header.Set("X-Timestamp", "yesterday")
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "service is not allowed",
+			sign:   func(r *http.Request) { signV1(r, testSecret, "k1", "worker", testNow) },
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(t, testConfig())
+			r := httptest.NewRequest(http.MethodPost, "/banners", nil)
+			tt.sign(r)
+
+			status, called := serve(v, r)
+			if status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+			if called != (tt.status == http.StatusOK) {
+				t.Fatalf("next handler called: %v, with status %d", called, status)
+			}
+		})
+	}
+}
+
+func TestVerifierMiddlewareV2(t *testing.T) {
+	body := []byte(`{"username":"hurtki"}`)
+
+	tests := []struct {
+		name   string
+		cfg    func(cfg *VerifierConfig)
+		req    func() *http.Request
+		status int
+	}{
+		{
+			name: "valid",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				return r
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "tampered body",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader([]byte(`{"username":"other"}`)))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered nonce",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				r.Header.Set("X-Nonce", "n2")
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "valid with query",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodDelete, "/banners/hurtki-dark?kind=long-term", nil)
+				signV2(r, testSecret, "k1", "api", "n1", testNow, nil)
+				return r
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "tampered query",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodDelete, "/banners/hurtki-dark?kind=long-term", nil)
+				signV2(r, testSecret, "k1", "api", "n1", testNow, nil)
+				r.URL.RawQuery = "kind=preview"
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "missing nonce",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				r.Header.Del("X-Nonce")
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "body is too large",
+			cfg:  func(cfg *VerifierConfig) { cfg.MaxBodySize = 8 },
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				return r
+			},
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "v1 when it's not accepted",
+			cfg:  func(cfg *VerifierConfig) { cfg.AcceptV1 = false },
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", nil)
+				signV1(r, testSecret, "k1", "api", testNow)
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "v1 without version header when it's not accepted",
+			cfg:  func(cfg *VerifierConfig) { cfg.AcceptV1 = false },
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", nil)
+				signV1(r, testSecret, "k1", "api", testNow)
+				r.Header.Del("X-Signature-Version")
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "unknown version",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+				signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+				r.Header.Set("X-Signature-Version", "3")
+				return r
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			v := newTestVerifier(t, cfg)
+
+			status, called := serve(v, tt.req())
+			if status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+			if called != (tt.status == http.StatusOK) {
+				t.Fatalf("next handler called: %v, with status %d", called, status)
+			}
+		})
+	}
+}
+
+func TestVerifierKeepsBodyForNextHandler(t *testing.T) {
+	body := []byte(`{"username":"hurtki"}`)
+	v := newTestVerifier(t, testConfig())
+	r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+	signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+
+	var got []byte
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+	})
+	v.Middleware(next).ServeHTTP(httptest.NewRecorder(), r)
+	if !bytes.Equal(got, body) {
+		t.Fatalf("expected next handler to read %q, got %q", body, got)
+	}
+}
+
+func TestVerifierRejectsReplayedNonce(t *testing.T) {
+	body := []byte(`{"username":"hurtki"}`)
+	v := newTestVerifier(t, testConfig())
+
+	request := func(service, nonce string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+		signV2(r, testSecret, "k1", service, nonce, testNow, body)
+		return r
+	}
+
+	if status, _ := serve(v, request("api", "n1")); status != http.StatusOK {
+		t.Fatalf("expected first request to pass, got %d", status)
+	}
+	if status, called := serve(v, request("api", "n1")); status != http.StatusUnauthorized || called {
+		t.Fatalf("expected replayed request to be rejected, got %d", status)
+	}
+	if status, _ := serve(v, request("api", "n2")); status != http.StatusOK {
+		t.Fatalf("expected request with other nonce to pass, got %d", status)
+	}
+}
+
+func TestVerifierDoesntRememberNonceOfInvalidRequest(t *testing.T) {
+	body := []byte(`{"username":"hurtki"}`)
+	v := newTestVerifier(t, testConfig())
+
+	r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+	signV2(r, []byte("other"), "k1", "api", "n1", testNow, body)
+	if status, _ := serve(v, r); status != http.StatusUnauthorized {
+		t.Fatalf("expected forged request to be rejected, got %d", status)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+	signV2(r, testSecret, "k1", "api", "n1", testNow, body)
+	if status, _ := serve(v, r); status != http.StatusOK {
+		t.Fatalf("expected valid request with the same nonce to pass, got %d", status)
+	}
+}
+
+func TestVerifierRejectsWhenNonceCacheIsFull(t *testing.T) {
+	body := []byte(`{"username":"hurtki"}`)
+	cfg := testConfig()
+	cfg.NonceCacheSize = 1
+	v := newTestVerifier(t, cfg)
+
+	request := func(nonce string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/banners", bytes.NewReader(body))
+		signV2(r, testSecret, "k1", "api", nonce, testNow, body)
+		return r
+	}
+
+	if status, _ := serve(v, request("n1")); status != http.StatusOK {
+		t.Fatalf("expected first request to pass, got %d", status)
+	}
+	if status, called := serve(v, request("n2")); status != http.StatusServiceUnavailable || called {
+		t.Fatalf("expected request to be rejected while cache is full, got %d", status)
+	}
+	// n1 wasn't evicted, so its replay is still rejected
+	if status, _ := serve(v, request("n1")); status != http.StatusUnauthorized {
+		t.Fatalf("expected replayed request to be rejected, got %d", status)
+	}
+}
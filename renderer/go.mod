@@ -5,6 +5,9 @@ go 1.25.5
 require (
 	github.com/IBM/sarama v1.46.3
 	github.com/go-chi/chi/v5 v5.2.5
+	github.com/hurtki/github-banners/httpauth v0.0.0
+	golang.org/x/image v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -24,4 +27,7 @@ require (
 	github.com/rcrowley/go-metrics v0.0.0-20250401214520-65e299d6c5c9 // indirect
 	golang.org/x/crypto v0.43.0 // indirect
 	golang.org/x/net v0.46.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
 )
+
+replace github.com/hurtki/github-banners/httpauth => ../httpauth
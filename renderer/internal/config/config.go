@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,8 +11,24 @@ type Config struct {
 	LogLevel  string
 	LogFormat string
 
-	ServiceSecret  string
-	StorageBaseURL string
+	ServiceSecret string
+	// list of named secrets "kid1:secret1,kid2:secret2:2026-12-31", has priority over ServiceSecret
+	ServiceSecretKeys string
+	StorageBaseURL    string
+
+	// services, that are allowed to call renderer's http endpoints
+	AllowedServices []string
+	// max difference between request's X-Timestamp and local time
+	SignatureMaxClockSkew time.Duration
+	// version of canonical, that renderer signs requests to other services with
+	SignatureVersion int
+	// accept requests signed with v1 canonical ( without body digest and nonce )
+	AcceptV1Signatures bool
+	NonceCacheSize     int
+	SignedBodyMaxBytes int64
+
+	// directory with additional themes ( .yaml, .yml, .json ), they override embedded ones with the same name
+	ThemesDir string
 }
 
 func Load() *Config {
@@ -19,8 +36,18 @@ func Load() *Config {
 		LogLevel:  getEnv("LOG_LEVEL", "info"),
 		LogFormat: getEnv("LOG_FORMAT", "json"),
 
-		ServiceSecret:  getEnv("SERVICES_SECRET_KEY", "1234"),
-		StorageBaseURL: getEnv("STORAGE_BASE_URL", "http://localhost:8081"),
+		ServiceSecret:     getEnv("SERVICES_SECRET_KEY", "1234"),
+		ServiceSecretKeys: getEnv("SERVICES_SECRET_KEYS", ""),
+		StorageBaseURL:    getEnv("STORAGE_BASE_URL", "http://localhost:8081"),
+
+		AllowedServices:       getEnvAsList("ALLOWED_SERVICES", "api"),
+		SignatureMaxClockSkew: getEnvAsDuration("SIGNATURE_MAX_CLOCK_SKEW", 30*time.Second),
+		SignatureVersion:      getEnvAsInt("SIGNATURE_VERSION", 2),
+		AcceptV1Signatures:    getEnvAsBool("ACCEPT_V1_SIGNATURES", true),
+		NonceCacheSize:        getEnvAsInt("NONCE_CACHE_SIZE", 100_000),
+		SignedBodyMaxBytes:    int64(getEnvAsInt("SIGNED_BODY_MAX_BYTES", 1<<20)),
+
+		ThemesDir: getEnv("THEMES_DIR", ""),
 	}
 }
 
@@ -41,6 +68,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
 		if dur, err := time.ParseDuration(value); err == nil {
@@ -49,3 +85,15 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsList(key string, defaultValue string) []string {
+	items := strings.Split(getEnv(key, defaultValue), ",")
+	res := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
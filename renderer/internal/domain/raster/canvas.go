@@ -0,0 +1,170 @@
+package raster
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// canvas is an RGBA image with primitives, that are needed to draw banners
+type canvas struct {
+	img *image.RGBA
+	z   *vector.Rasterizer
+}
+
+func newCanvas(w, h int) *canvas {
+	return &canvas{
+		img: image.NewRGBA(image.Rect(0, 0, w, h)),
+		z:   vector.NewRasterizer(w, h),
+	}
+}
+
+// fill draws src through the path, that is currently built in rasterizer, and resets the path
+func (c *canvas) fill(src image.Image) {
+	c.z.DrawOp = draw.Over
+	c.z.Draw(c.img, c.img.Bounds(), src, image.Point{})
+	c.z.Reset(c.img.Bounds().Dx(), c.img.Bounds().Dy())
+}
+
+// mask returns alpha mask of the path, that is currently built in rasterizer, and resets the path
+func (c *canvas) mask() *image.Alpha {
+	m := image.NewAlpha(c.img.Bounds())
+	c.z.DrawOp = draw.Src
+	c.z.Draw(m, m.Bounds(), image.Opaque, image.Point{})
+	c.z.Reset(c.img.Bounds().Dx(), c.img.Bounds().Dy())
+	return m
+}
+
+func (c *canvas) roundRectPath(x, y, w, h, r float32) {
+	r = min(r, w/2, h/2)
+	c.z.MoveTo(x+r, y)
+	c.z.LineTo(x+w-r, y)
+	c.z.QuadTo(x+w, y, x+w, y+r)
+	c.z.LineTo(x+w, y+h-r)
+	c.z.QuadTo(x+w, y+h, x+w-r, y+h)
+	c.z.LineTo(x+r, y+h)
+	c.z.QuadTo(x, y+h, x, y+h-r)
+	c.z.LineTo(x, y+r)
+	c.z.QuadTo(x, y, x+r, y)
+	c.z.ClosePath()
+}
+
+func (c *canvas) fillRoundRect(x, y, w, h, r float32, src image.Image) {
+	c.roundRectPath(x, y, w, h, r)
+	c.fill(src)
+}
+
+// strokeRoundRect draws rounded rect's border of given width
+func (c *canvas) strokeRoundRect(x, y, w, h, r, width float32, col color.Color) {
+	outer := c.roundRectMask(x, y, w, h, r)
+	inner := c.roundRectMask(x+width, y+width, w-2*width, h-2*width, max(r-width, 0))
+	for i := range outer.Pix {
+		outer.Pix[i] = uint8(max(int(outer.Pix[i])-int(inner.Pix[i]), 0))
+	}
+	draw.DrawMask(c.img, c.img.Bounds(), image.NewUniform(col), image.Point{}, outer, image.Point{}, draw.Over)
+}
+
+func (c *canvas) roundRectMask(x, y, w, h, r float32) *image.Alpha {
+	c.roundRectPath(x, y, w, h, r)
+	return c.mask()
+}
+
+// fillRectMasked fills rect, drawing only inside of the mask
+func (c *canvas) fillRectMasked(rect image.Rectangle, col color.Color, m image.Image) {
+	draw.DrawMask(c.img, rect, image.NewUniform(col), image.Point{}, m, rect.Min, draw.Over)
+}
+
+// fillRingSegment fills part of the ring between radii r0 and r1
+// start and sweep are parts of the full circle, starting from the top clockwise
+func (c *canvas) fillRingSegment(cx, cy, r0, r1 float32, start, sweep float64, col color.Color) {
+	if sweep <= 0 {
+		return
+	}
+	steps := max(int(sweep*128), 2)
+	angle := func(i int) float64 {
+		return 2*math.Pi*(start+sweep*float64(i)/float64(steps)) - math.Pi/2
+	}
+	point := func(r float32, a float64) (float32, float32) {
+		return cx + r*float32(math.Cos(a)), cy + r*float32(math.Sin(a))
+	}
+
+	c.z.MoveTo(point(r1, angle(0)))
+	for i := 1; i <= steps; i++ {
+		c.z.LineTo(point(r1, angle(i)))
+	}
+	for i := steps; i >= 0; i-- {
+		c.z.LineTo(point(r0, angle(i)))
+	}
+	c.z.ClosePath()
+	c.fill(image.NewUniform(col))
+}
+
+type textAnchor int
+
+const (
+	anchorStart textAnchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// text draws string with baseline at y, as svg's text element does
+func (c *canvas) text(face font.Face, x, y int, s string, col color.Color, anchor textAnchor) {
+	d := font.Drawer{Dst: c.img, Src: image.NewUniform(col), Face: face}
+	width := d.MeasureString(s).Round()
+	switch anchor {
+	case anchorMiddle:
+		x -= width / 2
+	case anchorEnd:
+		x -= width
+	}
+	d.Dot = fixed.P(x, y)
+	d.DrawString(s)
+}
+
+// diagonalGradient is a linear gradient from the top left to the bottom right corner
+// the same as svg's linearGradient with x1=0% y1=0% x2=100% y2=100%
+type diagonalGradient struct {
+	bounds image.Rectangle
+	stops  []gradientStop
+}
+
+type gradientStop struct {
+	offset float64
+	color  color.NRGBA
+}
+
+func (g *diagonalGradient) ColorModel() color.Model { return color.NRGBAModel }
+func (g *diagonalGradient) Bounds() image.Rectangle { return g.bounds }
+
+func (g *diagonalGradient) At(x, y int) color.Color {
+	if len(g.stops) == 0 {
+		return color.Transparent
+	}
+	t := (float64(x)/float64(g.bounds.Dx()) + float64(y)/float64(g.bounds.Dy())) / 2
+
+	if t <= g.stops[0].offset {
+		return g.stops[0].color
+	}
+	for i := 1; i < len(g.stops); i++ {
+		a, b := g.stops[i-1], g.stops[i]
+		if t <= b.offset {
+			k := (t - a.offset) / (b.offset - a.offset)
+			return color.NRGBA{
+				R: lerp(a.color.R, b.color.R, k),
+				G: lerp(a.color.G, b.color.G, k),
+				B: lerp(a.color.B, b.color.B, k),
+				A: lerp(a.color.A, b.color.A, k),
+			}
+		}
+	}
+	return g.stops[len(g.stops)-1].color
+}
+
+func lerp(a, b uint8, k float64) uint8 {
+	return uint8(math.Round(float64(a) + (float64(b)-float64(a))*k))
+}
@@ -0,0 +1,125 @@
+package raster
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseColor parses css colors, that are allowed in themes and used for languages:
+// #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba(), hsl() and hsla()
+func parseColor(s string) (color.NRGBA, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return parseHexColor(s[1:])
+	case strings.HasPrefix(s, "rgb"):
+		args, err := colorArgs(s)
+		if err != nil || len(args) < 3 {
+			return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+		}
+		return color.NRGBA{
+			R: channel(args[0], 255),
+			G: channel(args[1], 255),
+			B: channel(args[2], 255),
+			A: alpha(args),
+		}, nil
+	case strings.HasPrefix(s, "hsl"):
+		args, err := colorArgs(s)
+		if err != nil || len(args) < 3 {
+			return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+		}
+		r, g, b := hslToRGB(parseNumber(args[0], 360)/360, parseNumber(args[1], 100)/100, parseNumber(args[2], 100)/100)
+		return color.NRGBA{R: r, G: g, B: b, A: alpha(args)}, nil
+	}
+	return color.NRGBA{}, fmt.Errorf("unsupported color %q", s)
+}
+
+func parseHexColor(hex string) (color.NRGBA, error) {
+	// short forms are expanded: "f0a" -> "ff00aa"
+	if len(hex) == 3 || len(hex) == 4 {
+		var b strings.Builder
+		for _, c := range hex {
+			b.WriteRune(c)
+			b.WriteRune(c)
+		}
+		hex = b.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// colorArgs returns arguments of functional notation: "rgba(1, 2, 3, 0.5)" -> ["1", "2", "3", "0.5"]
+func colorArgs(s string) ([]string, error) {
+	open, end := strings.Index(s, "("), strings.LastIndex(s, ")")
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	args := strings.Split(s[open+1:end], ",")
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+	return args, nil
+}
+
+// parseNumber parses number or percent, percent is converted to the scale
+func parseNumber(v string, scale float64) float64 {
+	if p, ok := strings.CutSuffix(v, "%"); ok {
+		f, _ := strconv.ParseFloat(p, 64)
+		return f / 100 * scale
+	}
+	f, _ := strconv.ParseFloat(v, 64)
+	return f
+}
+
+func channel(v string, scale float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(255, parseNumber(v, scale)))))
+}
+
+func alpha(args []string) uint8 {
+	if len(args) < 4 {
+		return 255
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, parseNumber(args[3], 1))) * 255))
+}
+
+func hslToRGB(h, s, l float64) (uint8, uint8, uint8) {
+	h = h - math.Floor(h)
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h*6, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch int(h * 6) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return uint8(math.Round((r + m) * 255)), uint8(math.Round((g + m) * 255)), uint8(math.Round((b + m) * 255))
+}
+
+// withOpacity multiplies color's alpha by opacity
+func withOpacity(c color.NRGBA, opacity float64) color.NRGBA {
+	c.A = uint8(math.Round(float64(c.A) * opacity))
+	return c
+}
@@ -0,0 +1,95 @@
+package raster
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want color.NRGBA
+	}{
+		{"#0d1117", color.NRGBA{R: 13, G: 17, B: 23, A: 255}},
+		{"#00FFB4", color.NRGBA{R: 0, G: 255, B: 180, A: 255}},
+		{"#f0a", color.NRGBA{R: 255, G: 0, B: 170, A: 255}},
+		{"#f0a8", color.NRGBA{R: 255, G: 0, B: 170, A: 136}},
+		{"#00ffb480", color.NRGBA{R: 0, G: 255, B: 180, A: 128}},
+		{"  #ffffff ", color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"rgb(1, 2, 3)", color.NRGBA{R: 1, G: 2, B: 3, A: 255}},
+		{"rgb(100%, 0%, 50%)", color.NRGBA{R: 255, G: 0, B: 128, A: 255}},
+		{"rgb(300, -5, 0)", color.NRGBA{R: 255, G: 0, B: 0, A: 255}},
+		{"rgba(255, 0, 0, 0.5)", color.NRGBA{R: 255, G: 0, B: 0, A: 128}},
+		{"rgba(255, 0, 0, 50%)", color.NRGBA{R: 255, G: 0, B: 0, A: 128}},
+		{"hsl(120, 100%, 50%)", color.NRGBA{R: 0, G: 255, B: 0, A: 255}},
+		{"hsl(0, 0%, 100%)", color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"hsl(480, 100%, 50%)", color.NRGBA{R: 0, G: 255, B: 0, A: 255}},
+		{"hsla(240, 100%, 50%, 0.25)", color.NRGBA{R: 0, G: 0, B: 255, A: 64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseColor(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseColorInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"#",
+		"#12345",
+		"#1234567",
+		"#ggg",
+		"#00ffbz",
+		"rgb(1, 2)",
+		"rgb 1, 2, 3",
+		"hsl(120, 100%)",
+		// named colors aren't allowed in themes, so they aren't supported
+		"red",
+		"transparent",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got, err := parseColor(in); err == nil {
+				t.Fatalf("expected error, got %v", got)
+			}
+		})
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want color.NRGBA
+		ok   bool
+	}{
+		{"abc", color.NRGBA{R: 170, G: 187, B: 204, A: 255}, true},
+		{"abcd", color.NRGBA{R: 170, G: 187, B: 204, A: 221}, true},
+		{"aabbcc", color.NRGBA{R: 170, G: 187, B: 204, A: 255}, true},
+		{"aabbccdd", color.NRGBA{R: 170, G: 187, B: 204, A: 221}, true},
+		{"", color.NRGBA{}, false},
+		{"ab", color.NRGBA{}, false},
+		{"-abcde", color.NRGBA{}, false},
+		{"xyzxyz", color.NRGBA{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseHexColor(tt.in)
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected ok %v, got error %v", tt.ok, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
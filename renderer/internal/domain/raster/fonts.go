@@ -0,0 +1,68 @@
+package raster
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+)
+
+// fonts are monospace fonts, that replace theme's font family in png
+// theme's fonts are css font stacks, that can't be resolved without a browser
+type fonts struct {
+	regular *opentype.Font
+	bold    *opentype.Font
+}
+
+func loadFonts() (*fonts, error) {
+	regular, err := opentype.Parse(gomono.TTF)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := opentype.Parse(gomonobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return &fonts{regular: regular, bold: bold}, nil
+}
+
+type faceKey struct {
+	bold bool
+	size float64
+}
+
+// faces caches font faces of one render
+// faces aren't safe for concurrent use, so they are not shared between renders
+type faces struct {
+	fonts *fonts
+	cache map[faceKey]font.Face
+}
+
+func (f *fonts) newFaces() *faces {
+	return &faces{fonts: f, cache: map[faceKey]font.Face{}}
+}
+
+// get returns face of given size, size is in pixels as font-size in svg templates
+func (f *faces) get(size float64, bold bool) (font.Face, error) {
+	key := faceKey{bold: bold, size: size}
+	if face, ok := f.cache[key]; ok {
+		return face, nil
+	}
+
+	src := f.fonts.regular
+	if bold {
+		src = f.fonts.bold
+	}
+	face, err := opentype.NewFace(src, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	f.cache[key] = face
+	return face, nil
+}
+
+func (f *faces) close() {
+	for _, face := range f.cache {
+		face.Close()
+	}
+}
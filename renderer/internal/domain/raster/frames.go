@@ -0,0 +1,115 @@
+package raster
+
+import "github.com/hurtki/github-banners/renderer/internal/domain"
+
+// frame is a static part of layout's template: positions and sizes, that aren't in the view
+// values are taken from svg templates, so png looks like the first frame of svg
+type frame struct {
+	radius float32
+	// accent is a small vertical bar left of the title, zero width means no bar
+	accent   rect
+	title    textSpec
+	subtitle textSpec
+	// bar is a languages bar, zero height means layout has no bar
+	bar       rect
+	stat      statSpec
+	legendDot int
+	legend    float64
+	// time is position of fetch time, zero size means layout doesn't show it
+	time  textSpec
+	donut donutSpec
+}
+
+type rect struct {
+	x, y, w, h float32
+}
+
+type textSpec struct {
+	x, y int
+	size float64
+}
+
+// statSpec describes how StatItem is drawn, offsets are from item's X and Y
+type statSpec struct {
+	box       bool
+	labelDX   int
+	labelDY   int
+	labelSize float64
+	valueDX   int
+	valueDY   int
+	valueSize float64
+}
+
+type donutSpec struct {
+	cx, cy float32
+	stroke float32
+}
+
+var cardFrame = frame{
+	radius:    14,
+	accent:    rect{x: 20, y: 14, w: 2, h: 26},
+	title:     textSpec{x: 28, y: 29, size: 18},
+	subtitle:  textSpec{x: 28, y: 44, size: 8},
+	stat:      statSpec{box: true, labelDX: 8, labelDY: 14, labelSize: 7, valueDX: 8, valueDY: 36, valueSize: 18},
+	legendDot: 8,
+	legend:    8,
+	time:      textSpec{x: 340, y: 182, size: 7},
+}
+
+var frames = map[domain.BannerLayout]frame{
+	domain.LayoutDefault: {
+		radius:    14,
+		accent:    rect{x: 20, y: 14, w: 2, h: 26},
+		title:     textSpec{x: 28, y: 29, size: 18},
+		subtitle:  textSpec{x: 32, y: 44, size: 8},
+		bar:       rect{x: 20, y: 134, h: 10},
+		stat:      statSpec{box: true, labelDX: 8, labelDY: 14, labelSize: 8, valueDX: 8, valueDY: 34, valueSize: 20},
+		legendDot: 8,
+		legend:    8,
+		time:      textSpec{x: 440, y: 208, size: 7},
+	},
+	domain.LayoutCompact: {
+		radius:    10,
+		accent:    rect{x: 14, y: 12, w: 2, h: 20},
+		title:     textSpec{x: 22, y: 24, size: 13},
+		subtitle:  textSpec{x: 22, y: 35, size: 7},
+		bar:       rect{x: 14, y: 46, h: 6},
+		stat:      statSpec{labelDY: 9, labelSize: 6, valueSize: 13},
+		legendDot: 8,
+		legend:    8,
+	},
+	domain.LayoutWide: {
+		radius:    14,
+		accent:    rect{x: 24, y: 24, w: 2, h: 40},
+		title:     textSpec{x: 34, y: 46, size: 24},
+		subtitle:  textSpec{x: 34, y: 62, size: 9},
+		bar:       rect{x: 24, y: 104, h: 10},
+		stat:      statSpec{box: true, labelDX: 8, labelDY: 14, labelSize: 8, valueDX: 8, valueDY: 38, valueSize: 20},
+		legendDot: 8,
+		legend:    8,
+		time:      textSpec{x: 776, y: 96, size: 7},
+	},
+	domain.LayoutCard: cardFrame,
+	// activity is drawn with card's template
+	domain.LayoutActivity: cardFrame,
+	domain.LayoutRepository: {
+		radius:    14,
+		accent:    rect{x: 20, y: 14, w: 2, h: 26},
+		title:     textSpec{x: 28, y: 29, size: 18},
+		subtitle:  textSpec{x: 28, y: 44, size: 8},
+		bar:       rect{x: 20, y: 134, h: 10},
+		stat:      statSpec{box: true, labelDX: 8, labelDY: 14, labelSize: 7, valueDX: 8, valueDY: 36, valueSize: 18},
+		legendDot: 8,
+		legend:    8,
+		time:      textSpec{x: 440, y: 208, size: 7},
+	},
+	domain.LayoutLanguages: {
+		radius:    14,
+		title:     textSpec{x: 20, y: 28, size: 14},
+		subtitle:  textSpec{x: 20, y: 42, size: 7},
+		legendDot: 9,
+		legend:    9,
+		time:      textSpec{x: 320, y: 190, size: 7},
+		donut:     donutSpec{cx: 95, cy: 118, stroke: 18},
+	},
+}
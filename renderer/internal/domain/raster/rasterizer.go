@@ -0,0 +1,189 @@
+package raster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+	"github.com/hurtki/github-banners/renderer/internal/domain/render"
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+)
+
+// Rasterizer draws banner views straight to png
+// it doesn't rasterize svg templates, animations and filters can't be shown in png anyway
+// so it draws the static part of the same layout with pure go primitives
+type Rasterizer struct {
+	fonts *fonts
+}
+
+func NewRasterizer() (*Rasterizer, error) {
+	f, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+	return &Rasterizer{fonts: f}, nil
+}
+
+func (r *Rasterizer) RenderPNG(view *layout.BannerView) ([]byte, error) {
+	fr, ok := frames[domain.BannerLayout(view.Layout)]
+	if !ok {
+		fr = frames[domain.LayoutDefault]
+	}
+
+	faces := r.fonts.newFaces()
+	defer faces.close()
+
+	p := &painter{
+		c:     newCanvas(view.Width, view.Height),
+		faces: faces,
+		view:  view,
+		frame: fr,
+	}
+	p.paint()
+	if p.err != nil {
+		return nil, render.ErrRenderFailure
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, p.c.img); err != nil {
+		return nil, render.ErrRenderFailure
+	}
+	return buf.Bytes(), nil
+}
+
+// painter draws one view, the first error stops drawing and is kept in err
+type painter struct {
+	c     *canvas
+	faces *faces
+	view  *layout.BannerView
+	frame frame
+	err   error
+}
+
+func (p *painter) paint() {
+	p.background()
+	p.header()
+	p.stats()
+	p.languagesBar()
+	p.donut()
+	p.legend()
+	p.time()
+}
+
+func (p *painter) background() {
+	theme := p.view.Theme
+	g := &diagonalGradient{bounds: p.c.img.Bounds()}
+	for _, s := range theme.GradientStops {
+		g.stops = append(g.stops, gradientStop{offset: float64(s.Offset) / 100, color: p.color(s.Color)})
+	}
+	if len(g.stops) == 0 {
+		g.stops = []gradientStop{{color: p.color(theme.Background)}}
+	}
+	w, h := float32(p.view.Width), float32(p.view.Height)
+	p.c.fillRoundRect(0, 0, w, h, p.frame.radius, g)
+	p.c.strokeRoundRect(0, 0, w, h, p.frame.radius, 0.5, withOpacity(p.color(theme.Accent), 0.3))
+}
+
+func (p *painter) header() {
+	theme := p.view.Theme
+	if a := p.frame.accent; a.w > 0 {
+		p.c.fillRoundRect(a.x, a.y, a.w, a.h, 1, image.NewUniform(p.color(theme.Accent)))
+	}
+
+	p.text(p.frame.title, p.view.Username, true, p.color(theme.Foreground), anchorStart)
+
+	subtitle := p.view.BannerType
+	if p.view.Subtitle != "" {
+		subtitle = p.view.Subtitle
+	} else if p.frame.donut.stroke > 0 {
+		subtitle = "TOP LANGUAGES"
+	}
+	p.text(p.frame.subtitle, subtitle, false, withOpacity(p.color(theme.Accent), 0.8), anchorStart)
+}
+
+func (p *painter) stats() {
+	spec := p.frame.stat
+	fg := p.color(p.view.Theme.Foreground)
+	for _, item := range p.view.StatItems {
+		col := p.color(item.Color)
+		if spec.box {
+			x, y, w, h := float32(item.X), float32(item.Y), float32(item.Width), float32(item.Height)
+			p.c.fillRoundRect(x, y, w, h, 4, image.NewUniform(withOpacity(col, 0.04)))
+			p.c.strokeRoundRect(x, y, w, h, 4, 0.5, withOpacity(col, 0.3))
+		}
+		p.text(textSpec{x: item.X + spec.labelDX, y: item.Y + spec.labelDY, size: spec.labelSize}, item.Label, false, withOpacity(col, 0.6), anchorStart)
+		p.text(textSpec{x: item.X + spec.valueDX, y: item.Y + spec.valueDY, size: spec.valueSize}, strconv.Itoa(item.Value), true, fg, anchorStart)
+	}
+}
+
+func (p *painter) languagesBar() {
+	bar := p.frame.bar
+	if bar.h == 0 || p.view.BarWidth == 0 {
+		return
+	}
+	bar.w = float32(p.view.BarWidth)
+
+	p.c.fillRoundRect(bar.x, bar.y, bar.w, bar.h, bar.h/2, image.NewUniform(withOpacity(p.color(p.view.Theme.Muted), 0.15)))
+	mask := p.c.roundRectMask(bar.x, bar.y, bar.w, bar.h, bar.h/2)
+	for _, l := range p.view.Languages {
+		r := image.Rect(l.X, int(bar.y), l.X+l.Width, int(bar.y+bar.h))
+		p.c.fillRectMasked(r, p.color(l.Color), mask)
+	}
+}
+
+func (p *painter) donut() {
+	d := p.frame.donut
+	if d.stroke == 0 {
+		return
+	}
+	r := float32(p.view.DonutRadius)
+	r0, r1 := r-d.stroke/2, r+d.stroke/2
+
+	p.c.fillRingSegment(d.cx, d.cy, r0, r1, 0, 1, withOpacity(p.color(p.view.Theme.Muted), 0.15))
+	for _, s := range p.view.Donut {
+		p.c.fillRingSegment(d.cx, d.cy, r0, r1, s.Start, s.Sweep, p.color(s.Color))
+	}
+
+	count := strconv.Itoa(len(p.view.Stats.Languages))
+	p.text(textSpec{x: int(d.cx), y: int(d.cy) + 4, size: 12}, count, true, p.color(p.view.Theme.Foreground), anchorMiddle)
+}
+
+func (p *painter) legend() {
+	size := float32(p.frame.legendDot)
+	fg := withOpacity(p.color(p.view.Theme.Foreground), 0.85)
+	for _, item := range p.view.Legend {
+		p.c.fillRoundRect(float32(item.DotX), float32(item.DotY), size, size, 2, image.NewUniform(withOpacity(p.color(item.Color), 0.9)))
+		p.text(textSpec{x: item.TextX, y: item.TextY, size: p.frame.legend}, item.Label, false, fg, anchorStart)
+	}
+}
+
+func (p *painter) time() {
+	if p.frame.time.size == 0 {
+		return
+	}
+	p.text(p.frame.time, p.view.FormattedTime, false, withOpacity(p.color(p.view.Theme.Accent), 0.35), anchorEnd)
+}
+
+func (p *painter) text(spec textSpec, s string, bold bool, col color.Color, anchor textAnchor) {
+	if p.err != nil || s == "" {
+		return
+	}
+	face, err := p.faces.get(spec.size, bold)
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.c.text(face, spec.x, spec.y, s, col, anchor)
+}
+
+// color parses color and remembers the error, transparent color is returned in that case
+func (p *painter) color(s string) color.NRGBA {
+	c, err := parseColor(s)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return c
+}
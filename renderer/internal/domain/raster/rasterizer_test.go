@@ -0,0 +1,102 @@
+package raster_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+	"github.com/hurtki/github-banners/renderer/internal/domain/raster"
+	"github.com/hurtki/github-banners/renderer/internal/domain/themes"
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+)
+
+func bannerInfo(username string, kind domain.BannerKind, l domain.BannerLayout) domain.BannerInfo {
+	license, release := "MIT", "v1.2.0"
+	pushed := time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)
+	return domain.BannerInfo{
+		Username: username,
+		Kind:     kind,
+		Layout:   l,
+		Stats: domain.GithubUserStats{
+			TotalRepos:    42,
+			OriginalRepos: 32,
+			ForkedRepos:   10,
+			TotalStars:    1234,
+			TotalForks:    56,
+			Languages:     map[string]int{"Go": 12, "Python": 5, "TypeScript": 3},
+			FetchedAt:     time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC),
+			Members:       17,
+			Contributions: &domain.GithubContributions{Total: 842, Commits: 610, PullRequests: 45, Issues: 12, Reviews: 30, CurrentStreak: 7, LongestStreak: 23},
+			Repository:    &domain.GithubRepoStats{OpenIssues: 7, License: &license, LatestRelease: &release, PushedAt: &pushed},
+		},
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	rasterizer, err := raster.NewRasterizer()
+	if err != nil {
+		t.Fatalf("can't create rasterizer: %v", err)
+	}
+	registry, err := themes.NewRegistry("")
+	if err != nil {
+		t.Fatalf("can't load themes: %v", err)
+	}
+
+	layouts := []struct {
+		info          domain.BannerInfo
+		width, height int
+	}{
+		{bannerInfo("hurtki", domain.KindUser, domain.LayoutDefault), 460, 215},
+		{bannerInfo("hurtki", domain.KindUser, domain.LayoutCompact), 320, 64},
+		{bannerInfo("hurtki", domain.KindUser, domain.LayoutWide), 800, 160},
+		{bannerInfo("gophers", domain.KindOrg, domain.LayoutCard), 360, 190},
+		{bannerInfo("hurtki", domain.KindUser, domain.LayoutLanguages), 340, 200},
+		{bannerInfo("hurtki", domain.KindUser, domain.LayoutActivity), 360, 190},
+		{bannerInfo("hurtki/github-banners", domain.KindRepository, domain.LayoutRepository), 460, 215},
+	}
+
+	for _, theme := range registry.List() {
+		for _, l := range layouts {
+			t.Run(theme.Name+"-"+string(l.info.Layout), func(t *testing.T) {
+				info := l.info
+				info.BannerType = domain.BannerType(theme.Name)
+
+				got, err := rasterizer.RenderPNG(layout.BuildView(info, theme))
+				if err != nil {
+					t.Fatalf("can't render png: %v", err)
+				}
+				img, err := png.Decode(bytes.NewReader(got))
+				if err != nil {
+					t.Fatalf("rendered banner isn't valid png: %v", err)
+				}
+				if want := image.Rect(0, 0, l.width, l.height); img.Bounds() != want {
+					t.Fatalf("expected bounds %v, got %v", want, img.Bounds())
+				}
+				// background is drawn under the whole banner, except rounded corners
+				if _, _, _, a := img.At(l.width/2, l.height/2).RGBA(); a == 0 {
+					t.Fatal("expected center of the banner to be painted")
+				}
+			})
+		}
+	}
+}
+
+func TestRenderPNGInvalidColor(t *testing.T) {
+	rasterizer, err := raster.NewRasterizer()
+	if err != nil {
+		t.Fatalf("can't create rasterizer: %v", err)
+	}
+	registry, err := themes.NewRegistry("")
+	if err != nil {
+		t.Fatalf("can't load themes: %v", err)
+	}
+	theme := registry.List()[0]
+	theme.Accent = "red"
+
+	if _, err := rasterizer.RenderPNG(layout.BuildView(bannerInfo("hurtki", domain.KindUser, domain.LayoutDefault), theme)); err == nil {
+		t.Fatal("expected error for unsupported color")
+	}
+}
@@ -3,14 +3,28 @@ package render
 import "github.com/hurtki/github-banners/renderer/internal/domain"
 
 type UpdateBannerIn struct {
-	Username   string
+	Username string
+	// Kind is optional, banner is built for user, if it's blank
+	Kind       string
 	BannerType string
-	URLPath    string
-	Stats      domain.GithubUserStats
+	// Layout is optional, default layout is used, if it's blank
+	Layout string
+	// Motion is optional, banner is animated, if it's blank
+	Motion  string
+	URLPath string
+	Stats   domain.GithubUserStats
 }
 
 type RenderIn struct {
-	Username   string
+	Username string
+	// Kind is optional, banner is built for user, if it's blank
+	Kind       string
 	BannerType string
-	Stats      domain.GithubUserStats
+	// Layout is optional, default layout is used, if it's blank
+	Layout string
+	// Motion is optional, banner is animated, if it's blank
+	Motion string
+	// Format is optional, svg is rendered, if it's blank
+	Format string
+	Stats  domain.GithubUserStats
 }
@@ -5,7 +5,11 @@ import "errors"
 var (
 	ErrInvalidUsername   = errors.New("invalid username: cannot be empty")
 	ErrInvalidUrlPath    = errors.New("invalid url path: cannot be empty")
+	ErrInvalidKind       = errors.New("invalid kind: should be user or org")
 	ErrInvalidBannerType = errors.New("invalid banner type: template not supported")
+	ErrInvalidLayout     = errors.New("invalid layout: layout not supported")
+	ErrInvalidMotion     = errors.New("invalid motion: should be on or off")
+	ErrInvalidFormat     = errors.New("invalid format: format not supported")
 	ErrRenderFailure     = errors.New("render failure: unable to generate banner")
 	ErrStorageFailure    = errors.New("storage failure: unable to save banner")
 )
@@ -8,108 +8,209 @@ import (
 )
 
 type BannerStorage interface {
-	SaveBanner(ctx context.Context, bannerID string, svg string) (string, error)
+	SaveBanner(ctx context.Context, bannerID string, data []byte, format domain.BannerFormat) (string, error)
 }
 
 type BannerRenderer interface {
 	RenderBanner(view *layout.BannerView) ([]byte, error)
 }
 
+// BannerRasterizer draws static png of the banner
+type BannerRasterizer interface {
+	RenderPNG(view *layout.BannerView) ([]byte, error)
+}
+
+// ThemeRegistry provides themes, that banners are rendered with
+// banner type is a name of the theme
+type ThemeRegistry interface {
+	Get(name string) (layout.Theme, bool)
+}
+
 type Usecase struct {
-	renderer BannerRenderer
-	storage  BannerStorage
+	renderer   BannerRenderer
+	rasterizer BannerRasterizer
+	storage    BannerStorage
+	themes     ThemeRegistry
 }
 
-func NewUsecase(r BannerRenderer, s BannerStorage) *Usecase {
+func NewUsecase(r BannerRenderer, rasterizer BannerRasterizer, s BannerStorage, themes ThemeRegistry) *Usecase {
 	return &Usecase{
-		renderer: r,
-		storage:  s,
+		renderer:   r,
+		rasterizer: rasterizer,
+		storage:    s,
+		themes:     themes,
 	}
 }
 
 func (u *Usecase) ProcessBanner(ctx context.Context, req UpdateBannerIn) error {
-	ltInfo, err := u.validateUpdateBannerIn(req)
+	ltInfo, theme, err := u.validateUpdateBannerIn(req)
 	if err != nil {
 		return err
 	}
 
-	view := layout.BuildView(ltInfo.BannerInfo)
+	view := layout.BuildView(ltInfo.BannerInfo, theme)
 
-	renderedData, err := u.renderer.RenderBanner(view)
-	if err != nil {
-		return err
-	}
+	// long-term banner is stored in both formats
+	// so the same url path can be embedded as png, where svg isn't shown
+	for _, format := range []domain.BannerFormat{domain.FormatSVG, domain.FormatPNG} {
+		renderedData, err := u.renderView(view, format)
+		if err != nil {
+			return err
+		}
 
-	_, err = u.storage.SaveBanner(ctx, ltInfo.URLPath, string(renderedData))
-	if err != nil {
-		return err
+		_, err = u.storage.SaveBanner(ctx, ltInfo.URLPath, renderedData, format)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (u *Usecase) validateUpdateBannerIn(req UpdateBannerIn) (domain.LTBannerInfo, error) {
+func (u *Usecase) renderView(view *layout.BannerView, format domain.BannerFormat) ([]byte, error) {
+	if format == domain.FormatPNG {
+		return u.rasterizer.RenderPNG(view)
+	}
+	return u.renderer.RenderBanner(view)
+}
+
+func (u *Usecase) validateUpdateBannerIn(req UpdateBannerIn) (domain.LTBannerInfo, layout.Theme, error) {
 	if req.Username == "" {
-		return domain.LTBannerInfo{}, ErrInvalidUsername
+		return domain.LTBannerInfo{}, layout.Theme{}, ErrInvalidUsername
 	}
 
 	if req.URLPath == "" {
-		return domain.LTBannerInfo{}, ErrInvalidUrlPath
+		return domain.LTBannerInfo{}, layout.Theme{}, ErrInvalidUrlPath
 	}
 
-	bannerType := domain.BannerTypeDefault
+	theme, ok := u.themes.Get(req.BannerType)
+	if !ok {
+		return domain.LTBannerInfo{}, layout.Theme{}, ErrInvalidBannerType
+	}
+
+	bannerLayout, err := validateLayout(req.Layout)
+	if err != nil {
+		return domain.LTBannerInfo{}, layout.Theme{}, err
+	}
 
-	switch req.BannerType {
-	case string(domain.BannerTypeDark):
-		bannerType = domain.BannerTypeDark
-	case string(domain.BannerTypeDefault):
-		bannerType = domain.BannerTypeDefault
-	default:
-		return domain.LTBannerInfo{}, ErrInvalidBannerType
+	motion, err := validateMotion(req.Motion)
+	if err != nil {
+		return domain.LTBannerInfo{}, layout.Theme{}, err
+	}
+
+	kind, err := validateKind(req.Kind)
+	if err != nil {
+		return domain.LTBannerInfo{}, layout.Theme{}, err
 	}
 
 	return domain.LTBannerInfo{
 		URLPath: req.URLPath,
 		BannerInfo: domain.BannerInfo{
 			Username:   req.Username,
-			BannerType: bannerType,
+			Kind:       kind,
+			BannerType: domain.BannerType(theme.Name),
+			Layout:     bannerLayout,
+			Motion:     motion,
 			Stats:      req.Stats,
 		},
-	}, nil
+	}, theme, nil
 }
 
 func (u *Usecase) Render(ctx context.Context, req RenderIn) ([]byte, error) {
-	info, err := u.validateRenderIn(req)
+	info, theme, err := u.validateRenderIn(req)
 	if err != nil {
 		return nil, err
 	}
 
-	view := layout.BuildView(info)
-	renderedData, err := u.renderer.RenderBanner(view)
+	format, err := validateFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	view := layout.BuildView(info, theme)
+	renderedData, err := u.renderView(view, format)
 	if err != nil {
 		return nil, err
 	}
 	return renderedData, nil
 }
 
-func (u *Usecase) validateRenderIn(req RenderIn) (domain.BannerInfo, error) {
+func (u *Usecase) validateRenderIn(req RenderIn) (domain.BannerInfo, layout.Theme, error) {
 	if req.Username == "" {
-		return domain.BannerInfo{}, ErrInvalidUsername
+		return domain.BannerInfo{}, layout.Theme{}, ErrInvalidUsername
+	}
+
+	theme, ok := u.themes.Get(req.BannerType)
+	if !ok {
+		return domain.BannerInfo{}, layout.Theme{}, ErrInvalidBannerType
 	}
 
-	bannerType := domain.BannerTypeDefault
+	bannerLayout, err := validateLayout(req.Layout)
+	if err != nil {
+		return domain.BannerInfo{}, layout.Theme{}, err
+	}
 
-	switch req.BannerType {
-	case string(domain.BannerTypeDark):
-		bannerType = domain.BannerTypeDark
-	case string(domain.BannerTypeDefault):
-		bannerType = domain.BannerTypeDefault
-	default:
-		return domain.BannerInfo{}, ErrInvalidBannerType
+	motion, err := validateMotion(req.Motion)
+	if err != nil {
+		return domain.BannerInfo{}, layout.Theme{}, err
+	}
+
+	kind, err := validateKind(req.Kind)
+	if err != nil {
+		return domain.BannerInfo{}, layout.Theme{}, err
 	}
 
 	return domain.BannerInfo{
 		Username:   req.Username,
-		BannerType: bannerType,
+		Kind:       kind,
+		BannerType: domain.BannerType(theme.Name),
+		Layout:     bannerLayout,
+		Motion:     motion,
 		Stats:      req.Stats,
-	}, nil
+	}, theme, nil
+}
+
+// validateLayout returns default layout for blank one
+func validateLayout(l string) (domain.BannerLayout, error) {
+	if l == "" {
+		return domain.LayoutDefault, nil
+	}
+	if !layout.Supported(domain.BannerLayout(l)) {
+		return "", ErrInvalidLayout
+	}
+	return domain.BannerLayout(l), nil
+}
+
+// validateMotion returns MotionOn for blank motion
+func validateMotion(m string) (domain.BannerMotion, error) {
+	switch domain.BannerMotion(m) {
+	case "", domain.MotionOn:
+		return domain.MotionOn, nil
+	case domain.MotionOff:
+		return domain.MotionOff, nil
+	}
+	return "", ErrInvalidMotion
+}
+
+// validateKind returns KindUser for blank kind
+func validateKind(k string) (domain.BannerKind, error) {
+	switch domain.BannerKind(k) {
+	case "", domain.KindUser:
+		return domain.KindUser, nil
+	case domain.KindOrg:
+		return domain.KindOrg, nil
+	case domain.KindRepository:
+		return domain.KindRepository, nil
+	}
+	return "", ErrInvalidKind
+}
+
+// validateFormat returns svg for blank format
+func validateFormat(f string) (domain.BannerFormat, error) {
+	switch domain.BannerFormat(f) {
+	case "", domain.FormatSVG:
+		return domain.FormatSVG, nil
+	case domain.FormatPNG:
+		return domain.FormatPNG, nil
+	}
+	return "", ErrInvalidFormat
 }
@@ -26,9 +26,8 @@ func NewRenderer() (*Renderer, error) {
 
 func (r *Renderer) RenderBanner(view *layout.BannerView) ([]byte, error) {
 	var buf bytes.Buffer
-	templateName := "banner.svg"
 
-	if err := r.tmpl.ExecuteTemplate(&buf, templateName, view); err != nil {
+	if err := r.tmpl.ExecuteTemplate(&buf, view.Template, view); err != nil {
 		return nil, render.ErrRenderFailure
 	}
 	return buf.Bytes(), nil
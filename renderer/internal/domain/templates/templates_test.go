@@ -0,0 +1,143 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+	"github.com/hurtki/github-banners/renderer/internal/domain/templates"
+	"github.com/hurtki/github-banners/renderer/internal/domain/themes"
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+)
+
+// update regenerates golden files: go test ./internal/domain/templates -update
+var update = flag.Bool("update", false, "regenerate golden files")
+
+var fetchedAt = time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+
+type fixture struct {
+	name string
+	info domain.BannerInfo
+}
+
+func stats(repos, stars, forks int, langs map[string]int) domain.GithubUserStats {
+	return domain.GithubUserStats{
+		TotalRepos:    repos,
+		OriginalRepos: repos - repos/4,
+		ForkedRepos:   repos / 4,
+		TotalStars:    stars,
+		TotalForks:    forks,
+		Languages:     langs,
+		FetchedAt:     fetchedAt,
+	}
+}
+
+func activityStats() domain.GithubUserStats {
+	s := stats(42, 1234, 56, regularLanguages)
+	s.Contributions = &domain.GithubContributions{
+		Total: 842, Commits: 610, PullRequests: 45, Issues: 12, Reviews: 30, CurrentStreak: 7, LongestStreak: 23,
+	}
+	return s
+}
+
+func orgStats() domain.GithubUserStats {
+	s := stats(42, 1234, 56, regularLanguages)
+	s.Members = 17
+	return s
+}
+
+func repoStats() domain.GithubUserStats {
+	s := stats(1, 1234, 56, map[string]int{"Go": 90000, "Dockerfile": 1200, "Makefile": 800})
+	license, release := "MIT", "v1.2.0"
+	pushed := time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)
+	s.Repository = &domain.GithubRepoStats{OpenIssues: 7, License: &license, LatestRelease: &release, PushedAt: &pushed}
+	return s
+}
+
+var regularLanguages = map[string]int{"Go": 12, "Python": 5, "TypeScript": 3}
+
+var fixtures = []fixture{
+	{"no-languages", domain.BannerInfo{Username: "hurtki", Stats: stats(0, 0, 0, nil)}},
+	{"many-languages", domain.BannerInfo{Username: "polyglot", Stats: stats(120, 340, 56, map[string]int{
+		"Go": 30, "Python": 22, "TypeScript": 18, "Rust": 11, "C": 9, "C++": 7,
+		"Shell": 5, "Lua": 3, "Haskell": 2, "Zig": 1, "Makefile": 1, "Dockerfile": 1,
+	})}},
+	{"huge-stars", domain.BannerInfo{Username: "torvalds", Stats: stats(7, 2147483647, 999999999, map[string]int{"C": 6, "OpenSCAD": 1})}},
+	{"unicode-username", domain.BannerInfo{Username: "Ünïcødé-用户-<&>", Stats: stats(3, 14, 1, map[string]int{"Go": 2, "F#": 1})}},
+	{"compact", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutCompact, Stats: stats(42, 1234, 56, regularLanguages)}},
+	{"wide", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutWide, Stats: stats(42, 1234, 56, regularLanguages)}},
+	{"card", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutCard, Stats: stats(42, 1234, 56, regularLanguages)}},
+	{"org-card", domain.BannerInfo{Username: "gophers", Kind: domain.KindOrg, Layout: domain.LayoutCard, Stats: orgStats()}},
+	{"languages", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutLanguages, Stats: stats(42, 1234, 56, regularLanguages)}},
+	{"activity", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutActivity, Stats: activityStats()}},
+	{"activity-no-contributions", domain.BannerInfo{Username: "hurtki", Layout: domain.LayoutActivity, Stats: stats(42, 1234, 56, regularLanguages)}},
+	{"repository", domain.BannerInfo{Username: "hurtki/github-banners", Kind: domain.KindRepository, Layout: domain.LayoutRepository, Stats: repoStats()}},
+	{"repository-no-details", domain.BannerInfo{Username: "hurtki/dotfiles", Kind: domain.KindRepository, Layout: domain.LayoutRepository, Stats: stats(1, 3, 0, nil)}},
+	{"static", domain.BannerInfo{Username: "hurtki", Motion: domain.MotionOff, Stats: stats(42, 1234, 56, regularLanguages)}},
+}
+
+func TestRenderBannerGolden(t *testing.T) {
+	renderer, err := templates.NewRenderer()
+	if err != nil {
+		t.Fatalf("can't create renderer: %v", err)
+	}
+	registry, err := themes.NewRegistry("")
+	if err != nil {
+		t.Fatalf("can't load themes: %v", err)
+	}
+
+	for _, theme := range registry.List() {
+		for _, f := range fixtures {
+			name := theme.Name + "-" + f.name
+			t.Run(name, func(t *testing.T) {
+				info := f.info
+				info.BannerType = domain.BannerType(theme.Name)
+
+				got, err := renderer.RenderBanner(layout.BuildView(info, theme))
+				if err != nil {
+					t.Fatalf("can't render banner: %v", err)
+				}
+				if err := checkWellFormed(got); err != nil {
+					t.Fatalf("rendered svg isn't well-formed xml: %v", err)
+				}
+
+				golden := filepath.Join("testdata", "golden", name+".svg")
+				if *update {
+					if err := os.WriteFile(golden, got, 0o644); err != nil {
+						t.Fatalf("can't update golden file: %v", err)
+					}
+					return
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("can't read golden file, run tests with -update to create it: %v", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("rendered banner differs from %s, run tests with -update, if the change is expected", golden)
+				}
+			})
+		}
+	}
+}
+
+// checkWellFormed reads all the xml tokens, decoder fails on unclosed or mismatched tags and bad escaping
+func checkWellFormed(data []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
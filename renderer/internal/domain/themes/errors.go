@@ -0,0 +1,9 @@
+package themes
+
+import "errors"
+
+var (
+	ErrInvalidTheme   = errors.New("invalid theme")
+	ErrDuplicateTheme = errors.New("theme with the same name is already defined")
+	ErrNoThemes       = errors.New("no themes were loaded")
+)
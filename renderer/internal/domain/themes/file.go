@@ -0,0 +1,89 @@
+package themes
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+)
+
+// themeFile is a structure of YAML/JSON theme file
+type themeFile struct {
+	Name   string `json:"name" yaml:"name"`
+	Colors struct {
+		Background      string `json:"background" yaml:"background"`
+		Foreground      string `json:"foreground" yaml:"foreground"`
+		Muted           string `json:"muted" yaml:"muted"`
+		Accent          string `json:"accent" yaml:"accent"`
+		AccentSecondary string `json:"accent_secondary" yaml:"accent_secondary"`
+	} `json:"colors" yaml:"colors"`
+	Gradient []struct {
+		Offset int    `json:"offset" yaml:"offset"`
+		Color  string `json:"color" yaml:"color"`
+	} `json:"gradient" yaml:"gradient"`
+	Font struct {
+		Family string `json:"family" yaml:"family"`
+	} `json:"font" yaml:"font"`
+}
+
+var (
+	// theme name becomes part of banner's url path, so only safe symbols are allowed
+	themeNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_]{0,31}$`)
+	colorRegexp     = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\))$`)
+	fontRegexp      = regexp.MustCompile(`^[A-Za-z0-9 ,'"-]+$`)
+)
+
+const defaultFontFamily = "'Courier New', monospace"
+
+// toLayoutTheme validates theme file and converts it to layout.Theme
+func (f themeFile) toLayoutTheme() (layout.Theme, error) {
+	if !themeNameRegexp.MatchString(f.Name) {
+		return layout.Theme{}, fmt.Errorf("%w: name %q should match %s", ErrInvalidTheme, f.Name, themeNameRegexp)
+	}
+
+	colors := map[string]string{
+		"background":       f.Colors.Background,
+		"foreground":       f.Colors.Foreground,
+		"muted":            f.Colors.Muted,
+		"accent":           f.Colors.Accent,
+		"accent_secondary": f.Colors.AccentSecondary,
+	}
+	for field, color := range colors {
+		if !colorRegexp.MatchString(color) {
+			return layout.Theme{}, fmt.Errorf("%w: %s: invalid %s color %q", ErrInvalidTheme, f.Name, field, color)
+		}
+	}
+
+	theme := layout.Theme{
+		Name:            f.Name,
+		Background:      f.Colors.Background,
+		Foreground:      f.Colors.Foreground,
+		Muted:           f.Colors.Muted,
+		Accent:          f.Colors.Accent,
+		AccentSecondary: f.Colors.AccentSecondary,
+		FontFamily:      f.Font.Family,
+	}
+
+	if theme.FontFamily == "" {
+		theme.FontFamily = defaultFontFamily
+	}
+	if !fontRegexp.MatchString(theme.FontFamily) {
+		return layout.Theme{}, fmt.Errorf("%w: %s: invalid font family %q", ErrInvalidTheme, f.Name, theme.FontFamily)
+	}
+
+	// without gradient background is filled with one color
+	if len(f.Gradient) == 0 {
+		theme.GradientStops = []layout.GradientStop{{Offset: 0, Color: theme.Background}}
+	}
+	for _, stop := range f.Gradient {
+		if stop.Offset < 0 || stop.Offset > 100 {
+			return layout.Theme{}, fmt.Errorf("%w: %s: gradient offset %d should be in [0, 100]", ErrInvalidTheme, f.Name, stop.Offset)
+		}
+		if !colorRegexp.MatchString(stop.Color) {
+			return layout.Theme{}, fmt.Errorf("%w: %s: invalid gradient color %q", ErrInvalidTheme, f.Name, stop.Color)
+		}
+		theme.GradientStops = append(theme.GradientStops, layout.GradientStop{Offset: stop.Offset, Color: stop.Color})
+	}
+
+	return theme, nil
+}
@@ -0,0 +1,111 @@
+package themes
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed assets/*.yaml
+var embeddedThemes embed.FS
+
+// Registry keeps all the themes, that banners can be rendered with
+// theme name is a banner type
+type Registry struct {
+	themes map[string]layout.Theme
+}
+
+// NewRegistry loads embedded themes and themes from dir ( if it's not blank )
+// themes from dir override embedded themes with the same name
+func NewRegistry(dir string) (*Registry, error) {
+	themes, err := loadDir(embeddedThemes, "assets")
+	if err != nil {
+		return nil, fmt.Errorf("can't load embedded themes: %w", err)
+	}
+
+	if dir != "" {
+		diskThemes, err := loadDir(os.DirFS(dir), ".")
+		if err != nil {
+			return nil, fmt.Errorf("can't load themes from %s: %w", dir, err)
+		}
+		for name, theme := range diskThemes {
+			themes[name] = theme
+		}
+	}
+
+	if len(themes) == 0 {
+		return nil, ErrNoThemes
+	}
+	return &Registry{themes: themes}, nil
+}
+
+// Get returns theme with given name
+func (r *Registry) Get(name string) (layout.Theme, bool) {
+	theme, ok := r.themes[name]
+	return theme, ok
+}
+
+// List returns all the themes sorted by name
+func (r *Registry) List() []layout.Theme {
+	res := make([]layout.Theme, 0, len(r.themes))
+	for _, theme := range r.themes {
+		res = append(res, theme)
+	}
+	slices.SortFunc(res, func(a, b layout.Theme) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+// loadDir parses all the .yaml, .yml and .json files of the dir
+func loadDir(fsys fs.FS, dir string) (map[string]layout.Theme, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	themes := make(map[string]layout.Theme, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		var unmarshal func([]byte, any) error
+		switch path.Ext(entry.Name()) {
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		case ".json":
+			unmarshal = json.Unmarshal
+		default:
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var file themeFile
+		if err := unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTheme, entry.Name(), err)
+		}
+
+		theme, err := file.toLayoutTheme()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if _, ok := themes[theme.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateTheme, theme.Name)
+		}
+		themes[theme.Name] = theme
+	}
+	return themes, nil
+}
@@ -4,16 +4,64 @@ import (
 	"time"
 )
 
+// BannerType is a name of the theme from themes registry
 type BannerType string
 
+// BannerKind is a kind of github entity, that banner is built for
+type BannerKind string
+
+const (
+	KindUser BannerKind = "user"
+	KindOrg  BannerKind = "org"
+	// single repository, username of its banner is a full name: owner/name
+	KindRepository BannerKind = "repository"
+)
+
+// BannerLayout is a name of the banner's layout: its size and shown blocks
+type BannerLayout string
+
+const (
+	LayoutDefault BannerLayout = "default"
+	// small badge with main counters
+	LayoutCompact BannerLayout = "compact"
+	// 800px header for profile README
+	LayoutWide BannerLayout = "wide"
+	// card with counters only
+	LayoutCard BannerLayout = "card"
+	// languages donut
+	LayoutLanguages BannerLayout = "languages"
+	// card with contribution activity of the last year
+	LayoutActivity BannerLayout = "activity"
+	// card of single repository with its license, latest release and languages
+	LayoutRepository BannerLayout = "repository"
+)
+
+// BannerFormat is a format of the rendered banner's file
+type BannerFormat string
+
 const (
-	BannerTypeDefault BannerType = "default"
-	BannerTypeDark    BannerType = "dark"
+	// animated svg, that is rendered from templates
+	FormatSVG BannerFormat = "svg"
+	// static png for places, that can't show svg
+	FormatPNG BannerFormat = "png"
+)
+
+// BannerMotion tells, whether svg banner is animated
+type BannerMotion string
+
+const (
+	MotionOn BannerMotion = "on"
+	// static svg without animations and glitch filters
+	// for reduced motion and sanitizers, that strip animations
+	MotionOff BannerMotion = "off"
 )
 
 type BannerInfo struct {
 	Username   string
+	Kind       BannerKind
 	BannerType BannerType
+	Layout     BannerLayout
+	Motion     BannerMotion
 	Stats      GithubUserStats
 }
 
@@ -29,5 +77,33 @@ type GithubUserStats struct {
 	TotalStars    int
 	TotalForks    int
 	Languages     map[string]int
-	FetchedAt     time.Time
+	// nil, if api didn't fetch contributions yet
+	Contributions *GithubContributions
+	// public members, only for organizations
+	Members int
+	// nil for banners of users and organizations
+	Repository *GithubRepoStats
+	FetchedAt  time.Time
+}
+
+// GithubRepoStats are details of single repository
+type GithubRepoStats struct {
+	// open issues together with open pull requests
+	OpenIssues int
+	// nil, if repository has no license
+	License *string
+	// tag of the latest release, nil if repository has no releases
+	LatestRelease *string
+	PushedAt      *time.Time
+}
+
+// GithubContributions is a contribution activity of user in the last year
+type GithubContributions struct {
+	Total         int
+	Commits       int
+	PullRequests  int
+	Issues        int
+	Reviews       int
+	CurrentStreak int
+	LongestStreak int
 }
@@ -41,6 +41,9 @@ func (h *UpdateBannerHandler) Handle(ctx context.Context, msg Message) error {
 	if err != nil {
 		switch {
 		case errors.Is(err, render.ErrInvalidBannerType),
+			errors.Is(err, render.ErrInvalidLayout),
+			errors.Is(err, render.ErrInvalidMotion),
+			errors.Is(err, render.ErrInvalidKind),
 			errors.Is(err, render.ErrInvalidUsername),
 			errors.Is(err, render.ErrInvalidUrlPath):
 			return fmt.Errorf("%w:%w", err, ErrValidation)
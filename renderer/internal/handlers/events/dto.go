@@ -30,7 +30,10 @@ func (e BannerUpdateEvent) String() string {
 
 type BannerUpdateInfo struct {
 	Username    string            `json:"username"`
+	Kind        string            `json:"kind,omitempty"`
 	BannerType  string            `json:"banner_type"`
+	Layout      string            `json:"layout,omitempty"`
+	Motion      string            `json:"motion,omitempty"`
 	StoragePath string            `json:"storage_path"`
 	FetchedAt   time.Time         `json:"fetched_at"` // RFC3339
 	Stats       BannerUpdateStats `json:"stats"`
@@ -39,7 +42,10 @@ type BannerUpdateInfo struct {
 func (i BannerUpdateInfo) ToDomainInUpdateBannerIn() render.UpdateBannerIn {
 	return render.UpdateBannerIn{
 		Username:   i.Username,
+		Kind:       i.Kind,
 		BannerType: i.BannerType,
+		Layout:     i.Layout,
+		Motion:     i.Motion,
 		URLPath:    i.StoragePath,
 		Stats: domain.GithubUserStats{
 			TotalRepos:    i.Stats.TotalRepos,
@@ -48,16 +54,66 @@ func (i BannerUpdateInfo) ToDomainInUpdateBannerIn() render.UpdateBannerIn {
 			TotalStars:    i.Stats.TotalStars,
 			TotalForks:    i.Stats.TotalForks,
 			Languages:     i.Stats.Languages,
+			Contributions: i.Stats.Contributions.toDomain(),
+			Members:       i.Stats.Members,
+			Repository:    i.Stats.Repository.toDomain(),
 			FetchedAt:     i.FetchedAt,
 		},
 	}
 }
 
 type BannerUpdateStats struct {
-	TotalRepos    int            `json:"total_repos"`
-	OriginalRepos int            `json:"original_repos"`
-	ForkedRepos   int            `json:"forked_repos"`
-	TotalStars    int            `json:"total_stars"`
-	TotalForks    int            `json:"total_forks"`
-	Languages     map[string]int `json:"languages"`
+	TotalRepos    int                        `json:"total_repos"`
+	OriginalRepos int                        `json:"original_repos"`
+	ForkedRepos   int                        `json:"forked_repos"`
+	TotalStars    int                        `json:"total_stars"`
+	TotalForks    int                        `json:"total_forks"`
+	Languages     map[string]int             `json:"languages"`
+	Contributions *BannerUpdateContributions `json:"contributions,omitempty"`
+	Members       int                        `json:"members,omitempty"`
+	Repository    *BannerUpdateRepository    `json:"repository,omitempty"`
+}
+
+type BannerUpdateContributions struct {
+	Total         int `json:"total"`
+	Commits       int `json:"commits"`
+	PullRequests  int `json:"pull_requests"`
+	Issues        int `json:"issues"`
+	Reviews       int `json:"reviews"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+func (c *BannerUpdateContributions) toDomain() *domain.GithubContributions {
+	if c == nil {
+		return nil
+	}
+	return &domain.GithubContributions{
+		Total:         c.Total,
+		Commits:       c.Commits,
+		PullRequests:  c.PullRequests,
+		Issues:        c.Issues,
+		Reviews:       c.Reviews,
+		CurrentStreak: c.CurrentStreak,
+		LongestStreak: c.LongestStreak,
+	}
+}
+
+type BannerUpdateRepository struct {
+	OpenIssues    int        `json:"open_issues"`
+	License       *string    `json:"license,omitempty"`
+	LatestRelease *string    `json:"latest_release,omitempty"`
+	PushedAt      *time.Time `json:"pushed_at,omitempty"`
+}
+
+func (r *BannerUpdateRepository) toDomain() *domain.GithubRepoStats {
+	if r == nil {
+		return nil
+	}
+	return &domain.GithubRepoStats{
+		OpenIssues:    r.OpenIssues,
+		License:       r.License,
+		LatestRelease: r.LatestRelease,
+		PushedAt:      r.PushedAt,
+	}
 }
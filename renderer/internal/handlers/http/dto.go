@@ -9,24 +9,58 @@ import (
 
 type PreviewRequest struct {
 	Username   string       `json:"username"`
+	Kind       string       `json:"kind,omitempty"`
 	BannerType string       `json:"banner_type"`
+	Layout     string       `json:"layout,omitempty"`
+	Motion     string       `json:"motion,omitempty"`
 	Stats      PreviewStats `json:"stats"`
 	FetchedAt  time.Time    `json:"fetched_at"`
 }
 
 type PreviewStats struct {
-	TotalRepos    int            `json:"total_repos"`
-	OriginalRepos int            `json:"original_repos"`
-	ForkedRepos   int            `json:"forked_repos"`
-	TotalStars    int            `json:"total_stars"`
-	TotalForks    int            `json:"total_forks"`
-	Languages     map[string]int `json:"languages"`
+	TotalRepos    int                   `json:"total_repos"`
+	OriginalRepos int                   `json:"original_repos"`
+	ForkedRepos   int                   `json:"forked_repos"`
+	TotalStars    int                   `json:"total_stars"`
+	TotalForks    int                   `json:"total_forks"`
+	Languages     map[string]int        `json:"languages"`
+	Contributions *PreviewContributions `json:"contributions,omitempty"`
+	Members       int                   `json:"members,omitempty"`
+	Repository    *PreviewRepository    `json:"repository,omitempty"`
+}
+
+type PreviewContributions struct {
+	Total         int `json:"total"`
+	Commits       int `json:"commits"`
+	PullRequests  int `json:"pull_requests"`
+	Issues        int `json:"issues"`
+	Reviews       int `json:"reviews"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+func (c *PreviewContributions) toDomain() *domain.GithubContributions {
+	if c == nil {
+		return nil
+	}
+	return &domain.GithubContributions{
+		Total:         c.Total,
+		Commits:       c.Commits,
+		PullRequests:  c.PullRequests,
+		Issues:        c.Issues,
+		Reviews:       c.Reviews,
+		CurrentStreak: c.CurrentStreak,
+		LongestStreak: c.LongestStreak,
+	}
 }
 
 func (req PreviewRequest) ToDomainRenderIn() render.RenderIn {
 	return render.RenderIn{
 		Username:   req.Username,
+		Kind:       req.Kind,
 		BannerType: req.BannerType,
+		Layout:     req.Layout,
+		Motion:     req.Motion,
 		Stats: domain.GithubUserStats{
 			TotalRepos:    req.Stats.TotalRepos,
 			OriginalRepos: req.Stats.OriginalRepos,
@@ -34,6 +68,9 @@ func (req PreviewRequest) ToDomainRenderIn() render.RenderIn {
 			TotalStars:    req.Stats.TotalStars,
 			TotalForks:    req.Stats.TotalForks,
 			Languages:     req.Stats.Languages,
+			Contributions: req.Stats.Contributions.toDomain(),
+			Members:       req.Stats.Members,
+			Repository:    req.Stats.Repository.toDomain(),
 			FetchedAt:     req.FetchedAt,
 		},
 	}
@@ -42,3 +79,32 @@ func (req PreviewRequest) ToDomainRenderIn() render.RenderIn {
 type ErrorResponse struct {
 	Message string `json:"message"`
 }
+
+type ThemesResponse struct {
+	Themes []ThemeResponse `json:"themes"`
+}
+
+type ThemeResponse struct {
+	Name       string `json:"name"`
+	Background string `json:"background"`
+	Accent     string `json:"accent"`
+}
+
+type PreviewRepository struct {
+	OpenIssues    int        `json:"open_issues"`
+	License       *string    `json:"license,omitempty"`
+	LatestRelease *string    `json:"latest_release,omitempty"`
+	PushedAt      *time.Time `json:"pushed_at,omitempty"`
+}
+
+func (r *PreviewRepository) toDomain() *domain.GithubRepoStats {
+	if r == nil {
+		return nil
+	}
+	return &domain.GithubRepoStats{
+		OpenIssues:    r.OpenIssues,
+		License:       r.License,
+		LatestRelease: r.LatestRelease,
+		PushedAt:      r.PushedAt,
+	}
+}
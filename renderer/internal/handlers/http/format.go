@@ -0,0 +1,36 @@
+package http_handlers
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+)
+
+var formatContentTypes = map[domain.BannerFormat]string{
+	domain.FormatSVG: "image/svg+xml",
+	domain.FormatPNG: "image/png",
+}
+
+// requestedFormat returns format from "format" query parameter
+// if it's not set, the first of supported media types in Accept header is used
+// svg is returned, if client doesn't ask for any format
+func requestedFormat(r *http.Request) string {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f
+	}
+
+	for part := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for format, contentType := range formatContentTypes {
+			if mediaType == contentType {
+				return string(format)
+			}
+		}
+	}
+	return string(domain.FormatSVG)
+}
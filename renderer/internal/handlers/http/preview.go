@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/hurtki/github-banners/renderer/internal/domain"
 	"github.com/hurtki/github-banners/renderer/internal/domain/render"
 	"github.com/hurtki/github-banners/renderer/internal/logger"
 )
@@ -34,13 +35,15 @@ func (h *PreviewHandler) Preview(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer r.Body.Close()
-	h.logger.Debug("Received preview payload", "username", req.Username, "banner_type", req.BannerType)
-
 	renderIn := req.ToDomainRenderIn()
+	renderIn.Format = requestedFormat(r)
+	h.logger.Debug("Received preview payload", "username", req.Username, "banner_type", req.BannerType, "layout", req.Layout, "motion", req.Motion, "format", renderIn.Format)
 
-	svgBytes, err := h.usecase.Render(r.Context(), renderIn)
+	bannerBytes, err := h.usecase.Render(r.Context(), renderIn)
 	if err != nil {
-		if errors.Is(err, render.ErrInvalidUsername) || errors.Is(err, render.ErrInvalidBannerType) {
+		if errors.Is(err, render.ErrInvalidUsername) || errors.Is(err, render.ErrInvalidBannerType) || errors.Is(err, render.ErrInvalidLayout) ||
+			errors.Is(err, render.ErrInvalidMotion) || errors.Is(err, render.ErrInvalidKind) ||
+			errors.Is(err, render.ErrInvalidFormat) {
 			h.error(rw, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -49,11 +52,11 @@ func (h *PreviewHandler) Preview(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rw.Header().Set("Content-Type", "image/svg+xml")
+	rw.Header().Set("Content-Type", formatContentTypes[domain.BannerFormat(renderIn.Format)])
 	rw.WriteHeader(http.StatusOK)
-	_, err = rw.Write(svgBytes)
+	_, err = rw.Write(bannerBytes)
 	if err != nil {
-		h.logger.Warn("can't write svg response", "err", err, "source", fn)
+		h.logger.Warn("can't write banner response", "err", err, "source", fn)
 	}
 }
 
@@ -0,0 +1,46 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hurtki/github-banners/renderer/internal/layout"
+	"github.com/hurtki/github-banners/renderer/internal/logger"
+)
+
+type ThemesLister interface {
+	List() []layout.Theme
+}
+
+type ThemesHandler struct {
+	logger logger.Logger
+	themes ThemesLister
+}
+
+func NewThemesHandler(logger logger.Logger, themes ThemesLister) *ThemesHandler {
+	return &ThemesHandler{
+		logger: logger.With("service", "themes-http-handler"),
+		themes: themes,
+	}
+}
+
+// List returns names of all the themes, that renderer can render banners with
+func (h *ThemesHandler) List(rw http.ResponseWriter, r *http.Request) {
+	fn := "internal.handlers.http.ThemesHandler.List"
+
+	themes := h.themes.List()
+	res := ThemesResponse{Themes: make([]ThemeResponse, 0, len(themes))}
+	for _, theme := range themes {
+		res.Themes = append(res.Themes, ThemeResponse{
+			Name:       theme.Name,
+			Background: theme.Background,
+			Accent:     theme.Accent,
+		})
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(rw).Encode(res); err != nil {
+		h.logger.Warn("can't write themes response", "err", err, "source", fn)
+	}
+}
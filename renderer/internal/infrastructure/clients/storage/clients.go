@@ -39,16 +39,16 @@ func NewClient(baseURL string, httpClient *http.Client, logger logger.Logger) *C
 	}
 }
 
-func (c *Client) SaveBanner(ctx context.Context, bannerID string, svg string) (string, error) {
+func (c *Client) SaveBanner(ctx context.Context, bannerID string, data []byte, format domain.BannerFormat) (string, error) {
 	const fn = "infrastructure.clients.storage.SaveBanner"
 
 	start := time.Now()
 
-	encoded := base64.StdEncoding.EncodeToString([]byte(svg))
+	encoded := base64.StdEncoding.EncodeToString(data)
 	reqBody := SaveRequest{
 		URLPath:      bannerID,
 		BannerData:   encoded,
-		BannerFormat: "svg",
+		BannerFormat: string(format),
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -77,7 +77,7 @@ func (c *Client) SaveBanner(ctx context.Context, bannerID string, svg string) (s
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		c.logger.Error("storage upload failed", "status", resp.StatusCode, "banner_id", bannerID, "body", string(respBody), "duration", duration)
+		c.logger.Error("storage upload failed", "status", resp.StatusCode, "banner_id", bannerID, "format", format, "body", string(respBody), "duration", duration)
 		return "", fmt.Errorf("storage returned status %d, %w", resp.StatusCode, domain.ErrUnavailable)
 	}
 
@@ -86,7 +86,7 @@ func (c *Client) SaveBanner(ctx context.Context, bannerID string, svg string) (s
 		return "", fmt.Errorf("%s: decode response: %w, %w", fn, err, domain.ErrUnavailable)
 	}
 
-	c.logger.Debug("banner stored successfully", "banner_id", bannerID, "url", saveResp.URL, "duration", duration)
+	c.logger.Debug("banner stored successfully", "banner_id", bannerID, "format", format, "url", saveResp.URL, "duration", duration)
 
 	return saveResp.URL, nil
 }
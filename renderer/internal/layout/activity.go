@@ -0,0 +1,42 @@
+package layout
+
+import "github.com/hurtki/github-banners/renderer/internal/domain"
+
+// buildActivityView builds card with contribution activity of the last year
+// it's drawn with card's template, zeros are shown, if contributions weren't fetched yet
+func buildActivityView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W      = 360
+		H      = 190
+		pad    = 20
+		cols   = 3
+		boxW   = 100
+		boxH   = 46
+		boxGap = 10
+		gridY  = 62
+	)
+
+	var c domain.GithubContributions
+	if info.Stats.Contributions != nil {
+		c = *info.Stats.Contributions
+	}
+
+	stats := []StatItem{
+		{Label: "CONTRIBUTIONS", Value: c.Total, Color: theme.Accent},
+		{Label: "STREAK", Value: c.CurrentStreak, Color: theme.AccentSecondary},
+		{Label: "LONGEST", Value: c.LongestStreak, Color: theme.AccentSecondary},
+		{Label: "PULL REQUESTS", Value: c.PullRequests, Color: theme.Accent},
+		{Label: "ISSUES", Value: c.Issues, Color: theme.Accent},
+		{Label: "REVIEWS", Value: c.Reviews, Color: forksColor},
+	}
+	for i := range stats {
+		stats[i].X = pad + (i%cols)*(boxW+boxGap)
+		stats[i].Y = gridY + (i/cols)*(boxH+boxGap)
+		stats[i].Width = boxW
+		stats[i].Height = boxH
+	}
+
+	view := baseView(info, theme, domain.LayoutActivity, "card.svg", W, H)
+	view.StatItems = stats
+	return view
+}
@@ -2,91 +2,72 @@ package layout
 
 import (
 	"fmt"
-	"math"
-	"sort"
 
 	"github.com/hurtki/github-banners/renderer/internal/domain"
 )
 
-func BuildView(info domain.BannerInfo) *BannerView {
-	const (
-		W        = 460
-		H        = 210
-		pad      = 20
-		barWidth = W - pad*2
-		maxLangs = 5
-	)
-
-	var theme Theme
+type viewBuilder func(info domain.BannerInfo, theme Theme) *BannerView
+
+// builders are view builders of all the supported layouts
+// every builder sets template, that its view should be rendered with
+var builders = map[domain.BannerLayout]viewBuilder{
+	domain.LayoutDefault:    buildDefaultView,
+	domain.LayoutCompact:    buildCompactView,
+	domain.LayoutWide:       buildWideView,
+	domain.LayoutCard:       buildCardView,
+	domain.LayoutLanguages:  buildLanguagesView,
+	domain.LayoutActivity:   buildActivityView,
+	domain.LayoutRepository: buildRepositoryView,
+}
 
-	if string(info.BannerType) == "dark" || string(info.BannerType) == string(domain.BannerTypeDark) {
-		theme = Theme{
-			Background:                 "#0d1117",
-			Foreground:                 "#e6edf3",
-			Muted:                      "#8b949e",
-			BackgroundColorGradientOne: "#161b22",
-		}
-	} else {
-		theme = Theme{
-			Background:                 "#f6f8fa",
-			Foreground:                 "#24292f",
-			Muted:                      "#57606a",
-			BackgroundColorGradientOne: "#ffffff",
-		}
-	}
+// Supported reports, whether banner can be built with given layout
+func Supported(l domain.BannerLayout) bool {
+	_, ok := builders[l]
+	return ok
+}
 
-	total := 0
-	for _, v := range info.Stats.Languages {
-		total += v
+// BuildView builds view of the banner for its layout
+// blank or unknown layout is built as default one
+func BuildView(info domain.BannerInfo, theme Theme) *BannerView {
+	build, ok := builders[info.Layout]
+	if !ok {
+		build = buildDefaultView
 	}
+	return build(info, theme)
+}
 
-	type kv struct {
-		Name  string
-		Value int
-	}
+const timeFormat = "02 Jan 2006 · 15:04"
 
-	var sorted []kv
-	for k, v := range info.Stats.Languages {
-		sorted = append(sorted, kv{k, v})
+func baseView(info domain.BannerInfo, theme Theme, l domain.BannerLayout, tmpl string, w, h int) *BannerView {
+	return &BannerView{
+		Template:      tmpl,
+		Layout:        string(l),
+		Animated:      info.Motion != domain.MotionOff,
+		Width:         w,
+		Height:        h,
+		Username:      info.Username,
+		BannerType:    string(info.BannerType),
+		Stats:         info.Stats,
+		Theme:         theme,
+		FormattedTime: info.Stats.FetchedAt.Format(timeFormat),
 	}
+}
 
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Value > sorted[j].Value
-	})
+func buildDefaultView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W        = 460
+		H        = 215
+		pad      = 20
+		barWidth = W - pad*2
+		maxLangs = 5
+		legendY  = 170
+		colW     = 145
+	)
 
-	if len(sorted) > maxLangs {
-		other := 0
-		for _, l := range sorted[maxLangs:] {
-			other += l.Value
-		}
-		sorted = append(sorted[:maxLangs], kv{
-			Name:  "Other",
-			Value: other,
-		})
-	}
+	shares := languageShares(info.Stats.Languages, maxLangs)
 
-	var segments []LanguageSegment
 	var legend []LegendItem
-
-	cursor := pad
-	legendY := 170
-	colW := 145
-
-	for i, l := range sorted {
-		pct := 0.0
-		if total > 0 {
-			pct = math.Round(float64(l.Value)/float64(total)*1000) / 10
-		}
-
-		w := max(int(pct/100*float64(barWidth)), 1)
-		color := langColorHash(l.Name)
-
-		segments = append(segments, LanguageSegment{
-			X:     cursor,
-			Width: w,
-			Color: color,
-		})
-
+	for i, l := range shares {
 		col := i % 3
 		row := i / 3
 
@@ -95,22 +76,19 @@ func BuildView(info domain.BannerInfo) *BannerView {
 			DotY:  legendY + row*15,
 			TextX: pad + col*colW + 14,
 			TextY: legendY + row*15 + 4,
-			Color: color,
-			Label: fmt.Sprintf("%s %.1f%%", l.Name, pct),
+			Color: l.Color,
+			Label: fmt.Sprintf("%s %.1f%%", l.Name, l.Percent),
 		})
-
-		cursor += w
 	}
-	return &BannerView{
-		Width:         W,
-		Height:        H,
-		Username:      info.Username,
-		BannerType:    string(info.BannerType),
-		Stats:         info.Stats,
-		Theme:         theme,
-		BarWidth:      barWidth,
-		Languages:     segments,
-		Legend:        legend,
-		FormattedTime: info.Stats.FetchedAt.Format("02 Jan 2006 · 15:04"),
+
+	view := baseView(info, theme, domain.LayoutDefault, "banner.svg", W, H)
+	view.StatItems = []StatItem{
+		{X: 20, Y: 62, Width: 120, Height: 42, Label: "REPOS", Value: info.Stats.TotalRepos, Color: theme.Accent},
+		{X: 150, Y: 62, Width: 120, Height: 42, Label: "STARS", Value: info.Stats.TotalStars, Color: theme.AccentSecondary},
+		{X: 280, Y: 62, Width: 120, Height: 42, Label: "FORKS", Value: info.Stats.TotalForks, Color: forksColor},
 	}
+	view.BarWidth = barWidth
+	view.Languages = languageBar(shares, pad, barWidth)
+	view.Legend = legend
+	return view
 }
@@ -0,0 +1,40 @@
+package layout
+
+import "github.com/hurtki/github-banners/renderer/internal/domain"
+
+// buildCardView builds card with all the counters and without languages
+// organization's card shows public members instead of forked repositories
+func buildCardView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W      = 360
+		H      = 190
+		pad    = 20
+		cols   = 3
+		boxW   = 100
+		boxH   = 46
+		boxGap = 10
+		gridY  = 62
+	)
+
+	stats := []StatItem{
+		{Label: "REPOS", Value: info.Stats.TotalRepos, Color: theme.Accent},
+		{Label: "ORIGINAL", Value: info.Stats.OriginalRepos, Color: theme.Accent},
+		{Label: "FORKED", Value: info.Stats.ForkedRepos, Color: theme.Accent},
+		{Label: "STARS", Value: info.Stats.TotalStars, Color: theme.AccentSecondary},
+		{Label: "FORKS", Value: info.Stats.TotalForks, Color: forksColor},
+		{Label: "LANGUAGES", Value: len(info.Stats.Languages), Color: theme.AccentSecondary},
+	}
+	if info.Kind == domain.KindOrg {
+		stats[2] = StatItem{Label: "MEMBERS", Value: info.Stats.Members, Color: theme.Accent}
+	}
+	for i := range stats {
+		stats[i].X = pad + (i%cols)*(boxW+boxGap)
+		stats[i].Y = gridY + (i/cols)*(boxH+boxGap)
+		stats[i].Width = boxW
+		stats[i].Height = boxH
+	}
+
+	view := baseView(info, theme, domain.LayoutCard, "card.svg", W, H)
+	view.StatItems = stats
+	return view
+}
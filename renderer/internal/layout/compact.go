@@ -0,0 +1,24 @@
+package layout
+
+import "github.com/hurtki/github-banners/renderer/internal/domain"
+
+// buildCompactView builds small badge with username, main counters and languages bar without legend
+func buildCompactView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W        = 320
+		H        = 64
+		pad      = 14
+		barWidth = W - pad*2
+		maxLangs = 5
+	)
+
+	view := baseView(info, theme, domain.LayoutCompact, "compact.svg", W, H)
+	view.BarWidth = barWidth
+	view.Languages = languageBar(languageShares(info.Stats.Languages, maxLangs), pad, barWidth)
+	view.StatItems = []StatItem{
+		{X: 150, Y: 28, Label: "REPOS", Value: info.Stats.TotalRepos, Color: theme.Accent},
+		{X: 206, Y: 28, Label: "STARS", Value: info.Stats.TotalStars, Color: theme.AccentSecondary},
+		{X: 262, Y: 28, Label: "FORKS", Value: info.Stats.TotalForks, Color: forksColor},
+	}
+	return view
+}
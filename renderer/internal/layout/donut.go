@@ -0,0 +1,54 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+)
+
+// buildLanguagesView builds languages donut with legend and without other counters
+func buildLanguagesView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W        = 340
+		H        = 200
+		maxLangs = 6
+		radius   = 52
+		legendX  = 185
+		legendY  = 70
+		rowH     = 18
+	)
+
+	shares := languageShares(info.Stats.Languages, maxLangs)
+	circumference := 2 * math.Pi * radius
+
+	donut := make([]DonutSegment, 0, len(shares))
+	legend := make([]LegendItem, 0, len(shares))
+	offset := 0.0
+	for i, l := range shares {
+		length := l.Percent / 100 * circumference
+		donut = append(donut, DonutSegment{
+			Color:      l.Color,
+			DashArray:  fmt.Sprintf("%.2f %.2f", length, circumference-length),
+			DashOffset: fmt.Sprintf("%.2f", -offset),
+			Start:      offset / circumference,
+			Sweep:      l.Percent / 100,
+		})
+		offset += length
+
+		legend = append(legend, LegendItem{
+			DotX:  legendX,
+			DotY:  legendY + i*rowH,
+			TextX: legendX + 14,
+			TextY: legendY + i*rowH + 7,
+			Color: l.Color,
+			Label: fmt.Sprintf("%s %.1f%%", l.Name, l.Percent),
+		})
+	}
+
+	view := baseView(info, theme, domain.LayoutLanguages, "languages.svg", W, H)
+	view.Donut = donut
+	view.DonutRadius = radius
+	view.Legend = legend
+	return view
+}
@@ -0,0 +1,71 @@
+package layout
+
+import (
+	"math"
+	"sort"
+)
+
+// languageShare is one language with its part of all the user's code
+type languageShare struct {
+	Name    string
+	Value   int
+	Percent float64
+	Color   string
+}
+
+// languageShares sorts languages by their size and joins all the languages after maxLangs into "Other"
+// percents are rounded to one decimal place
+func languageShares(languages map[string]int, maxLangs int) []languageShare {
+	total := 0
+	for _, v := range languages {
+		total += v
+	}
+
+	sorted := make([]languageShare, 0, len(languages))
+	for k, v := range languages {
+		sorted = append(sorted, languageShare{Name: k, Value: v})
+	}
+
+	// languages with the same size are sorted by name, so view is the same for the same stats
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Value != sorted[j].Value {
+			return sorted[i].Value > sorted[j].Value
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	if len(sorted) > maxLangs {
+		other := 0
+		for _, l := range sorted[maxLangs:] {
+			other += l.Value
+		}
+		sorted = append(sorted[:maxLangs], languageShare{
+			Name:  "Other",
+			Value: other,
+		})
+	}
+
+	for i := range sorted {
+		if total > 0 {
+			sorted[i].Percent = math.Round(float64(sorted[i].Value)/float64(total)*1000) / 10
+		}
+		sorted[i].Color = langColorHash(sorted[i].Name)
+	}
+	return sorted
+}
+
+// languageBar splits bar of given width, that starts at x, into segments
+func languageBar(shares []languageShare, x int, width int) []LanguageSegment {
+	segments := make([]LanguageSegment, 0, len(shares))
+	cursor := x
+	for _, l := range shares {
+		w := max(int(l.Percent/100*float64(width)), 1)
+		segments = append(segments, LanguageSegment{
+			X:     cursor,
+			Width: w,
+			Color: l.Color,
+		})
+		cursor += w
+	}
+	return segments
+}
@@ -0,0 +1,81 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+)
+
+// buildRepositoryView builds card of single repository
+// license, latest release and last push are joined into subtitle, zeros and placeholders are shown without repository's details
+func buildRepositoryView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W        = 460
+		H        = 215
+		pad      = 20
+		barWidth = W - pad*2
+		maxLangs = 5
+		legendY  = 156
+		colW     = 145
+		boxW     = 134
+		boxGap   = 9
+		gridY    = 62
+	)
+
+	var r domain.GithubRepoStats
+	if info.Stats.Repository != nil {
+		r = *info.Stats.Repository
+	}
+
+	shares := languageShares(info.Stats.Languages, maxLangs)
+
+	legend := make([]LegendItem, 0, len(shares))
+	for i, l := range shares {
+		col := i % 3
+		row := i / 3
+
+		legend = append(legend, LegendItem{
+			DotX:  pad + col*colW + 4,
+			DotY:  legendY + row*15,
+			TextX: pad + col*colW + 14,
+			TextY: legendY + row*15 + 4,
+			Color: l.Color,
+			Label: fmt.Sprintf("%s %.1f%%", l.Name, l.Percent),
+		})
+	}
+
+	stats := []StatItem{
+		{Label: "STARS", Value: info.Stats.TotalStars, Color: theme.AccentSecondary},
+		{Label: "FORKS", Value: info.Stats.TotalForks, Color: forksColor},
+		{Label: "OPEN ISSUES", Value: r.OpenIssues, Color: theme.Accent},
+	}
+	for i := range stats {
+		stats[i].X = pad + i*(boxW+boxGap)
+		stats[i].Y = gridY
+		stats[i].Width = boxW
+		stats[i].Height = 46
+	}
+
+	view := baseView(info, theme, domain.LayoutRepository, "repository.svg", W, H)
+	view.Subtitle = repositorySubtitle(r)
+	view.StatItems = stats
+	view.BarWidth = barWidth
+	view.Languages = languageBar(shares, pad, barWidth)
+	view.Legend = legend
+	return view
+}
+
+func repositorySubtitle(r domain.GithubRepoStats) string {
+	parts := []string{"NO LICENSE", "NO RELEASES"}
+	if r.License != nil {
+		parts[0] = strings.ToUpper(*r.License)
+	}
+	if r.LatestRelease != nil {
+		parts[1] = *r.LatestRelease
+	}
+	if r.PushedAt != nil {
+		parts = append(parts, "PUSHED "+r.PushedAt.Format("02 Jan 2006"))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -3,10 +3,21 @@ package layout
 import "github.com/hurtki/github-banners/renderer/internal/domain"
 
 type Theme struct {
-	Background                 string
-	Foreground                 string
-	Muted                      string
-	BackgroundColorGradientOne string
+	Name            string
+	Background      string
+	Foreground      string
+	Muted           string
+	Accent          string
+	AccentSecondary string
+	FontFamily      string
+	// GradientStops are stops of background's linear gradient
+	GradientStops []GradientStop
+}
+
+type GradientStop struct {
+	// Offset in percents
+	Offset int
+	Color  string
 }
 
 type LanguageSegment struct {
@@ -24,15 +35,50 @@ type LegendItem struct {
 	Label string
 }
 
+// StatItem is one counter of the banner, X and Y are coordinates of its box
+type StatItem struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Label  string
+	Value  int
+	Color  string
+}
+
+// DonutSegment is an arc of languages donut, drawn with stroke dashes of the circle
+// Start and Sweep are the same arc in parts of the full circle, starting from the top clockwise
+type DonutSegment struct {
+	Color      string
+	DashArray  string
+	DashOffset string
+	Start      float64
+	Sweep      float64
+}
+
+// forksColor is a color of forks counter, that doesn't depend on theme
+const forksColor = "#ff00ff"
+
 type BannerView struct {
-	Width         int
-	Height        int
-	Username      string
-	BannerType    string
+	// Template is a name of the template, that view should be rendered with
+	Template string
+	Layout   string
+	// Animated is false for banners with motion turned off
+	// templates draw only static elements then
+	Animated   bool
+	Width      int
+	Height     int
+	Username   string
+	BannerType string
+	// Subtitle is shown under the title instead of banner type, blank means banner type
+	Subtitle      string
 	Stats         domain.GithubUserStats
 	Theme         Theme
 	BarWidth      int
 	Languages     []LanguageSegment
 	Legend        []LegendItem
+	StatItems     []StatItem
+	Donut         []DonutSegment
+	DonutRadius   int
 	FormattedTime string
 }
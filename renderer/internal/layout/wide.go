@@ -0,0 +1,58 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/hurtki/github-banners/renderer/internal/domain"
+)
+
+// buildWideView builds 800px header for profile README
+// counters are placed in one row and legend fits into one line
+func buildWideView(info domain.BannerInfo, theme Theme) *BannerView {
+	const (
+		W        = 800
+		H        = 160
+		pad      = 24
+		barWidth = W - pad*2
+		maxLangs = 5
+		legendY  = 132
+		colW     = barWidth / (maxLangs + 1)
+		boxW     = 110
+		boxGap   = 10
+	)
+
+	shares := languageShares(info.Stats.Languages, maxLangs)
+
+	legend := make([]LegendItem, 0, len(shares))
+	for i, l := range shares {
+		legend = append(legend, LegendItem{
+			DotX:  pad + i*colW + 4,
+			DotY:  legendY,
+			TextX: pad + i*colW + 14,
+			TextY: legendY + 4,
+			Color: l.Color,
+			Label: fmt.Sprintf("%s %.1f%%", l.Name, l.Percent),
+		})
+	}
+
+	stats := []StatItem{
+		{Label: "REPOS", Value: info.Stats.TotalRepos, Color: theme.Accent},
+		{Label: "ORIGINAL", Value: info.Stats.OriginalRepos, Color: theme.Accent},
+		{Label: "STARS", Value: info.Stats.TotalStars, Color: theme.AccentSecondary},
+		{Label: "FORKS", Value: info.Stats.TotalForks, Color: forksColor},
+	}
+	// boxes are aligned to the right edge, username takes the left part
+	for i := range stats {
+		stats[i].X = W - pad - (len(stats)-i)*(boxW+boxGap) + boxGap
+		stats[i].Y = 22
+		stats[i].Width = boxW
+		stats[i].Height = 48
+	}
+
+	view := baseView(info, theme, domain.LayoutWide, "wide.svg", W, H)
+	view.BarWidth = barWidth
+	view.Languages = languageBar(shares, pad, barWidth)
+	view.Legend = legend
+	view.StatItems = stats
+	return view
+}
@@ -9,13 +9,15 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/httpauth"
 	"github.com/hurtki/github-banners/renderer/internal/config"
+	"github.com/hurtki/github-banners/renderer/internal/domain/raster"
 	"github.com/hurtki/github-banners/renderer/internal/domain/render"
 	"github.com/hurtki/github-banners/renderer/internal/domain/templates"
+	"github.com/hurtki/github-banners/renderer/internal/domain/themes"
 	"github.com/hurtki/github-banners/renderer/internal/handlers/events"
 	http_handlers "github.com/hurtki/github-banners/renderer/internal/handlers/http"
 	"github.com/hurtki/github-banners/renderer/internal/infrastructure/clients/storage"
-	httpauth "github.com/hurtki/github-banners/renderer/internal/infrastructure/httpauth"
 	"github.com/hurtki/github-banners/renderer/internal/infrastructure/kafka"
 	kafka_cg_handlers "github.com/hurtki/github-banners/renderer/internal/infrastructure/kafka/cg_handlers"
 	"github.com/hurtki/github-banners/renderer/internal/logger"
@@ -27,9 +29,13 @@ func main() {
 	logger := logger.NewLogger(cfg.LogLevel, cfg.LogFormat)
 	logger.Info("started renderer service")
 
-	signer := httpauth.NewHMACSigner([]byte(cfg.ServiceSecret))
+	keyring, err := httpauth.LoadKeyring(cfg.ServiceSecretKeys, cfg.ServiceSecret)
+	if err != nil {
+		logger.Error("can't load services secret keys", "err", err)
+		os.Exit(1)
+	}
 
-	authTripper := httpauth.NewAuthHTTPRoundTripper("renderer-ms", signer, time.Now)
+	authTripper := httpauth.NewAuthHTTPRoundTripper("renderer-ms", keyring, time.Now, httpauth.SignatureVersion(cfg.SignatureVersion))
 	httpClient := &http.Client{
 		Transport: authTripper,
 		Timeout:   time.Second * 15,
@@ -43,14 +49,37 @@ func main() {
 		os.Exit(1)
 	}
 
-	renderUsecase := render.NewUsecase(renderer, storageClient)
+	rasterizer, err := raster.NewRasterizer()
+	if err != nil {
+		logger.Error("can't initialize png rasterizer", "err", err)
+		os.Exit(1)
+	}
+
+	themesRegistry, err := themes.NewRegistry(cfg.ThemesDir)
+	if err != nil {
+		logger.Error("can't load banner themes", "err", err)
+		os.Exit(1)
+	}
+	logger.Info("loaded banner themes", "count", len(themesRegistry.List()))
+
+	renderUsecase := render.NewUsecase(renderer, rasterizer, storageClient, themesRegistry)
 
 	bannerUpdateHandler := events.NewBannerUpdateHandler(logger, renderUsecase)
 
 	previewHandler := http_handlers.NewPreviewHandler(logger, renderUsecase)
+	themesHandler := http_handlers.NewThemesHandler(logger, themesRegistry)
+
+	verifier := httpauth.NewVerifier(keyring, httpauth.VerifierConfig{
+		AllowedServices: cfg.AllowedServices,
+		MaxClockSkew:    cfg.SignatureMaxClockSkew,
+		AcceptV1:        cfg.AcceptV1Signatures,
+		NonceCacheSize:  cfg.NonceCacheSize,
+		MaxBodySize:     cfg.SignedBodyMaxBytes,
+	}, time.Now, logger.With("service", "http-auth-verifier"))
 
 	router := chi.NewRouter()
-	router.Post("/preview", previewHandler.Preview)
+	router.With(verifier.Middleware).Post("/preview", previewHandler.Preview)
+	router.With(verifier.Middleware).Get("/themes", themesHandler.List)
 
 	httpServer := &http.Server{
 		Addr:    ":80",
@@ -54,6 +54,7 @@ run_service() {
 run_service "api"
 run_service "renderer"
 run_service "storage"
+run_service "httpauth"
 
 printf "\ncoverage summary\n"
 
@@ -3,6 +3,10 @@ module github.com/hurtki/github-banners/storage
 go 1.25.5
 
 require (
-	github.com/go-chi/chi/v5 v5.2.5 // indirect
-	golang.org/x/sync v0.19.0 // indirect
+	github.com/go-chi/chi/v5 v5.2.5
+	github.com/hurtki/github-banners/httpauth v0.0.0
 )
+
+require golang.org/x/sync v0.19.0 // indirect
+
+replace github.com/hurtki/github-banners/httpauth => ../httpauth
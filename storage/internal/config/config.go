@@ -2,15 +2,29 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	LogLevel  string
 	LogFormat string
 
-	ServiceSecret      string
+	ServiceSecret string
+	// list of named secrets "kid1:secret1,kid2:secret2:2026-12-31", has priority over ServiceSecret
+	ServiceSecretKeys  string
 	BannersStoragePath string
 	Port               string
+
+	// services, that are allowed to call storage's http endpoints
+	AllowedServices []string
+	// max difference between request's X-Timestamp and local time
+	SignatureMaxClockSkew time.Duration
+	// accept requests signed with v1 canonical ( without body digest and nonce )
+	AcceptV1Signatures bool
+	NonceCacheSize     int
+	SignedBodyMaxBytes int64
 }
 
 func Load() *Config {
@@ -19,8 +33,15 @@ func Load() *Config {
 		LogFormat: getEnv("LOG_FORMAT", "json"),
 
 		ServiceSecret:      getEnv("SERVICES_SECRET_KEY", "1234"),
+		ServiceSecretKeys:  getEnv("SERVICES_SECRET_KEYS", ""),
 		BannersStoragePath: getEnv("BANNERS_STORAGE_PATH", "/var/www/banners/"),
 		Port:               "80",
+
+		AllowedServices:       getEnvAsList("ALLOWED_SERVICES", "api,renderer-ms"),
+		SignatureMaxClockSkew: getEnvAsDuration("SIGNATURE_MAX_CLOCK_SKEW", 30*time.Second),
+		AcceptV1Signatures:    getEnvAsBool("ACCEPT_V1_SIGNATURES", true),
+		NonceCacheSize:        getEnvAsInt("NONCE_CACHE_SIZE", 100_000),
+		SignedBodyMaxBytes:    int64(getEnvAsInt("SIGNED_BODY_MAX_BYTES", 10<<20)),
 	}
 }
 
@@ -30,3 +51,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if dur, err := time.ParseDuration(value); err == nil {
+			return dur
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key string, defaultValue string) []string {
+	items := strings.Split(getEnv(key, defaultValue), ",")
+	res := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
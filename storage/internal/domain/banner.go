@@ -4,8 +4,16 @@ type BannerExtension uint8
 
 const (
 	SvgBannerExtension = iota
+	PngBannerExtension
 )
 
 var BannerExtensions = map[string]BannerExtension{
 	"svg": SvgBannerExtension,
+	"png": PngBannerExtension,
+}
+
+// BannerFileSuffixes are suffixes of banner's files for every extension
+var BannerFileSuffixes = map[BannerExtension]string{
+	SvgBannerExtension: ".svg",
+	PngBannerExtension: ".png",
 }
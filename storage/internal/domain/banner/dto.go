@@ -9,3 +9,7 @@ type SaveIn struct {
 type SaveOut struct {
 	BannerUrl string
 }
+
+type DeleteIn struct {
+	UrlPath string
+}
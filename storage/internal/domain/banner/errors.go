@@ -8,4 +8,5 @@ var (
 	ErrInvalidUrlPath      = errors.New("invalid url path")
 	ErrInvalidBannerFormat = errors.New("invalid banner format")
 	ErrCantSaveBanner      = errors.New("cant save banner")
+	ErrCantDeleteBanner    = errors.New("cant delete banner")
 )
@@ -12,6 +12,7 @@ import (
 
 type BannerStorage interface {
 	Save(ctx context.Context, name string, extension domain.BannerExtension, content []byte) error
+	Delete(ctx context.Context, name string, extension domain.BannerExtension) error
 }
 
 type BannerUsecase struct {
@@ -25,7 +26,7 @@ func NewBannerUsecase(storage BannerStorage) *BannerUsecase {
 }
 
 func (u *BannerUsecase) Save(ctx context.Context, in SaveIn) (SaveOut, error) {
-	if in.UrlPath == "" || (url.PathEscape(in.UrlPath) != in.UrlPath) {
+	if !validUrlPath(in.UrlPath) {
 		return SaveOut{}, ErrInvalidUrlPath
 	}
 	ext, ok := domain.BannerExtensions[in.Format]
@@ -44,5 +45,34 @@ func (u *BannerUsecase) Save(ctx context.Context, in SaveIn) (SaveOut, error) {
 	}
 
 	// returning relative path
-	return SaveOut{BannerUrl: path.Join("/banners/", in.UrlPath)}, nil
+	// svg is served without suffix, other formats are served with their file suffix
+	bannerUrl := path.Join("/banners/", in.UrlPath)
+	if ext != domain.SvgBannerExtension {
+		bannerUrl += domain.BannerFileSuffixes[ext]
+	}
+	return SaveOut{BannerUrl: bannerUrl}, nil
+}
+
+// Delete removes banner's images of all the extensions
+// is idempotent, not existing banner is not an error
+func (u *BannerUsecase) Delete(ctx context.Context, in DeleteIn) error {
+	if !validUrlPath(in.UrlPath) {
+		return ErrInvalidUrlPath
+	}
+	for _, ext := range domain.BannerExtensions {
+		err := u.storage.Delete(ctx, in.UrlPath, ext)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrUnavailable):
+				return ErrCantDeleteBanner
+			default:
+				return fmt.Errorf("%s:%w:%w", "unhandled error from storage", err, ErrCantDeleteBanner)
+			}
+		}
+	}
+	return nil
+}
+
+func validUrlPath(urlPath string) bool {
+	return urlPath != "" && url.PathEscape(urlPath) == urlPath
 }
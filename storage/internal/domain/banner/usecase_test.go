@@ -0,0 +1,84 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hurtki/github-banners/storage/internal/domain"
+)
+
+type deletedFile struct {
+	name      string
+	extension domain.BannerExtension
+}
+
+type storageFake struct {
+	deleted []deletedFile
+	err     error
+}
+
+func (s *storageFake) Save(ctx context.Context, name string, extension domain.BannerExtension, content []byte) error {
+	return s.err
+}
+
+func (s *storageFake) Delete(ctx context.Context, name string, extension domain.BannerExtension) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.deleted = append(s.deleted, deletedFile{name, extension})
+	return nil
+}
+
+func TestDeleteRemovesAllExtensions(t *testing.T) {
+	storage := &storageFake{}
+	u := NewBannerUsecase(storage)
+
+	if err := u.Delete(context.Background(), DeleteIn{UrlPath: "hurtki-dark"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(storage.deleted) != len(domain.BannerExtensions) {
+		t.Fatalf("expected %d deleted files, got %d", len(domain.BannerExtensions), len(storage.deleted))
+	}
+	seen := map[domain.BannerExtension]bool{}
+	for _, f := range storage.deleted {
+		if f.name != "hurtki-dark" {
+			t.Fatalf("expected hurtki-dark to be deleted, got %s", f.name)
+		}
+		seen[f.extension] = true
+	}
+	for _, ext := range domain.BannerExtensions {
+		if !seen[ext] {
+			t.Fatalf("expected file with extension %d to be deleted", ext)
+		}
+	}
+}
+
+func TestDeleteErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		urlPath    string
+		storageErr error
+		err        error
+	}{
+		{name: "blank url path", urlPath: "", err: ErrInvalidUrlPath},
+		{name: "url path with slash", urlPath: "../hurtki", err: ErrInvalidUrlPath},
+		{name: "storage is unavailable", urlPath: "hurtki-dark", storageErr: domain.ErrUnavailable, err: ErrCantDeleteBanner},
+		{name: "unexpected storage error", urlPath: "hurtki-dark", storageErr: errors.New("disk is on fire"), err: ErrCantDeleteBanner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &storageFake{err: tt.storageErr}
+			u := NewBannerUsecase(storage)
+
+			err := u.Delete(context.Background(), DeleteIn{UrlPath: tt.urlPath})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if len(storage.deleted) != 0 {
+				t.Fatalf("expected nothing to be deleted, got %v", storage.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/storage/internal/domain/banner"
+)
+
+// Delete removes all the images of the banner, responds 204 even if there was nothing to remove
+func (h *BannersHandler) Delete(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.Delete"
+	err := h.usecase.Delete(req.Context(), banner.DeleteIn{UrlPath: chi.URLParam(req, "url_path")})
+	if err != nil {
+		switch {
+		case errors.Is(err, banner.ErrInvalidUrlPath):
+			h.error(rw, http.StatusBadRequest, "invalid url path")
+		case errors.Is(err, banner.ErrCantDeleteBanner):
+			h.logger.Warn("can't delete banner", "err", err, "source", fn)
+			h.error(rw, http.StatusInternalServerError, "can't delete banner")
+		default:
+			h.logger.Warn("unhandled error from usecase", "source", fn, "err", err)
+			h.error(rw, http.StatusInternalServerError, "can't delete banner")
+		}
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/storage/internal/domain/banner"
+	"github.com/hurtki/github-banners/storage/internal/logger"
+)
+
+type usecaseFake struct {
+	deleted []banner.DeleteIn
+	err     error
+}
+
+func (u *usecaseFake) Save(ctx context.Context, in banner.SaveIn) (banner.SaveOut, error) {
+	return banner.SaveOut{}, u.err
+}
+
+func (u *usecaseFake) Delete(ctx context.Context, in banner.DeleteIn) error {
+	u.deleted = append(u.deleted, in)
+	return u.err
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "deleted", status: http.StatusNoContent},
+		{name: "invalid url path", err: banner.ErrInvalidUrlPath, status: http.StatusBadRequest},
+		{name: "can't delete", err: banner.ErrCantDeleteBanner, status: http.StatusInternalServerError},
+		{name: "unhandled error", err: errors.New("unexpected"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &usecaseFake{err: tt.err}
+			h := NewBannersHandler(logger.NewLogger("error", "json"), usecase)
+			router := chi.NewRouter()
+			router.Delete("/banners/{url_path}", h.Delete)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/banners/hurtki-dark", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if len(usecase.deleted) != 1 || usecase.deleted[0].UrlPath != "hurtki-dark" {
+				t.Fatalf("expected hurtki-dark to be deleted, got %v", usecase.deleted)
+			}
+			if tt.status != http.StatusNoContent && rec.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("expected json error, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -10,24 +10,25 @@ import (
 	"github.com/hurtki/github-banners/storage/internal/logger"
 )
 
-type BannerSaveUsecase interface {
+type BannerUsecase interface {
 	Save(ctx context.Context, in banner.SaveIn) (banner.SaveOut, error)
+	Delete(ctx context.Context, in banner.DeleteIn) error
 }
 
-type BannerSaveHandler struct {
+type BannersHandler struct {
 	logger  logger.Logger
-	usecase BannerSaveUsecase
+	usecase BannerUsecase
 }
 
-func NewBannerSaveHandler(logger logger.Logger, usecase BannerSaveUsecase) *BannerSaveHandler {
-	return &BannerSaveHandler{
-		logger:  logger.With("service", "banner-save-handler"),
+func NewBannersHandler(logger logger.Logger, usecase BannerUsecase) *BannersHandler {
+	return &BannersHandler{
+		logger:  logger.With("service", "banners-handler"),
 		usecase: usecase,
 	}
 }
 
-func (h *BannerSaveHandler) Save(rw http.ResponseWriter, req *http.Request) {
-	fn := "internal.handlers.BannerSaveHandler.Save"
+func (h *BannersHandler) Save(rw http.ResponseWriter, req *http.Request) {
+	fn := "internal.handlers.BannersHandler.Save"
 	reqDto := SaveRequest{}
 	err := json.NewDecoder(req.Body).Decode(&reqDto)
 	if err != nil {
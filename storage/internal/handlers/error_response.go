@@ -7,7 +7,7 @@ import (
 
 // errror is used to write error in json
 // if error, when marshaling appears, handles and logs it
-func (h *BannerSaveHandler) error(rw http.ResponseWriter, statusCode int, message string) {
+func (h *BannersHandler) error(rw http.ResponseWriter, statusCode int, message string) {
 	fn := "internal.handlers.BannersHandler.error"
 	rw.Header().Set("Content-Type", "application/json")
 	res, err := json.Marshal(map[string]string{"error": message})
@@ -2,7 +2,9 @@ package bannersstorage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -11,47 +13,74 @@ import (
 )
 
 type WriteFileFunc func(string, []byte, os.FileMode) error
+type RemoveFileFunc func(string) error
 
 type FileStorage struct {
-	basePath      string
-	logger        logger.Logger
-	writeFileFunc WriteFileFunc
+	basePath       string
+	logger         logger.Logger
+	writeFileFunc  WriteFileFunc
+	removeFileFunc RemoveFileFunc
 
 	kmu *keyedMutex
 }
 
-func NewFileStorage(basePath string, logger logger.Logger, wWriteFileFunc WriteFileFunc) *FileStorage {
+func NewFileStorage(basePath string, logger logger.Logger, wWriteFileFunc WriteFileFunc, removeFileFunc RemoveFileFunc) *FileStorage {
 	return &FileStorage{
-		basePath:      basePath,
-		logger:        logger.With("service", "banners-file-storage"),
-		writeFileFunc: wWriteFileFunc,
-		kmu:           newKeyedMutex(),
+		basePath:       basePath,
+		logger:         logger.With("service", "banners-file-storage"),
+		writeFileFunc:  wWriteFileFunc,
+		removeFileFunc: removeFileFunc,
+		kmu:            newKeyedMutex(),
 	}
 }
 
 // Save is idempotent, saves banner's content with given extenstion
 // returns nil or domain.ErrUnavailable
 func (s *FileStorage) Save(ctx context.Context, name string, extension domain.BannerExtension, content []byte) error {
-	switch extension {
-	case domain.SvgBannerExtension:
-		path := filepath.Join(s.basePath, name+".svg")
-		muKey := fmt.Sprintf("%s%d", name, uint8(extension))
-
-		// usage of keyed mutex
-		// in order to block on same file write operations
-		// and don't block on different file write operations
-		s.kmu.Lock(muKey)
-		defer s.kmu.Unlock(muKey)
-
-		err := s.writeFileFunc(path, content, 0644)
-
-		if err != nil {
-			s.logger.Error("error from os", "err", err, "path", path)
-			return domain.ErrUnavailable
-		}
-		return nil
-	default:
+	suffix, ok := domain.BannerFileSuffixes[extension]
+	if !ok {
 		s.logger.Warn("unexpected extension", "extension", extension)
 		return domain.ErrUnavailable
 	}
+
+	path := filepath.Join(s.basePath, name+suffix)
+	muKey := fmt.Sprintf("%s%d", name, uint8(extension))
+
+	// usage of keyed mutex
+	// in order to block on same file write operations
+	// and don't block on different file write operations
+	s.kmu.Lock(muKey)
+	defer s.kmu.Unlock(muKey)
+
+	err := s.writeFileFunc(path, content, 0644)
+
+	if err != nil {
+		s.logger.Error("error from os", "err", err, "path", path)
+		return domain.ErrUnavailable
+	}
+	return nil
+}
+
+// Delete is idempotent, removes banner's file with given extension
+// returns nil or domain.ErrUnavailable
+func (s *FileStorage) Delete(ctx context.Context, name string, extension domain.BannerExtension) error {
+	suffix, ok := domain.BannerFileSuffixes[extension]
+	if !ok {
+		s.logger.Warn("unexpected extension", "extension", extension)
+		return domain.ErrUnavailable
+	}
+
+	path := filepath.Join(s.basePath, name+suffix)
+	muKey := fmt.Sprintf("%s%d", name, uint8(extension))
+
+	s.kmu.Lock(muKey)
+	defer s.kmu.Unlock(muKey)
+
+	err := s.removeFileFunc(path)
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Error("error from os", "err", err, "path", path)
+		return domain.ErrUnavailable
+	}
+	return nil
 }
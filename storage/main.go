@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/hurtki/github-banners/httpauth"
 	"github.com/hurtki/github-banners/storage/internal/config"
 	"github.com/hurtki/github-banners/storage/internal/domain/banner"
 	"github.com/hurtki/github-banners/storage/internal/handlers"
@@ -21,12 +22,33 @@ func main() {
 	logger := logger.NewLogger(config.LogLevel, config.LogFormat)
 	logger.Info("started storage service")
 
-	bannersStorage := bannersstorage.NewFileStorage(config.BannersStoragePath, logger, os.WriteFile)
+	bannersStorage := bannersstorage.NewFileStorage(config.BannersStoragePath, logger, os.WriteFile, os.Remove)
 	usecase := banner.NewBannerUsecase(bannersStorage)
-	handler := handlers.NewBannerSaveHandler(logger, usecase)
+	handler := handlers.NewBannersHandler(logger, usecase)
+
+	keyring, err := httpauth.LoadKeyring(config.ServiceSecretKeys, config.ServiceSecret)
+	if err != nil {
+		logger.Error("can't load services secret keys", "err", err)
+		os.Exit(1)
+	}
+
+	verifier := httpauth.NewVerifier(
+		keyring,
+		httpauth.VerifierConfig{
+			AllowedServices: config.AllowedServices,
+			MaxClockSkew:    config.SignatureMaxClockSkew,
+			AcceptV1:        config.AcceptV1Signatures,
+			NonceCacheSize:  config.NonceCacheSize,
+			MaxBodySize:     config.SignedBodyMaxBytes,
+		},
+		time.Now,
+		logger.With("service", "http-auth-verifier"),
+	)
 
 	router := chi.NewRouter()
+	router.Use(verifier.Middleware)
 	router.Post("/banners", handler.Save)
+	router.Delete("/banners/{url_path}", handler.Delete)
 	srv := server.New(config, router, logger)
 	srv.Start()
 